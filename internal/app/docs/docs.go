@@ -62,11 +62,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -123,11 +147,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             },
@@ -182,11 +230,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -260,11 +332,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -314,11 +410,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -375,11 +495,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             },
@@ -434,11 +578,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -512,11 +680,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -583,11 +775,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -647,18 +863,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
         "/api/v1/:cluster/lustre/quota/application": {
             "post": {
-                "description": "参数 cluster 为保留参数, 当前整体设计就不支持多集群.",
+                "description": "申请属于路径中的集群, 仅该集群的审核人可审核.",
                 "produces": [
                     "application/json"
                 ],
@@ -711,6 +951,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -781,6 +1027,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -840,6 +1098,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -902,6 +1172,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -913,7 +1195,7 @@ const docTemplate = `{
         },
         "/api/v1/:cluster/lustre/quota/application/{id}/review": {
             "post": {
-                "description": "根据审核结果执行实际配额配置（通过时）并更新申请的审核状态与内容",
+                "description": "根据审核结果执行实际配额配置（通过时）并更新申请的审核状态与内容; 申请不属于路径中的集群时返回 404",
                 "produces": [
                     "application/json"
                 ],
@@ -973,18 +1255,42 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
         "/api/v1/:cluster/lustre/quota/applications": {
             "get": {
-                "description": "获取配额申请列表，支持按申请人筛选与分页；Actual 字段依据 KD 查询当前实际配额并以默认值兜底",
+                "description": "获取路径中集群的配额申请列表，支持按申请人筛选与分页；Actual 字段依据 KD 查询当前实际配额并以默认值兜底",
                 "produces": [
                     "application/json"
                 ],
@@ -1058,11 +1364,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -1147,11 +1471,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -1168,6 +1516,13 @@ const docTemplate = `{
                 ],
                 "summary": "获取实时报警信息.",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "enum": [
                             "inband",
@@ -1234,11 +1589,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -1375,12 +1748,17 @@ const docTemplate = `{
                 ],
                 "summary": "查询带外传感器阈值",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU",
+                        "name": "cluster",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "RMU IP",
                         "name": "rmu_ip",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
@@ -1403,11 +1781,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -1427,6 +1823,12 @@ const docTemplate = `{
                 ],
                 "summary": "设置带外传感器抑制规则",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU",
+                        "name": "cluster",
+                        "in": "query"
+                    },
                     {
                         "description": "设置参数",
                         "name": "data",
@@ -1462,11 +1864,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -1486,6 +1906,12 @@ const docTemplate = `{
                 ],
                 "summary": "设置带外传感器下限阈值",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU",
+                        "name": "cluster",
+                        "in": "query"
+                    },
                     {
                         "description": "设置参数",
                         "name": "data",
@@ -1521,11 +1947,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -1545,6 +1989,12 @@ const docTemplate = `{
                 ],
                 "summary": "设置带外传感器某一阈值",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU",
+                        "name": "cluster",
+                        "in": "query"
+                    },
                     {
                         "description": "设置参数",
                         "name": "data",
@@ -1580,11 +2030,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
@@ -1604,6 +2072,12 @@ const docTemplate = `{
                 ],
                 "summary": "设置带外传感器上限阈值",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU",
+                        "name": "cluster",
+                        "in": "query"
+                    },
                     {
                         "description": "设置参数",
                         "name": "data",
@@ -1639,33 +2113,91 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/{cluster}/ldap/group": {
-            "post": {
+        "/api/v1/audit": {
+            "get": {
+                "description": "获取修改类(POST/PUT/PATCH/DELETE)接口的调用记录, 请求体中的密码等敏感字段已脱敏.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "审计"
                 ],
-                "summary": "向某集群中 ldap 用户组添加新用户组",
+                "summary": "获取审计日志",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
+                        "description": "开始时间, 需符合 RFC3339 时间格式, 缺省不限制",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间, 需符合 RFC3339 时间格式, 缺省不限制",
+                        "name": "end",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "调用者",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "路由模板前缀, 如 /api/v1/:cluster/ldap",
+                        "name": "route",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量, 最大仅支持100",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1680,7 +2212,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "string"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AuditLog"
+                                            }
                                         }
                                     }
                                 }
@@ -1693,6 +2228,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1700,24 +2241,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/auth/login": {
+            "post": {
+                "description": "使用集群 Ldap 校验用户名密码, 成功后返回访问令牌及刷新令牌. 访问其他接口时需携带 Authorization: Bearer \u003caccess_token\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "认证"
                 ],
-                "summary": "删除某集群中 ldap 用户组",
+                "summary": "登录",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
+                        "description": "登录参数",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginParam"
+                        }
                     }
                 ],
                 "responses": {
@@ -1732,7 +2279,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/auth.TokenPair"
                                         }
                                     }
                                 }
@@ -1745,35 +2292,48 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/{cluster}/ldap/groups": {
+        "/api/v1/auth/me": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
-                ],
-                "summary": "获取某集群中 ldap 用户组列表",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
-                    }
+                    "认证"
                 ],
+                "summary": "当前用户",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -1786,24 +2346,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/slurm.LdapGroup"
-                                            }
+                                            "$ref": "#/definitions/auth.Identity"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -1811,24 +2362,28 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/ldap/user": {
-            "put": {
+        "/api/v1/auth/refresh": {
+            "post": {
+                "description": "重新查询用户在登录集群 Ldap 中的附加组, 新令牌使用最新的附加组; 用户已不存在时返回 401.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "认证"
                 ],
-                "summary": "在某集群 ldap 中更新用户信息",
+                "summary": "刷新令牌",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
+                        "description": "刷新令牌",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshParam"
+                        }
                     }
                 ],
                 "responses": {
@@ -1843,7 +2398,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/auth.TokenPair"
                                         }
                                     }
                                 }
@@ -1856,35 +2411,70 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/clusters": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "集群管理"
                 ],
-                "summary": "在某集群 ldap 中创建用户",
+                "summary": "获取集群列表",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量, 最大仅支持100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
                         "description": "OK",
                         "schema": {
                             "allOf": [
@@ -1895,7 +2485,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "string"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Cluster"
+                                            }
                                         }
                                     }
                                 }
@@ -1916,28 +2509,31 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "集群管理"
                 ],
-                "summary": "在某集群 ldap 中删除某用户",
+                "summary": "注册集群",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
+                        "description": "集群配置",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cluster.ClusterParam"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "allOf": [
                                 {
@@ -1960,6 +2556,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1969,22 +2577,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/ldap/users": {
+        "/api/v1/clusters/{name}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "集群管理"
                 ],
-                "summary": "获取某集群中 LDAP 所有用户",
+                "summary": "获取集群详情",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"test\"",
                         "description": "集群名称",
-                        "name": "cluster",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -2001,18 +2607,15 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/slurm.LdapUser"
-                                            }
+                                            "$ref": "#/definitions/model.Cluster"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2024,27 +2627,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/{cluster}/slurm/account/name/list": {
-            "get": {
-                "description": "返回账户名称字符串数组，无分页",
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "集群管理"
                 ],
-                "summary": "获取某集群账户名称列表",
+                "summary": "更新集群",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"test\"",
                         "description": "集群名称",
-                        "name": "cluster",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "集群配置",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cluster.ClusterParam"
+                        }
                     }
                 ],
                 "responses": {
@@ -2059,10 +2669,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "type": "string"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2075,6 +2682,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2082,41 +2701,20 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/{cluster}/slurm/account/{account}/childnodes": {
-            "get": {
-                "description": "返回账户节点的子账号名称列表及子用户节点信息",
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "集群管理"
                 ],
-                "summary": "获取某集群中指定账户的子节点信息",
+                "summary": "删除集群",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"test\"",
-                        "description": "账户节点名称",
-                        "name": "account",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"test\"",
                         "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"root\"",
-                        "description": "账户节点名称",
-                        "name": "account",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -2133,15 +2731,21 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "$ref": "#/definitions/slurmrest.AccountNode"
+                                            "type": "string"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2155,22 +2759,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/accounting/accounts/name": {
+        "/api/v1/clusters/{name}/roles": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "用户管理"
+                    "集群管理"
                 ],
-                "summary": "获取某集群中 slurm 账户名列表",
+                "summary": "获取集群角色映射",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"test\"",
                         "description": "集群名称",
-                        "name": "cluster",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -2189,7 +2791,7 @@ const docTemplate = `{
                                         "results": {
                                             "type": "array",
                                             "items": {
-                                                "type": "string"
+                                                "$ref": "#/definitions/model.ClusterRole"
                                             }
                                         }
                                     }
@@ -2197,8 +2799,14 @@ const docTemplate = `{
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2210,50 +2818,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/{cluster}/slurm/accounting/job/list": {
-            "get": {
-                "description": "返回账户历史作业列表，可选分页参数",
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "作业管理"
+                    "集群管理"
                 ],
-                "summary": "获取某集群账户中作业列表",
+                "summary": "设置集群角色映射",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"test\"",
                         "description": "集群名称",
-                        "name": "cluster",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "default": true,
-                        "description": "是否开启分页, 当前仅支持true",
-                        "name": "paging",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页号(从1开始)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 20,
-                        "description": "每页数量",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "角色映射",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cluster.ClusterRolesParam"
+                        }
                     }
                 ],
                 "responses": {
@@ -2268,10 +2860,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/slurm.JobListItem"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2284,6 +2873,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2293,34 +2894,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/accounting/job/{jobid}/detail": {
+        "/api/v1/upstreams": {
             "get": {
-                "description": "返回作业基础元信息与所有作业步列表",
+                "description": "获取 slurmrestd, KD Lustre, Alertmanager 各地址的熔断器状态. 仅包含服务启动后访问过的地址;\nstate 为 open 时发往该地址的请求直接返回 UPSTREAM_UNAVAILABLE, 冷却结束后转为 half_open 并放行一个探测请求.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "资源管理",
-                    "作业管理"
-                ],
-                "summary": "获取某集群账户中某作业详情",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "作业号",
-                        "name": "jobid",
-                        "in": "path",
-                        "required": true
-                    }
+                    "上游服务"
                 ],
+                "summary": "获取上游服务熔断状态",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -2333,21 +2916,18 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "$ref": "#/definitions/slurm.DetailOfJobFromAccounting"
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/transport.BreakerStatus"
+                                            }
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -2355,9 +2935,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/association/detail": {
-            "get": {
-                "description": "根据 account、可选 user、可选 partition 查询关联详情",
+        "/api/v1/{cluster}/ldap/group": {
+            "post": {
                 "produces": [
                     "application/json"
                 ],
@@ -2365,7 +2944,7 @@ const docTemplate = `{
                     "资源管理",
                     "用户管理"
                 ],
-                "summary": "获取某集群中某个关联详情",
+                "summary": "向某集群中 ldap 用户组添加新用户组",
                 "parameters": [
                     {
                         "type": "string",
@@ -2374,30 +2953,6 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"root\"",
-                        "description": "账户名",
-                        "name": "account",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "default": "\"\"",
-                        "example": "\"alice\"",
-                        "description": "用户名",
-                        "name": "user",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "default": "\"\"",
-                        "example": "\"p1\"",
-                        "description": "分区名",
-                        "name": "partition",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2412,7 +2967,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "$ref": "#/definitions/slurm.AssociationDetail"
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2425,6 +2980,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2432,11 +2993,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/{cluster}/slurm/association/{account}/childnodes": {
-            "get": {
-                "description": "返回账户关联树中该账户的子账户与子用户节点信息",
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
@@ -2444,7 +3002,7 @@ const docTemplate = `{
                     "资源管理",
                     "用户管理"
                 ],
-                "summary": "获取某集群中指定账户的关联子节点信息",
+                "summary": "删除某集群中 ldap 用户组",
                 "parameters": [
                     {
                         "type": "string",
@@ -2453,14 +3011,6 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"root\"",
-                        "description": "账户名称",
-                        "name": "account",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -2475,7 +3025,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "$ref": "#/definitions/slurmrest.AssociationNode"
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2488,6 +3038,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2497,17 +3053,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/nodes": {
+        "/api/v1/{cluster}/ldap/groups": {
             "get": {
-                "description": "按分区查询节点列表，支持分页。",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "资源总览"
+                    "用户管理"
                 ],
-                "summary": "获取某集群分区节点列表",
+                "summary": "获取某集群中 ldap 用户组列表",
                 "parameters": [
                     {
                         "type": "string",
@@ -2516,44 +3071,28 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "分区名称",
-                        "name": "partition",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "default": true,
-                        "description": "是否开启分页, 默认为 true",
-                        "name": "paging",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码，从1开始",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 10,
-                        "description": "每页数量，最大100",
-                        "name": "page_size",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/slurm.LdapGroup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -2562,6 +3101,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2571,17 +3116,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/overview": {
-            "get": {
-                "description": "获取资源总览页面资源统计信息, 包括集群节点总数, 逻辑CPU总数, 总核数, 内存总量, 运行作业数, 总作业数.",
+        "/api/v1/{cluster}/ldap/user": {
+            "put": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "资源总览"
+                    "用户管理"
                 ],
-                "summary": "获取资源总览页面资源统计信息",
+                "summary": "在某集群 ldap 中更新用户信息",
                 "parameters": [
                     {
                         "type": "string",
@@ -2604,7 +3148,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "$ref": "#/definitions/slurm.Overview"
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2617,6 +3161,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2624,18 +3174,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/{cluster}/slurm/partition/list": {
-            "get": {
+            },
+            "post": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "分区管理"
+                    "用户管理"
                 ],
-                "summary": "获取某集群所有分区概述",
+                "summary": "在某集群 ldap 中创建用户",
                 "parameters": [
                     {
                         "type": "string",
@@ -2644,30 +3192,6 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "default": true,
-                        "description": "是否开启分页",
-                        "name": "paging",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页号(从1开始)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 20,
-                        "description": "每页数量",
-                        "name": "page_size",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2682,10 +3206,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/slurm.PartitionListItem"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2698,6 +3219,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2705,18 +3232,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/{cluster}/slurm/partition/{name}/detail": {
-            "get": {
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "分区管理"
+                    "用户管理"
                 ],
-                "summary": "获取某集群中某个分区的详细内容",
+                "summary": "在某集群 ldap 中删除某用户",
                 "parameters": [
                     {
                         "type": "string",
@@ -2725,14 +3250,6 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "example": "\"p1\"",
-                        "description": "分区名称",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -2747,7 +3264,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "$ref": "#/definitions/slurm.PartitionDetail"
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2760,6 +3277,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2769,16 +3292,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/qos/list": {
+        "/api/v1/{cluster}/ldap/users": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "QoS"
+                    "用户管理"
                 ],
-                "summary": "获取某集群中 QoS 列表",
+                "summary": "获取某集群中 LDAP 所有用户",
                 "parameters": [
                     {
                         "type": "string",
@@ -2787,30 +3310,6 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "default": false,
-                        "description": "是否开启分页",
-                        "name": "paging",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页码，从 1 开始（仅当 paging=true 生效）",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "maximum": 100,
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 20,
-                        "description": "每页数量，1-100（仅当 paging=true 生效）",
-                        "name": "page_size",
-                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -2827,7 +3326,7 @@ const docTemplate = `{
                                         "results": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/slurm.QoSListElem"
+                                                "$ref": "#/definitions/slurm.LdapUser"
                                             }
                                         }
                                     }
@@ -2841,6 +3340,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2850,8 +3355,12 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/qos/name/list": {
-            "get": {
+        "/api/v1/{cluster}/slurm/account": {
+            "post": {
+                "description": "对应 sacctmgr add account \u003cname\u003e parent=\u003cparent\u003e organization=\u003corganization\u003e description=\u003cdescription\u003e, parent 为空时为 root.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
@@ -2859,7 +3368,7 @@ const docTemplate = `{
                     "资源管理",
                     "用户管理"
                 ],
-                "summary": "获取某集群中所有 QoS 名称列表",
+                "summary": "创建账户",
                 "parameters": [
                     {
                         "type": "string",
@@ -2868,6 +3377,15 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "账户信息",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountWrite"
+                        }
                     }
                 ],
                 "responses": {
@@ -2882,10 +3400,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "type": "string"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -2898,25 +3413,56 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/qos/{id}/detail": {
+        "/api/v1/{cluster}/slurm/account/name/list": {
             "get": {
+                "description": "返回账户名称字符串数组，无分页",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "QoS"
+                    "用户管理"
                 ],
-                "summary": "获取某集群中某个 QoS 详情",
+                "summary": "获取某集群账户名称列表",
                 "parameters": [
                     {
                         "type": "string",
@@ -2925,13 +3471,6 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "QoS ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -2946,7 +3485,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "$ref": "#/definitions/slurm.QoSDetail"
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
                                         }
                                     }
                                 }
@@ -2959,74 +3501,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/{cluster}/slurm/reservation/application": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "资源管理",
-                    "资源预约"
-                ],
-                "summary": "创建资源预约申请",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
                     },
-                    {
-                        "description": "申请内容",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/slurm.ApplicationContent"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "results": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "502": {
+                        "description": "Bad Gateway",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "504": {
+                        "description": "Gateway Timeout",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3034,19 +3528,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/reservation/application/{id}": {
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/{cluster}/slurm/account/{account}": {
+            "delete": {
+                "description": "对应 sacctmgr delete account. 账户下仍有子账户, 用户关联或未结束的作业时拒绝删除(409).",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "资源预约"
+                    "用户管理"
                 ],
-                "summary": "更新资源预约申请",
+                "summary": "删除账户",
                 "parameters": [
                     {
                         "type": "string",
@@ -3057,20 +3549,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "申请ID",
-                        "name": "id",
+                        "type": "string",
+                        "example": "\"physics\"",
+                        "description": "账户名称",
+                        "name": "account",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "申请内容",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/slurm.ApplicationContent"
-                        }
                     }
                 ],
                 "responses": {
@@ -3098,61 +3582,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "资源管理",
-                    "资源预约"
-                ],
-                "summary": "删除资源预约申请",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "example": "\"test\"",
-                        "description": "集群名称",
-                        "name": "cluster",
-                        "in": "path",
-                        "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "申请ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "allOf": [
-                                {
-                                    "$ref": "#/definitions/response.Response"
-                                },
-                                {
-                                    "type": "object",
-                                    "properties": {
-                                        "results": {
-                                            "type": "string"
-                                        }
-                                    }
-                                }
-                            ]
+                            "$ref": "#/definitions/response.Response"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -3162,21 +3605,42 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/reservation/application/{id}/decision": {
+        "/api/v1/{cluster}/slurm/account/{account}/childnodes": {
             "get": {
+                "description": "返回账户节点的子账号名称列表及子用户节点信息",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "资源预约"
+                    "用户管理"
                 ],
-                "summary": "获取资源预约申请的审核结果",
+                "summary": "获取某集群中指定账户的子节点信息",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "账户节点名称",
+                        "name": "account",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "example": "\"test\"",
@@ -3186,9 +3650,10 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "申请ID",
-                        "name": "id",
+                        "type": "string",
+                        "example": "\"root\"",
+                        "description": "账户节点名称",
+                        "name": "account",
                         "in": "path",
                         "required": true
                     }
@@ -3205,7 +3670,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "string"
+                                            "$ref": "#/definitions/slurmrest.AccountNode"
                                         }
                                     }
                                 }
@@ -3218,28 +3683,43 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/reservation/application/{id}/review": {
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/v1/{cluster}/slurm/accounting/accounts/name": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "资源预约"
+                    "用户管理"
                 ],
-                "summary": "审核资源预约申请",
+                "summary": "获取某集群中 slurm 账户名列表",
                 "parameters": [
                     {
                         "type": "string",
@@ -3248,22 +3728,6 @@ const docTemplate = `{
                         "name": "cluster",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "申请ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "审核结论",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/slurm.Review"
-                        }
                     }
                 ],
                 "responses": {
@@ -3278,7 +3742,10 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "string"
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
                                         }
                                     }
                                 }
@@ -3291,6 +3758,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3300,16 +3773,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/reservation/applications": {
+        "/api/v1/{cluster}/slurm/accounting/job/list": {
             "get": {
+                "description": "返回账户历史作业列表, 支持按用户, 账户, 分区, QoS, 状态, 时间范围, 作业名称, 退出码及节点过滤, 列表条件以逗号分隔, 匹配其中任一值.\n时间参数支持 Unix 时间戳, YYYY-MM-DD[THH:MM[:SS]] 及 now+count[seconds|minutes|hours|days|weeks].",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "资源预约"
+                    "作业管理"
                 ],
-                "summary": "获取资源预约申请列表",
+                "summary": "获取某集群账户中作业列表",
                 "parameters": [
                     {
                         "type": "string",
@@ -3319,32 +3793,128 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
-                    {
-                        "type": "string",
-                        "description": "申请人，缺省为全部",
-                        "name": "applier",
-                        "in": "query"
-                    },
                     {
                         "type": "boolean",
                         "default": true,
-                        "description": "是否分页",
+                        "description": "是否开启分页, 当前仅支持true",
                         "name": "paging",
                         "in": "query"
                     },
                     {
+                        "minimum": 1,
                         "type": "integer",
                         "default": 1,
-                        "description": "页码，从1开始",
+                        "description": "页号(从1开始)",
                         "name": "page",
                         "in": "query"
                     },
                     {
+                        "minimum": 1,
                         "type": "integer",
                         "default": 20,
-                        "description": "每页条数",
+                        "description": "每页数量",
                         "name": "page_size",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"alice,bob\"",
+                        "description": "用户名",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"physics\"",
+                        "description": "账户",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"gpu\"",
+                        "description": "分区",
+                        "name": "partition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"normal\"",
+                        "description": "QoS 名称",
+                        "name": "qos",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"COMPLETED,FAILED\"",
+                        "description": "状态, 大小写不敏感",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-01-01\"",
+                        "description": "提交时间下限",
+                        "name": "submit_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-01-31T23:59:59\"",
+                        "description": "提交时间上限",
+                        "name": "submit_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间下限",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间上限",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间下限",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间上限",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"train-*\"",
+                        "description": "作业名称, 支持 * 及 ? 通配符",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "退出码",
+                        "name": "exit_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"cn[01-04]\"",
+                        "description": "节点, 支持 hostlist 表达式",
+                        "name": "node",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"-submit\"",
+                        "description": "排序字段: jobid, submit, start, end, 前缀 - 表示降序",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3361,7 +3931,7 @@ const docTemplate = `{
                                         "results": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/slurm.Application"
+                                                "$ref": "#/definitions/slurm.JobListItem"
                                             }
                                         }
                                     }
@@ -3375,18 +3945,36 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/scheduling/job/list": {
+        "/api/v1/{cluster}/slurm/accounting/job/{jobid}/detail": {
             "get": {
-                "description": "返回当前调度队列中的作业列表，可选分页参数",
+                "description": "返回作业基础元信息与所有作业步列表, 作业步包含各任务 TRES 用量(最小, 最大, 平均及总量)的结构化表示",
                 "produces": [
                     "application/json"
                 ],
@@ -3394,7 +3982,7 @@ const docTemplate = `{
                     "资源管理",
                     "作业管理"
                 ],
-                "summary": "获取某集群调度列表中作业列表",
+                "summary": "获取某集群账户中某作业详情",
                 "parameters": [
                     {
                         "type": "string",
@@ -3405,27 +3993,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "boolean",
-                        "default": true,
-                        "description": "是否开启分页, 当前仅支持true",
-                        "name": "paging",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
-                        "type": "integer",
-                        "default": 1,
-                        "description": "页号(从1开始)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "minimum": 1,
                         "type": "integer",
-                        "default": 20,
-                        "description": "每页数量",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "作业号",
+                        "name": "jobid",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -3440,10 +4012,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/slurm.JobListItemOfScheduling"
-                                            }
+                                            "$ref": "#/definitions/slurm.DetailOfJobFromAccounting"
                                         }
                                     }
                                 }
@@ -3456,25 +4025,47 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
         },
-        "/api/v1/{cluster}/slurm/scheduling/job/{jobid}/detail": {
-            "get": {
+        "/api/v1/{cluster}/slurm/association": {
+            "put": {
+                "description": "由 account, user, partition 确定关联(user 为空时为账户本身的关联), 其余字段未设置时不修改, 数值限制为 -1 表示清除限制.\ngrp_tres 格式为 type[/name]=count, 逗号分隔, 仅修改列出的 TRES; qos, def_qos 为 QoS 名称, def_qos 须在可用 QoS 中.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "资源管理",
-                    "作业管理"
+                    "用户管理"
                 ],
-                "summary": "获取某集群调度列表中某作业详情",
+                "summary": "修改关联限制",
                 "parameters": [
                     {
                         "type": "string",
@@ -3485,11 +4076,102 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "作业号",
-                        "name": "jobid",
+                        "description": "关联及需修改的限制",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AssociationWrite"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "对应 sacctmgr add user \u003cuser\u003e account=\u003caccount\u003e [partition=\u003cpartition\u003e], 用户不存在时一并创建. partition 为空时关联全部分区.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "用户管理"
+                ],
+                "summary": "将用户加入账户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "用户, 账户及分区",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UserAssociation"
+                        }
                     }
                 ],
                 "responses": {
@@ -3504,10 +4186,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "results": {
-                                            "type": "array",
-                                            "items": {
-                                                "$ref": "#/definitions/model.JobsStepInScheduling"
-                                            }
+                                            "type": "string"
                                         }
                                     }
                                 }
@@ -3520,247 +4199,3732 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.Response"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "alert.Alert": {
-            "type": "object",
-            "properties": {
-                "annotations": {
-                    "description": "报警注释",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+        },
+        "/api/v1/{cluster}/slurm/association/detail": {
+            "get": {
+                "description": "根据 account、可选 user、可选 partition 查询关联详情",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "用户管理"
+                ],
+                "summary": "获取某集群中某个关联详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"root\"",
+                        "description": "账户名",
+                        "name": "account",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "\"\"",
+                        "example": "\"alice\"",
+                        "description": "用户名",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "\"\"",
+                        "example": "\"p1\"",
+                        "description": "分区名",
+                        "name": "partition",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.AssociationDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/association/{account}/childnodes": {
+            "get": {
+                "description": "返回账户关联树中该账户的子账户与子用户节点信息",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "用户管理"
+                ],
+                "summary": "获取某集群中指定账户的关联子节点信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"root\"",
+                        "description": "账户名称",
+                        "name": "account",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurmrest.AssociationNode"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/cache": {
+            "delete": {
+                "description": "节点, 分区, QoS, 账户, TRES 类型及调度队列等数据在服务端缓存一段时间(见配置 cache.ttl), 通过本接口修改的数据会自动清除缓存,\n直接在集群上修改(如 scontrol, sacctmgr)后可调用本接口立即生效. resource 可重复传递, 缺省清除全部.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理"
+                ],
+                "summary": "清除 Slurm 数据缓存",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "enum": [
+                                "nodes",
+                                "partitions",
+                                "qos",
+                                "accounts",
+                                "tres",
+                                "jobs"
+                            ],
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "资源类型",
+                        "name": "resource",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/job": {
+            "post": {
+                "description": "提交批处理脚本, 对应 sbatch. 作业默认以登录用户的身份运行, 指定 user 为其他用户时需 operator 或 admin 角色. validate_only 为 true 时仅校验作业能否被调度(sbatch --test-only), 不实际提交;\n官方 slurmrestd 不支持仅校验; 其以 X-SLURM-USER-NAME 指定提交用户, 集群的 slurmrestd_token 须为 root 或 SlurmUser 的令牌.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "作业管理"
+                ],
+                "summary": "提交作业",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "仅校验, 不提交",
+                        "name": "validate_only",
+                        "in": "query"
+                    },
+                    {
+                        "description": "脚本及作业选项",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/slurm.JobSubmitParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/model.JobSubmitResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/job/{jobid}/{action}": {
+            "post": {
+                "description": "对应 scancel 及 scontrol hold/release/requeue/suspend/resume. jobid 可为作业号(1234), 作业数组任务(1234_5, 1234_[1-3])\n或作业步(1234.0, 仅 cancel/signal), 多个作业以逗号分隔, 最多 100 个.\n普通用户仅能操作本人的作业, operator 可操作全部作业. 单个作业失败时按错误返回相应状态码;\n多个作业时返回 200 及每个作业的结果.\n官方 slurmrestd 仅支持 cancel, signal, hold, release.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "作业管理"
+                ],
+                "summary": "作业控制",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"1234,1235_[1-3]\"",
+                        "description": "作业号, 多个以逗号分隔",
+                        "name": "jobid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "cancel",
+                            "hold",
+                            "release",
+                            "requeue",
+                            "suspend",
+                            "resume",
+                            "signal"
+                        ],
+                        "type": "string",
+                        "description": "操作",
+                        "name": "action",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "信号",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/slurm.JobControlParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/slurm.JobControlResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/node/{name}/history": {
+            "get": {
+                "description": "通过本服务执行的 drain/resume/down/reboot 记录, 包括操作者, 时间及原因.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理"
+                ],
+                "summary": "获取节点状态变更历史",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"cn001\"",
+                        "description": "节点名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量, 最大仅支持100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.NodeStateChange"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/node/{name}/state": {
+            "put": {
+                "description": "对应 scontrol update nodename=... state=... reason=... 及 scontrol reboot. name 支持主机列表表达式(如 cn[001-004,010]),\n最多 1024 个节点. 变更记录(操作者, 时间, 原因)可通过节点变更历史接口查看. 官方 slurmrestd 不支持 REBOOT.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理"
+                ],
+                "summary": "设置节点状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"cn[001-004]\"",
+                        "description": "节点名称或主机列表表达式",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "目标状态及原因",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/slurm.NodeStateParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.NodeStateResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/nodes": {
+            "get": {
+                "description": "按分区查询节点列表，支持分页及按节点(hostlist 表达式)过滤。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源总览"
+                ],
+                "summary": "获取某集群分区节点列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "分区名称",
+                        "name": "partition",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"cn[01-04,10]\"",
+                        "description": "节点, 支持 hostlist 表达式",
+                        "name": "nodes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否开启分页, 默认为 true",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码，从1开始",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "每页数量，最大100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/overview": {
+            "get": {
+                "description": "获取资源总览页面资源统计信息, 包括集群节点总数, 逻辑CPU总数, 总核数, 内存总量, 运行作业数, 总作业数.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源总览"
+                ],
+                "summary": "获取资源总览页面资源统计信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.Overview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/partition/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "分区管理"
+                ],
+                "summary": "获取某集群所有分区概述",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否开启分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页号(从1开始)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/slurm.PartitionListItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/partition/{name}/detail": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "分区管理"
+                ],
+                "summary": "获取某集群中某个分区的详细内容",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"p1\"",
+                        "description": "分区名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.PartitionDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/qos": {
+            "post": {
+                "description": "对应 sacctmgr add qos. 未设置的字段使用 Slurm 默认值. TRES 字段格式为 type[/name]=count, 逗号分隔, 如 cpu=64,mem=256G,gres/gpu=8,\n服务端校验类型及数值, 容量转换为 MB. dry_run 为 true 时仅校验并返回与默认值相比的变化, 不实际创建.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "QoS"
+                ],
+                "summary": "创建 QoS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "仅校验并返回变化, 不创建",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "QoS 属性",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.QoSWrite"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.QoSWriteResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/qos/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "QoS"
+                ],
+                "summary": "获取某集群中 QoS 列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "是否开启分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码，从 1 开始（仅当 paging=true 生效）",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量，1-100（仅当 paging=true 生效）",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/slurm.QoSListElem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/qos/name/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "用户管理"
+                ],
+                "summary": "获取某集群中所有 QoS 名称列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/qos/{id}/detail": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "QoS"
+                ],
+                "summary": "获取某集群中某个 QoS 详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "QoS ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.QoSDetail"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/qos/{name}": {
+            "put": {
+                "description": "对应 sacctmgr modify qos, 请求体中未设置的字段不修改, 请求体中的 name 忽略. 数值限制为 -1 表示清除限制;\nTRES 字段仅修改列出的 TRES, count 为 -1 表示清除该 TRES 的限制. dry_run 为 true 时仅校验并返回与当前 QoS 相比的变化, 不实际修改.\n没有变化时不调用 slurmrestd.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "QoS"
+                ],
+                "summary": "修改 QoS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"normal\"",
+                        "description": "QoS 名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "仅校验并返回变化, 不修改",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "description": "需修改的 QoS 属性",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.QoSWrite"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.QoSWriteResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "对应 sacctmgr delete qos. 关联(association)中对该 QoS 的引用由 Slurm 一并移除.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "QoS"
+                ],
+                "summary": "删除 QoS",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"normal\"",
+                        "description": "QoS 名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/application": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "创建资源预约申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "申请内容",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/slurm.ApplicationContent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/application/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "更新资源预约申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "申请内容",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/slurm.ApplicationContent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "删除资源预约申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/application/{id}/check": {
+            "get": {
+                "description": "审核前检查申请的节点(nodes 或 node_cnt)在申请的时间段内是否可用: 节点是否存在及其状态, 是否与已有预约重叠,\n节点上运行中的作业是否在开始时间前结束, 以及节点是否属于申请的分区. 同时给出满足节点数的无冲突节点建议.\n普通用户仅能检查本人的申请.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "检查资源预约申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.ReservationCheck"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/application/{id}/decision": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "获取资源预约申请的审核结果",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/application/{id}/review": {
+            "put": {
+                "description": "通过时按申请内容创建 Slurm 预约(scontrol create reservation). Slurm 拒绝时申请状态为\"通过, 但预约创建失败\",\n审核意见中包含 Slurm 的错误信息.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "审核资源预约申请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "申请ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "审核结论",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/slurm.Review"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/applications": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "获取资源预约申请列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "申请人，缺省为全部",
+                        "name": "applier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码，从1开始",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页条数",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/slurm.Application"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/list": {
+            "get": {
+                "description": "对应 scontrol show reservation, 包括通过申请审核创建的预约及管理员直接创建的预约.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "获取资源预约列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量, 最大仅支持100",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Reservation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/reservation/{name}": {
+            "put": {
+                "description": "对应 scontrol update reservation, 请求体中为空的字段不修改, 请求体中的 name 忽略.\n官方 slurmrestd v0.0.43 之前的接口不支持.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "更新资源预约",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"resv_app1\"",
+                        "description": "预约名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "预约参数",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Reservation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "对应 scontrol delete reservation. 官方 slurmrestd v0.0.43 之前的接口不支持.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "资源预约"
+                ],
+                "summary": "删除资源预约",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"resv_app1\"",
+                        "description": "预约名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/scheduling/job/list": {
+            "get": {
+                "description": "返回当前调度队列中的作业列表及各状态, 各排队原因的作业数, 支持按用户, 账户, 分区, QoS, 状态, 原因及节点过滤, 列表条件以逗号分隔, 匹配其中任一值.\n作业数统计满足查询条件的全部作业, 不受分页影响. 调度队列在服务端缓存数秒(见配置 cache.ttl.jobs), 提交及控制作业后清除.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "作业管理"
+                ],
+                "summary": "获取某集群调度列表中作业列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "是否开启分页",
+                        "name": "paging",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页号(从1开始)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"alice,bob\"",
+                        "description": "用户名",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"physics\"",
+                        "description": "账户",
+                        "name": "account",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"gpu\"",
+                        "description": "分区",
+                        "name": "partition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"normal\"",
+                        "description": "QoS 名称",
+                        "name": "qos",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"PENDING\"",
+                        "description": "状态, 大小写不敏感",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"Resources,Priority\"",
+                        "description": "原因, 大小写不敏感",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"cn[01-04]\"",
+                        "description": "节点, 支持 hostlist 表达式, 返回分配的节点包含其中任一节点的作业",
+                        "name": "node",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"-priority\"",
+                        "description": "排序字段: priority, submit, jobid, 前缀 - 表示降序",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/slurm.SchedulingJobQueue"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/scheduling/job/{jobid}/detail": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "作业管理"
+                ],
+                "summary": "获取某集群调度列表中某作业详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "作业号",
+                        "name": "jobid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.JobsStepInScheduling"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/{cluster}/slurm/user/{user}/default_account": {
+            "put": {
+                "description": "对应 sacctmgr modify user \u003cuser\u003e set defaultaccount=\u003caccount\u003e, 用户须已加入该账户.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "资源管理",
+                    "用户管理"
+                ],
+                "summary": "设置用户的默认账户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"test\"",
+                        "description": "集群名称",
+                        "name": "cluster",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"alice\"",
+                        "description": "用户名",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "默认账户",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/slurm.DefaultAccountParam"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "健康检查"
+                ],
+                "summary": "存活检查",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "type": "string"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "探测数据库及每个已注册集群的 slurmrestd, Lustre KD 服务, Alertmanager, 返回各依赖状态及耗时.\n仅数据库不可用时返回 503; 集群依赖不可用时返回 200, status 为 degraded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "健康检查"
+                ],
+                "summary": "就绪检查",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/health.Readiness"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "results": {
+                                            "$ref": "#/definitions/health.Readiness"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "alert.Alert": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "description": "报警注释",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "endsat": {
+                    "description": "报警结束时间",
+                    "type": "string"
+                },
+                "fingerprint": {
+                    "description": "报警指纹",
+                    "type": "string"
+                },
+                "generatorurl": {
+                    "description": "报警产生来源",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID, 活跃报警中无该字段, 历史报警中才会存在, 对应数据库中 alert 表 ID.",
+                    "type": "integer"
+                },
+                "labels": {
+                    "description": "报警标签",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "operation": {
+                    "description": "报警处理办法",
+                    "type": "string"
+                },
+                "responder": {
+                    "description": "报警处理人",
+                    "type": "string"
+                },
+                "startsat": {
+                    "description": "报警开始时间",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "报警状态",
+                    "type": "string"
+                }
+            }
+        },
+        "alert.AlertsFiringResults": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "description": "报警信息",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/alert.Alert"
+                    }
+                },
+                "severity_count": {
+                    "description": "统计信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/alert.FiringStatistic"
+                        }
+                    ]
+                }
+            }
+        },
+        "alert.FiringStatistic": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "description": "时间报警统计信息",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "inband": {
+                    "description": "带内报警统计信息",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "outband": {
+                    "description": "带外报警统计信息",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "alert.InhibitParam": {
+            "type": "object",
+            "required": [
+                "board",
+                "rule",
+                "sensor"
+            ],
+            "properties": {
+                "board": {
+                    "description": "传感器编号",
+                    "type": "string"
+                },
+                "rmu_ip": {
+                    "description": "RMU IP, 未指定时使用集群默认 RMU",
+                    "type": "string"
+                },
+                "rule": {
+                    "description": "抑制规则",
+                    "type": "string"
+                },
+                "sensor": {
+                    "description": "传感器编号或传感器名字",
+                    "type": "string"
+                }
+            }
+        },
+        "alert.LowerQuery": {
+            "type": "object",
+            "required": [
+                "bmu_ip",
+                "id",
+                "lcr",
+                "lnc",
+                "lnr"
+            ],
+            "properties": {
+                "bmu_ip": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lcr": {
+                    "type": "number"
+                },
+                "lnc": {
+                    "type": "number"
+                },
+                "lnr": {
+                    "type": "number"
+                },
+                "rmu_ip": {
+                    "description": "RMU IP, 未指定时使用集群默认 RMU",
+                    "type": "string"
+                }
+            }
+        },
+        "alert.ThreshParam": {
+            "type": "object",
+            "required": [
+                "bmu_ip",
+                "id",
+                "value",
+                "which"
+            ],
+            "properties": {
+                "bmu_ip": {
+                    "type": "string"
                 },
-                "endsat": {
-                    "description": "报警结束时间",
+                "id": {
+                    "type": "string"
+                },
+                "rmu_ip": {
+                    "description": "RMU IP, 未指定时使用集群默认 RMU",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "which": {
+                    "type": "string"
+                }
+            }
+        },
+        "alert.UpperQuery": {
+            "type": "object",
+            "required": [
+                "bmu_ip",
+                "id",
+                "ucr",
+                "unc",
+                "unr"
+            ],
+            "properties": {
+                "bmu_ip": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rmu_ip": {
+                    "description": "RMU IP, 未指定时使用集群默认 RMU",
+                    "type": "string"
+                },
+                "ucr": {
+                    "type": "number"
+                },
+                "unc": {
+                    "type": "number"
+                },
+                "unr": {
+                    "type": "number"
+                }
+            }
+        },
+        "auth.Identity": {
+            "type": "object",
+            "properties": {
+                "cluster": {
+                    "description": "登录时校验身份的集群",
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "Ldap 附加组",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "username": {
+                    "description": "Ldap 用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LoginParam": {
+            "type": "object",
+            "required": [
+                "cluster",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "cluster": {
+                    "description": "集群名称, 使用该集群的 Ldap 校验身份",
+                    "type": "string"
+                },
+                "password": {
+                    "description": "密码",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Ldap 用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshParam": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "description": "登录时获取的刷新令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "auth.TokenPair": {
+            "type": "object",
+            "properties": {
+                "access_expires_at": {
+                    "description": "访问令牌过期时间",
+                    "type": "string"
+                },
+                "access_token": {
+                    "description": "访问令牌, 通过 Authorization: Bearer \u003ctoken\u003e 传递",
+                    "type": "string"
+                },
+                "refresh_expires_at": {
+                    "description": "刷新令牌过期时间",
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "description": "刷新令牌, 仅用于 /api/v1/auth/refresh",
+                    "type": "string"
+                }
+            }
+        },
+        "cluster.ClusterParam": {
+            "type": "object",
+            "required": [
+                "name",
+                "slurmrestd"
+            ],
+            "properties": {
+                "alertmanager_url": {
+                    "description": "Alertmanager 地址, 如 http://host:9093",
+                    "type": "string"
+                },
+                "bmc_password": {
+                    "description": "默认 BMC 密码, 更新时为空表示不修改",
+                    "type": "string"
+                },
+                "bmc_user": {
+                    "description": "默认 BMC 用户名",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "描述",
+                    "type": "string"
+                },
+                "lustre_server": {
+                    "description": "KD Lustre 服务地址(host:port)",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "集群名称, 更新时忽略",
+                    "type": "string"
+                },
+                "rmu_addr": {
+                    "description": "默认 RMU 地址",
+                    "type": "string"
+                },
+                "slurmrestd": {
+                    "description": "slurmrestd 服务地址(host:port)",
+                    "type": "string"
+                },
+                "slurmrestd_flavor": {
+                    "description": "slurmrestd 接口类型, 默认 custom",
+                    "type": "string",
+                    "enum": [
+                        "custom",
+                        "official"
+                    ]
+                },
+                "slurmrestd_token": {
+                    "description": "官方 slurmrestd 令牌(X-SLURM-USER-TOKEN), 须为 root 或 SlurmUser 的令牌(作业以请求用户的身份提交), 更新时为空表示不修改",
+                    "type": "string"
+                },
+                "slurmrestd_user": {
+                    "description": "官方 slurmrestd 用户名(X-SLURM-USER-NAME)",
+                    "type": "string"
+                },
+                "slurmrestd_version": {
+                    "description": "官方 slurmrestd 接口版本, 如 v0.0.40, 默认 v0.0.40",
+                    "type": "string"
+                }
+            }
+        },
+        "cluster.ClusterRoleParam": {
+            "type": "object",
+            "required": [
+                "group",
+                "role"
+            ],
+            "properties": {
+                "group": {
+                    "description": "Ldap 组名",
+                    "type": "string"
+                },
+                "role": {
+                    "description": "角色",
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "reviewer",
+                        "operator",
+                        "user"
+                    ]
+                }
+            }
+        },
+        "cluster.ClusterRolesParam": {
+            "type": "object",
+            "properties": {
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cluster.ClusterRoleParam"
+                    }
+                }
+            }
+        },
+        "health.DependencyStatus": {
+            "type": "object",
+            "properties": {
+                "cluster": {
+                    "description": "所属集群, postgres 为空",
+                    "type": "string"
+                },
+                "error": {
+                    "description": "失败原因",
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "description": "探测耗时(毫秒)",
+                    "type": "number"
+                },
+                "name": {
+                    "description": "依赖名称: postgres, slurmrestd, lustre, alertmanager",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "up, down, skipped",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "探测地址",
+                    "type": "string"
+                }
+            }
+        },
+        "health.Readiness": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "description": "各依赖状态",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.DependencyStatus"
+                    }
+                },
+                "status": {
+                    "description": "ready, degraded, notready",
+                    "type": "string"
+                }
+            }
+        },
+        "ldap.GroupListItem": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "description": "ldap.description",
+                    "type": "string"
+                },
+                "gid": {
+                    "description": "ldap.GidNumber",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "ldap.cn",
+                    "type": "string"
+                },
+                "users": {
+                    "description": "ldap.memberuid",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ldap.User": {
+            "type": "object",
+            "properties": {
+                "additional_groups": {
+                    "description": "需根据 ldap.uid 单独查询",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cn": {
+                    "description": "全名",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "对应 ldap.gidNumber",
+                    "type": "string"
+                },
+                "home_dir": {
+                    "description": "家目录",
+                    "type": "string"
+                },
+                "mobile": {
+                    "description": "电话",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "对应 ldap.uid",
+                    "type": "string"
+                },
+                "ou": {
+                    "description": "部门",
+                    "type": "string"
+                },
+                "uid": {
+                    "description": "对应 ldap.uidNumber",
                     "type": "string"
+                }
+            }
+        },
+        "lustre.QuotaApplication": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "description": "当前实际配额",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/lustre.UserQuota"
+                        }
+                    ]
+                },
+                "apply": {
+                    "description": "申请内容",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/lustre.UserQuota"
+                        }
+                    ]
                 },
-                "fingerprint": {
-                    "description": "报警指纹",
+                "apply_at": {
+                    "description": "申请日期",
                     "type": "string"
                 },
-                "generatorurl": {
-                    "description": "报警产生来源",
+                "decision": {
+                    "description": "审核结果",
                     "type": "string"
                 },
                 "id": {
-                    "description": "ID, 活跃报警中无该字段, 历史报警中才会存在, 对应数据库中 alert 表 ID.",
                     "type": "integer"
                 },
-                "labels": {
-                    "description": "报警标签",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "operation": {
-                    "description": "报警处理办法",
-                    "type": "string"
-                },
-                "responder": {
-                    "description": "报警处理人",
-                    "type": "string"
-                },
-                "startsat": {
-                    "description": "报警开始时间",
+                "review_at": {
+                    "description": "审核日期",
                     "type": "string"
                 },
-                "status": {
-                    "description": "报警状态",
+                "state": {
+                    "description": "审核状态, 0: 审核中, 1: 完成",
                     "type": "string"
                 }
             }
         },
-        "alert.AlertsFiringResults": {
+        "lustre.Review": {
             "type": "object",
             "properties": {
-                "alerts": {
-                    "description": "报警信息",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/alert.Alert"
-                    }
+                "approve": {
+                    "description": "是否通过审核",
+                    "type": "boolean"
                 },
-                "severity_count": {
-                    "description": "统计信息",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/alert.FiringStatistic"
-                        }
-                    ]
+                "block_quota_grace": {
+                    "description": "块配额宽限期",
+                    "type": "string"
+                },
+                "block_quota_hard_limit": {
+                    "description": "块配额硬限制",
+                    "type": "string"
+                },
+                "block_quota_soft_limit": {
+                    "description": "块配额软限制",
+                    "type": "string"
+                },
+                "decision": {
+                    "description": "审核意见",
+                    "type": "string"
+                },
+                "file_quota_grace": {
+                    "description": "文件配额宽限期",
+                    "type": "string"
+                },
+                "file_quota_hard_limit": {
+                    "description": "文件配额硬限制",
+                    "type": "string"
+                },
+                "file_quota_soft_limit": {
+                    "description": "文件配额软限制",
+                    "type": "string"
+                },
+                "filesystem": {
+                    "description": "文件系统",
+                    "type": "string"
+                },
+                "user": {
+                    "description": "用户",
+                    "type": "string"
                 }
             }
         },
-        "alert.FiringStatistic": {
+        "lustre.UserQuota": {
             "type": "object",
             "properties": {
-                "event": {
-                    "description": "时间报警统计信息",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "block_quota_grace": {
+                    "description": "块配额宽限期",
+                    "type": "string"
                 },
-                "inband": {
-                    "description": "带内报警统计信息",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "block_quota_hard_limit": {
+                    "description": "块配额硬限制",
+                    "type": "string"
                 },
-                "outband": {
-                    "description": "带外报警统计信息",
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "integer"
-                    }
+                "block_quota_soft_limit": {
+                    "description": "块配额软限制",
+                    "type": "string"
+                },
+                "file_quota_grace": {
+                    "description": "文件配额宽限期",
+                    "type": "string"
+                },
+                "file_quota_hard_limit": {
+                    "description": "文件配额硬限制",
+                    "type": "string"
+                },
+                "file_quota_soft_limit": {
+                    "description": "文件配额软限制",
+                    "type": "string"
+                },
+                "filesystem": {
+                    "description": "文件系统",
+                    "type": "string"
+                },
+                "user": {
+                    "description": "用户",
+                    "type": "string"
                 }
             }
         },
-        "alert.InhibitParam": {
+        "model.AccountWrite": {
             "type": "object",
             "required": [
-                "board",
-                "rmu_ip",
-                "rule",
-                "sensor"
+                "name"
             ],
             "properties": {
-                "board": {
-                    "description": "传感器编号",
-                    "type": "string"
+                "description": {
+                    "description": "描述",
+                    "type": "string",
+                    "example": "physics department"
                 },
-                "rmu_ip": {
-                    "description": "RMU IP",
-                    "type": "string"
+                "name": {
+                    "description": "账户名称",
+                    "type": "string",
+                    "example": "physics"
                 },
-                "rule": {
-                    "description": "抑制规则",
-                    "type": "string"
+                "organization": {
+                    "description": "单位",
+                    "type": "string",
+                    "example": "school"
                 },
-                "sensor": {
-                    "description": "传感器编号或传感器名字",
-                    "type": "string"
+                "parent": {
+                    "description": "父账户, 为空时为 root",
+                    "type": "string",
+                    "example": "root"
                 }
             }
         },
-        "alert.LowerQuery": {
+        "model.AssociationWrite": {
             "type": "object",
             "required": [
-                "bmu_ip",
-                "id",
-                "lcr",
-                "lnc",
-                "lnr",
-                "rmu_ip"
+                "account"
             ],
             "properties": {
-                "bmu_ip": {
-                    "type": "string"
+                "account": {
+                    "description": "账户",
+                    "type": "string",
+                    "example": "physics"
                 },
-                "id": {
-                    "type": "string"
+                "def_qos": {
+                    "description": "默认 QoS 名称, 须在可用 QoS 中; 为空字符串时清除",
+                    "type": "string",
+                    "example": "normal"
                 },
-                "lcr": {
-                    "type": "number"
+                "grp_tres": {
+                    "description": "组级总 TRES 资源限制, 仅修改列出的 TRES, count 为 -1 表示清除",
+                    "type": "string",
+                    "example": "cpu=128"
                 },
-                "lnc": {
-                    "type": "number"
+                "max_jobs": {
+                    "description": "运行作业数上限",
+                    "type": "integer"
                 },
-                "lnr": {
-                    "type": "number"
+                "max_wall_pj": {
+                    "description": "单作业最大运行时间(分钟)",
+                    "type": "integer"
                 },
-                "rmu_ip": {
-                    "type": "string"
+                "partition": {
+                    "description": "分区",
+                    "type": "string",
+                    "example": ""
+                },
+                "qos": {
+                    "description": "可用 QoS 名称, 逗号分隔",
+                    "type": "string",
+                    "example": "normal,high"
+                },
+                "shares": {
+                    "description": "公平份额权重, -1 表示恢复默认值(1)",
+                    "type": "integer"
+                },
+                "user": {
+                    "description": "用户",
+                    "type": "string",
+                    "example": "alice"
                 }
             }
         },
-        "alert.ThreshParam": {
+        "model.AuditLog": {
             "type": "object",
-            "required": [
-                "bmu_ip",
-                "id",
-                "rmu_ip",
-                "value",
-                "which"
-            ],
             "properties": {
-                "bmu_ip": {
+                "actor": {
+                    "description": "调用者, 未认证时为空",
+                    "type": "string"
+                },
+                "body": {
+                    "description": "脱敏后的请求体",
                     "type": "string"
                 },
+                "client_ip": {
+                    "description": "客户端地址",
+                    "type": "string"
+                },
+                "cluster": {
+                    "description": "集群名称",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "请求开始时间",
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "description": "耗时(毫秒)",
+                    "type": "integer"
+                },
                 "id": {
+                    "description": "ID",
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "HTTP 方法",
                     "type": "string"
                 },
-                "rmu_ip": {
+                "outcome": {
+                    "description": "success / failure",
                     "type": "string"
                 },
-                "value": {
-                    "type": "number"
+                "path": {
+                    "description": "实际请求路径",
+                    "type": "string"
                 },
-                "which": {
+                "route": {
+                    "description": "路由模板, 如 /api/v1/:cluster/ldap/user/:name",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "HTTP 响应码",
+                    "type": "integer"
+                },
+                "target": {
+                    "description": "操作对象(路径参数), 如 name=foo",
                     "type": "string"
                 }
             }
         },
-        "alert.UpperQuery": {
+        "model.Cluster": {
             "type": "object",
-            "required": [
-                "bmu_ip",
-                "id",
-                "rmu_ip",
-                "ucr",
-                "unc",
-                "unr"
-            ],
             "properties": {
-                "bmu_ip": {
+                "alertmanager_url": {
+                    "description": "Alertmanager 地址, 如 http://host:9093",
+                    "type": "string"
+                },
+                "bmc_user": {
+                    "description": "默认 BMC(ipmitool) 用户名",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "描述",
                     "type": "string"
                 },
                 "id": {
+                    "description": "ID",
+                    "type": "integer"
+                },
+                "lustre_server": {
+                    "description": "KD Lustre 服务地址(host:port)",
                     "type": "string"
                 },
-                "rmu_ip": {
+                "name": {
+                    "description": "集群名称, 对应路由中的 :cluster",
                     "type": "string"
                 },
-                "ucr": {
-                    "type": "number"
+                "rmu_addr": {
+                    "description": "默认 RMU 地址",
+                    "type": "string"
                 },
-                "unc": {
-                    "type": "number"
+                "slurmrestd": {
+                    "description": "slurmrestd 服务地址(host:port)",
+                    "type": "string"
                 },
-                "unr": {
-                    "type": "number"
+                "slurmrestd_flavor": {
+                    "description": "slurmrestd 接口类型, custom 或 official",
+                    "type": "string"
+                },
+                "slurmrestd_user": {
+                    "description": "官方 slurmrestd 用户名(X-SLURM-USER-NAME), 可为空",
+                    "type": "string"
+                },
+                "slurmrestd_version": {
+                    "description": "slurmrestd 接口版本, 如 v0.0.40",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "更新时间",
+                    "type": "string"
                 }
             }
         },
-        "ldap.GroupListItem": {
+        "model.ClusterRole": {
             "type": "object",
             "properties": {
-                "description": {
-                    "description": "ldap.description",
+                "group": {
+                    "description": "Ldap 组名",
                     "type": "string"
                 },
-                "gid": {
-                    "description": "ldap.GidNumber",
-                    "type": "integer"
-                },
-                "name": {
-                    "description": "ldap.cn",
+                "role": {
+                    "description": "角色, admin/reviewer/operator/user",
+                    "type": "string"
+                }
+            }
+        },
+        "model.JobSubmitResult": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "description": "作业号, 仅校验时为空",
                     "type": "string"
                 },
-                "users": {
-                    "description": "ldap.memberuid",
+                "warnings": {
+                    "description": "提交警告(如 slurmctld 的 job_submit 插件返回的提示)",
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -3768,179 +7932,240 @@ const docTemplate = `{
                 }
             }
         },
-        "ldap.User": {
+        "model.JobsStepInScheduling": {
             "type": "object",
             "properties": {
-                "additional_groups": {
-                    "description": "需根据 ldap.uid 单独查询",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "Name": {
+                    "type": "string"
                 },
-                "cn": {
-                    "description": "全名",
+                "id": {
                     "type": "string"
                 },
-                "group": {
-                    "description": "对应 ldap.gidNumber",
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.NodeStateChange": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "操作者, 未认证时为空",
                     "type": "string"
                 },
-                "home_dir": {
-                    "description": "家目录",
+                "cluster": {
+                    "description": "集群名称",
                     "type": "string"
                 },
-                "mobile": {
-                    "description": "电话",
+                "created_at": {
+                    "description": "变更时间",
                     "type": "string"
                 },
-                "name": {
-                    "description": "对应 ldap.uid",
+                "id": {
+                    "description": "ID",
+                    "type": "integer"
+                },
+                "node": {
+                    "description": "节点名称",
                     "type": "string"
                 },
-                "ou": {
-                    "description": "部门",
+                "reason": {
+                    "description": "原因",
                     "type": "string"
                 },
-                "uid": {
-                    "description": "对应 ldap.uidNumber",
+                "state": {
+                    "description": "目标状态: DRAIN / RESUME / DOWN / REBOOT",
                     "type": "string"
                 }
             }
         },
-        "lustre.QuotaApplication": {
+        "model.QoSWrite": {
             "type": "object",
             "properties": {
-                "actual": {
-                    "description": "当前实际配额",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/lustre.UserQuota"
-                        }
-                    ]
+                "description": {
+                    "description": "描述",
+                    "type": "string"
                 },
-                "apply": {
-                    "description": "申请内容",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/lustre.UserQuota"
-                        }
-                    ]
+                "flags": {
+                    "description": "标志, 逗号分隔, 如 DenyOnLimit,NoReserve; 为空字符串时清除全部标志",
+                    "type": "string"
                 },
-                "apply_at": {
-                    "description": "申请日期",
+                "grace_time": {
+                    "description": "抢占宽限时间(秒)",
+                    "type": "integer"
+                },
+                "grp_jobs": {
+                    "description": "总运行作业数限制",
+                    "type": "integer"
+                },
+                "grp_submit_jobs": {
+                    "description": "总提交作业数限制",
+                    "type": "integer"
+                },
+                "grp_tres": {
+                    "description": "总 TRES 限制, GrpTRES",
                     "type": "string"
                 },
-                "decision": {
-                    "description": "审核结果",
+                "grp_tres_mins": {
+                    "description": "总 TRES 时间限制, GrpTRESMins",
                     "type": "string"
                 },
-                "id": {
+                "grp_tres_run_mins": {
+                    "description": "总 TRES 运行时间限制, GrpTRESRunMins",
+                    "type": "string"
+                },
+                "grp_wall": {
+                    "description": "总运行时间限制(分钟)",
                     "type": "integer"
                 },
-                "review_at": {
-                    "description": "审核日期",
+                "max_jobs_pa": {
+                    "description": "每账号运行作业数限制",
+                    "type": "integer"
+                },
+                "max_jobs_per_user": {
+                    "description": "每用户运行作业数限制",
+                    "type": "integer"
+                },
+                "max_submit_jobs_pa": {
+                    "description": "每账号提交作业数限制",
+                    "type": "integer"
+                },
+                "max_submit_jobs_per_user": {
+                    "description": "每用户提交作业数限制",
+                    "type": "integer"
+                },
+                "max_tres_mins_pj": {
+                    "description": "作业 TRES 时间限制, MaxTRESMinsPerJob",
                     "type": "string"
                 },
-                "state": {
-                    "description": "审核状态, 0: 审核中, 1: 完成",
+                "max_tres_pa": {
+                    "description": "账号 TRES 限制, MaxTRESPerAccount",
                     "type": "string"
-                }
-            }
-        },
-        "lustre.Review": {
-            "type": "object",
-            "properties": {
-                "approve": {
-                    "description": "是否通过审核",
-                    "type": "boolean"
                 },
-                "block_quota_grace": {
-                    "description": "块配额宽限期",
+                "max_tres_pj": {
+                    "description": "作业 TRES 限制, MaxTRESPerJob",
                     "type": "string"
                 },
-                "block_quota_hard_limit": {
-                    "description": "块配额硬限制",
+                "max_tres_pn": {
+                    "description": "节点 TRES 限制, MaxTRESPerNode",
                     "type": "string"
                 },
-                "block_quota_soft_limit": {
-                    "description": "块配额软限制",
+                "max_tres_pu": {
+                    "description": "用户 TRES 限制, MaxTRESPerUser",
                     "type": "string"
                 },
-                "decision": {
-                    "description": "审核意见",
+                "max_tres_run_mins_pa": {
+                    "description": "账号 TRES 运行时间限制, MaxTRESRunMinsPerAccount",
                     "type": "string"
                 },
-                "file_quota_grace": {
-                    "description": "文件配额宽限期",
+                "max_tres_run_mins_pu": {
+                    "description": "用户 TRES 运行时间限制, MaxTRESRunMinsPerUser",
                     "type": "string"
                 },
-                "file_quota_hard_limit": {
-                    "description": "文件配额硬限制",
+                "max_wall_duration_per_job": {
+                    "description": "作业运行时间限制(分钟)",
+                    "type": "integer"
+                },
+                "min_tres_pj": {
+                    "description": "作业 TRES 下限, MinTRESPerJob",
                     "type": "string"
                 },
-                "file_quota_soft_limit": {
-                    "description": "文件配额软限制",
+                "name": {
+                    "description": "名称",
                     "type": "string"
                 },
-                "filesystem": {
-                    "description": "文件系统",
+                "preempt": {
+                    "description": "可被抢占的 QoS 名称, 逗号分隔; 为空字符串时清除",
                     "type": "string"
                 },
-                "user": {
-                    "description": "用户",
+                "preempt_exempt_time": {
+                    "description": "抢占豁免时间(秒)",
+                    "type": "integer"
+                },
+                "preempt_mode": {
+                    "description": "抢占模式: OFF, SUSPEND, REQUEUE, CANCEL, 可附加 GANG, WITHIN",
                     "type": "string"
+                },
+                "priority": {
+                    "description": "优先级因子",
+                    "type": "integer"
+                },
+                "usage_factor": {
+                    "description": "资源使用因子",
+                    "type": "number"
+                },
+                "usage_thres": {
+                    "description": "资源使用阈值",
+                    "type": "number"
                 }
             }
         },
-        "lustre.UserQuota": {
+        "model.Reservation": {
             "type": "object",
             "properties": {
-                "block_quota_grace": {
-                    "description": "块配额宽限期",
+                "accounts": {
+                    "description": "Accounts, 逗号分隔",
                     "type": "string"
                 },
-                "block_quota_hard_limit": {
-                    "description": "块配额硬限制",
+                "duration": {
+                    "description": "Duration, 如 120(分钟), 2-00:00:00, UNLIMITED",
                     "type": "string"
                 },
-                "block_quota_soft_limit": {
-                    "description": "块配额软限制",
+                "end_time": {
+                    "description": "EndTime, 创建时与 Duration 二选一",
                     "type": "string"
                 },
-                "file_quota_grace": {
-                    "description": "文件配额宽限期",
+                "flags": {
+                    "description": "Flags, 逗号分隔, 如 MAINT,IGNORE_JOBS",
                     "type": "string"
                 },
-                "file_quota_hard_limit": {
-                    "description": "文件配额硬限制",
+                "name": {
+                    "description": "ReservationName, 创建时为空则由 Slurm 生成",
                     "type": "string"
                 },
-                "file_quota_soft_limit": {
-                    "description": "文件配额软限制",
+                "node_cnt": {
+                    "description": "NodeCnt, 未指定 Nodes 时由 Slurm 选择节点",
+                    "type": "integer"
+                },
+                "nodes": {
+                    "description": "Nodes, 主机列表表达式, 如 cn[001-004]",
                     "type": "string"
                 },
-                "filesystem": {
-                    "description": "文件系统",
+                "partition": {
+                    "description": "PartitionName",
                     "type": "string"
                 },
-                "user": {
-                    "description": "用户",
+                "start_time": {
+                    "description": "StartTime, 如 2025-01-01T08:00:00, now, now+1hour",
+                    "type": "string"
+                },
+                "users": {
+                    "description": "Users, 逗号分隔",
                     "type": "string"
                 }
             }
         },
-        "model.JobsStepInScheduling": {
+        "model.UserAssociation": {
             "type": "object",
+            "required": [
+                "account",
+                "user"
+            ],
             "properties": {
-                "Name": {
-                    "type": "string"
+                "account": {
+                    "description": "账户",
+                    "type": "string",
+                    "example": "physics"
                 },
-                "id": {
-                    "type": "string"
+                "partition": {
+                    "description": "分区, 为空时关联全部分区",
+                    "type": "string",
+                    "example": "cpu"
                 },
-                "state": {
-                    "type": "string"
+                "user": {
+                    "description": "用户名",
+                    "type": "string",
+                    "example": "alice"
                 }
             }
         },
@@ -3957,7 +8182,8 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "id": {
-                    "type": "integer"
+                    "type": "integer",
+                    "format": "int64"
                 },
                 "label": {
                     "type": "object",
@@ -3982,6 +8208,10 @@ const docTemplate = `{
         "response.Response": {
             "type": "object",
             "properties": {
+                "code": {
+                    "description": "错误码(见 apperror), 成功时为空",
+                    "type": "string"
+                },
                 "count": {
                     "type": "integer"
                 },
@@ -4004,6 +8234,10 @@ const docTemplate = `{
                     "description": "申请日期",
                     "type": "string"
                 },
+                "cluster": {
+                    "description": "申请的集群",
+                    "type": "string"
+                },
                 "decision": {
                     "description": "审核结果",
                     "type": "string"
@@ -4044,7 +8278,11 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "state": {
-                    "description": "审核状态",
+                    "description": "申请状态, 通过后由后台依次变更为生效中, 已到期或已取消",
+                    "type": "string"
+                },
+                "state_changed_at": {
+                    "description": "后台最近一次变更状态的时间",
                     "type": "string"
                 },
                 "user": {
@@ -4188,24 +8426,75 @@ const docTemplate = `{
                 "shares": {
                     "description": "公平份额权重",
                     "type": "integer"
+                },
+                "tres_resources": {
+                    "description": "TRES 限制的结构化表示, 键为上述 TRES 字段名, 未设置的字段不包含; *_mins 字段的值为分钟数",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/tres.Resources"
+                    }
+                }
+            }
+        },
+        "slurm.DefaultAccountParam": {
+            "type": "object",
+            "required": [
+                "account"
+            ],
+            "properties": {
+                "account": {
+                    "description": "默认账户, 用户须已加入该账户",
+                    "type": "string",
+                    "example": "physics"
+                }
+            }
+        },
+        "slurm.DetailOfJobFromAccounting": {
+            "type": "object",
+            "properties": {
+                "jobid": {
+                    "description": "作业ID",
+                    "type": "integer"
+                },
+                "meta": {
+                    "$ref": "#/definitions/slurm.MetadataOfJobFromAccounting"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/slurm.StepOfJobFromAccounting"
+                    }
+                }
+            }
+        },
+        "slurm.JobControlParam": {
+            "type": "object",
+            "properties": {
+                "signal": {
+                    "description": "信号, action 为 signal 时必填, cancel 时可选",
+                    "type": "string",
+                    "example": "USR1"
                 }
             }
         },
-        "slurm.DetailOfJobFromAccounting": {
+        "slurm.JobControlResult": {
             "type": "object",
             "properties": {
-                "jobid": {
-                    "description": "作业ID",
-                    "type": "integer"
+                "code": {
+                    "description": "失败时的错误码",
+                    "type": "string"
                 },
-                "meta": {
-                    "$ref": "#/definitions/slurm.MetadataOfJobFromAccounting"
+                "detail": {
+                    "description": "失败原因",
+                    "type": "string"
                 },
-                "steps": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/slurm.StepOfJobFromAccounting"
-                    }
+                "jobid": {
+                    "description": "作业号",
+                    "type": "string"
+                },
+                "success": {
+                    "description": "是否成功",
+                    "type": "boolean"
                 }
             }
         },
@@ -4216,10 +8505,18 @@ const docTemplate = `{
                     "description": "账号",
                     "type": "string"
                 },
+                "exit_code": {
+                    "description": "退出码",
+                    "type": "integer"
+                },
                 "jobid": {
                     "description": "作业ID",
                     "type": "integer"
                 },
+                "name": {
+                    "description": "作业名称",
+                    "type": "string"
+                },
                 "nodelist": {
                     "description": "节点列表",
                     "type": "string"
@@ -4240,9 +8537,28 @@ const docTemplate = `{
                     "description": "状态",
                     "type": "string"
                 },
+                "time_end": {
+                    "description": "结束时间(Unix 时间戳), 0 表示未结束",
+                    "type": "integer"
+                },
+                "time_start": {
+                    "description": "开始时间(Unix 时间戳), 0 表示未开始",
+                    "type": "integer"
+                },
+                "time_submit": {
+                    "description": "提交时间(Unix 时间戳)",
+                    "type": "integer"
+                },
                 "tres_alloc": {
                     "description": "资源个数",
                     "type": "string"
+                },
+                "tres_resources": {
+                    "description": "tres_alloc 的结构化表示, 键为 tres_alloc",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/tres.Resources"
+                    }
                 }
             }
         },
@@ -4265,6 +8581,10 @@ const docTemplate = `{
                     "description": "分区",
                     "type": "string"
                 },
+                "priority": {
+                    "description": "优先级",
+                    "type": "integer"
+                },
                 "qos": {
                     "description": "QoS",
                     "type": "string"
@@ -4277,12 +8597,100 @@ const docTemplate = `{
                     "description": "状态",
                     "type": "string"
                 },
+                "time_submit": {
+                    "description": "提交时间(Unix 时间戳)",
+                    "type": "integer"
+                },
                 "user": {
                     "description": "账户 user(account)",
                     "type": "string"
                 }
             }
         },
+        "slurm.JobSubmitParam": {
+            "type": "object",
+            "required": [
+                "script"
+            ],
+            "properties": {
+                "account": {
+                    "description": "账户",
+                    "type": "string",
+                    "example": "root"
+                },
+                "array": {
+                    "description": "作业数组",
+                    "type": "string",
+                    "example": "1-10%2"
+                },
+                "cpus_per_task": {
+                    "description": "每个任务的 CPU 数",
+                    "type": "integer",
+                    "example": 1
+                },
+                "dependency": {
+                    "description": "作业依赖",
+                    "type": "string",
+                    "example": "afterok:1234"
+                },
+                "environment": {
+                    "description": "环境变量",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "gres": {
+                    "description": "通用资源",
+                    "type": "string",
+                    "example": "gpu:1"
+                },
+                "name": {
+                    "description": "作业名",
+                    "type": "string",
+                    "example": "test"
+                },
+                "nodes": {
+                    "description": "节点数, 如 2 或 1-4",
+                    "type": "string",
+                    "example": "1-2"
+                },
+                "partition": {
+                    "description": "分区",
+                    "type": "string",
+                    "example": "cpu"
+                },
+                "qos": {
+                    "description": "QoS",
+                    "type": "string",
+                    "example": "normal"
+                },
+                "script": {
+                    "description": "批处理脚本, 以 #! 开头",
+                    "type": "string",
+                    "example": "#!/bin/bash\nsrun hostname"
+                },
+                "tasks": {
+                    "description": "任务数",
+                    "type": "integer",
+                    "example": 4
+                },
+                "time_limit": {
+                    "description": "运行时间上限, 单位分钟",
+                    "type": "integer",
+                    "example": 60
+                },
+                "user": {
+                    "description": "提交用户, 为空表示登录用户; 代其他用户提交需 operator 或 admin 角色",
+                    "type": "string"
+                },
+                "work_dir": {
+                    "description": "工作目录",
+                    "type": "string",
+                    "example": "/home/test"
+                }
+            }
+        },
         "slurm.LdapGroup": {
             "type": "object",
             "properties": {
@@ -4376,6 +8784,21 @@ const docTemplate = `{
                     "description": "运行结果 state 和 exitcode 组合",
                     "type": "string"
                 },
+                "tres_alloc": {
+                    "description": "已分配资源",
+                    "type": "string"
+                },
+                "tres_req": {
+                    "description": "申请资源",
+                    "type": "string"
+                },
+                "tres_resources": {
+                    "description": "tres_alloc 及 tres_req 的结构化表示, 键为字段名, 为空的字段不包含",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/tres.Resources"
+                    }
+                },
                 "user": {
                     "description": "用户名",
                     "type": "string"
@@ -4386,6 +8809,53 @@ const docTemplate = `{
                 }
             }
         },
+        "slurm.NodeStateParam": {
+            "type": "object",
+            "required": [
+                "reason",
+                "state"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "原因",
+                    "type": "string",
+                    "example": "disk failure"
+                },
+                "state": {
+                    "description": "目标状态: DRAIN, RESUME, DOWN, REBOOT",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/slurmrest.NodeState"
+                        }
+                    ],
+                    "example": "DRAIN"
+                }
+            }
+        },
+        "slurm.NodeStateResult": {
+            "type": "object",
+            "properties": {
+                "nodes": {
+                    "description": "展开后的节点列表",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "description": "原因",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "目标状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/slurmrest.NodeState"
+                        }
+                    ]
+                }
+            }
+        },
         "slurm.Overview": {
             "type": "object",
             "properties": {
@@ -4545,6 +9015,23 @@ const docTemplate = `{
                 }
             }
         },
+        "slurm.QoSChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "字段, 与请求体中的名称一致",
+                    "type": "string"
+                },
+                "new": {
+                    "description": "修改后的值",
+                    "type": "string"
+                },
+                "old": {
+                    "description": "当前值",
+                    "type": "string"
+                }
+            }
+        },
         "slurm.QoSDetail": {
             "type": "object",
             "properties": {
@@ -4676,6 +9163,13 @@ const docTemplate = `{
                     "description": "优先级因子",
                     "type": "integer"
                 },
+                "tres_resources": {
+                    "description": "TRES 限制的结构化表示, 键为上述 TRES 字段名, 未设置的字段不包含; *_mins 字段的值为分钟数",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/tres.Resources"
+                    }
+                },
                 "usage_factor": {
                     "description": "资源使用因子",
                     "type": "number"
@@ -4735,6 +9229,100 @@ const docTemplate = `{
                 }
             }
         },
+        "slurm.QoSWriteResult": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "与当前 QoS(创建时为 Slurm 默认值)相比的变化",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/slurm.QoSChange"
+                    }
+                },
+                "dry_run": {
+                    "description": "为 true 时未实际修改",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "QoS 名称",
+                    "type": "string"
+                }
+            }
+        },
+        "slurm.ReservationCheck": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "description": "申请节点的冲突",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/slurm.ReservationConflict"
+                    }
+                },
+                "end_time": {
+                    "description": "申请的结束时间, 不限时长时为零值",
+                    "type": "string"
+                },
+                "node_cnt": {
+                    "description": "需要的节点数",
+                    "type": "integer"
+                },
+                "ok": {
+                    "description": "申请的节点无冲突且属于申请的分区, 可直接审核通过",
+                    "type": "boolean"
+                },
+                "partition_mismatch": {
+                    "description": "不属于申请分区的节点",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requested": {
+                    "description": "申请的节点(展开后), 仅指定节点数时为空",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "satisfiable": {
+                    "description": "建议的节点是否满足需要的节点数",
+                    "type": "boolean"
+                },
+                "start_time": {
+                    "description": "申请的开始时间",
+                    "type": "string"
+                },
+                "suggested": {
+                    "description": "建议的无冲突节点, 优先保留申请中无冲突的节点",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "suggested_nodes": {
+                    "description": "建议节点的主机列表表达式, 如 cn[01-04], 可直接用作预约的 nodes",
+                    "type": "string"
+                }
+            }
+        },
+        "slurm.ReservationConflict": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "节点状态, 预约名称或作业号等",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "冲突类型: node_not_found, node_state, reservation, job",
+                    "type": "string"
+                },
+                "node": {
+                    "description": "节点名称",
+                    "type": "string"
+                }
+            }
+        },
         "slurm.Review": {
             "type": "object",
             "properties": {
@@ -4778,6 +9366,32 @@ const docTemplate = `{
                 }
             }
         },
+        "slurm.SchedulingJobQueue": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "description": "作业列表",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/slurm.JobListItemOfScheduling"
+                    }
+                },
+                "reasons": {
+                    "description": "排队(PENDING)作业按原因统计的作业数, 如 {\"Resources\": 120, \"QOSMaxJobsPerUserLimit\": 40}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "states": {
+                    "description": "各状态的作业数, 始终包含 PENDING, RUNNING, COMPLETING",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "slurm.StepOfJobFromAccounting": {
             "type": "object",
             "properties": {
@@ -4788,6 +9402,13 @@ const docTemplate = `{
                 "state": {
                     "description": "作业步状态",
                     "type": "string"
+                },
+                "tres_resources": {
+                    "description": "各任务 TRES 用量的结构化表示, 键为 tres_usage_{in,out}_{min,max,ave,tot}, 为空的字段不包含; cpu 为 CPU 时间(秒), mem_mb 为内存(MB)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/tres.Resources"
+                    }
                 }
             }
         },
@@ -4864,6 +9485,33 @@ const docTemplate = `{
                 }
             }
         },
+        "slurmrest.NodeState": {
+            "type": "string",
+            "enum": [
+                "DRAIN",
+                "RESUME",
+                "DOWN",
+                "REBOOT"
+            ],
+            "x-enum-comments": {
+                "NODE_STATE_DOWN": "立即下线, 运行中的作业被终止",
+                "NODE_STATE_DRAIN": "不再分配新作业, 运行中的作业不受影响",
+                "NODE_STATE_REBOOT": "作业结束后重启(scontrol reboot)",
+                "NODE_STATE_RESUME": "恢复 drain/down 的节点"
+            },
+            "x-enum-descriptions": [
+                "不再分配新作业, 运行中的作业不受影响",
+                "恢复 drain/down 的节点",
+                "立即下线, 运行中的作业被终止",
+                "作业结束后重启(scontrol reboot)"
+            ],
+            "x-enum-varnames": [
+                "NODE_STATE_DRAIN",
+                "NODE_STATE_RESUME",
+                "NODE_STATE_DOWN",
+                "NODE_STATE_REBOOT"
+            ]
+        },
         "slurmrest.UserNode": {
             "type": "object",
             "properties": {
@@ -4883,6 +9531,87 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "transport.BreakerStatus": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "上游地址 host:port",
+                    "type": "string"
+                },
+                "failures": {
+                    "description": "连续失败次数",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "最近一次失败原因",
+                    "type": "string"
+                },
+                "opened_at": {
+                    "description": "最近一次熔断时间, 未熔断过时为零值",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "熔断器状态",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/transport.State"
+                        }
+                    ]
+                },
+                "upstream": {
+                    "description": "上游服务, 如 slurmrestd",
+                    "type": "string"
+                }
+            }
+        },
+        "transport.State": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "open",
+                "half_open"
+            ],
+            "x-enum-comments": {
+                "STATE_CLOSED": "正常",
+                "STATE_HALF_OPEN": "冷却结束, 正在放行探测请求",
+                "STATE_OPEN": "熔断中, 请求直接失败"
+            },
+            "x-enum-descriptions": [
+                "正常",
+                "熔断中, 请求直接失败",
+                "冷却结束, 正在放行探测请求"
+            ],
+            "x-enum-varnames": [
+                "STATE_CLOSED",
+                "STATE_OPEN",
+                "STATE_HALF_OPEN"
+            ]
+        },
+        "tres.Resources": {
+            "type": "object",
+            "properties": {
+                "billing": {
+                    "description": "计费值",
+                    "type": "integer"
+                },
+                "cpu": {
+                    "description": "CPU 数",
+                    "type": "integer"
+                },
+                "gpus": {
+                    "description": "GPU 数, 即 gres/gpu, 不存在时为各型号(gres/gpu:\u003ctype\u003e)之和",
+                    "type": "integer"
+                },
+                "mem_mb": {
+                    "description": "内存(MB)",
+                    "type": "integer"
+                },
+                "nodes": {
+                    "description": "节点数",
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
@@ -1,12 +1,17 @@
 package router
 
 import (
+	"csjk-bk/internal/pkg/metrics"
+
 	"github.com/gin-gonic/gin"
 )
 
-func New() *gin.Engine {
+// New 创建 gin.Engine, middlewares(如认证)在内置中间件之后按顺序执行.
+func New(middlewares ...gin.HandlerFunc) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Recovery())
-	// TODO: 日志、鉴权、CORS、trace、中间件
+	r.Use(metrics.Middleware())
+	r.Use(middlewares...)
+	// TODO: CORS、trace 中间件
 	return r
 }
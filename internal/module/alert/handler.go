@@ -1,8 +1,13 @@
 package alert
 
 import (
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/alertmanager"
+	"csjk-bk/internal/pkg/client/exec"
 	"csjk-bk/internal/pkg/common/paging"
+	"csjk-bk/internal/pkg/middleware"
 	"csjk-bk/internal/pkg/response"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -43,13 +48,17 @@ type Alert struct {
 // @Description 从报警平台(alertmanager)中获取实时报警信息, 请求参数 class 对应分屏设计. 不分屏时, class=all. 分屏时 class="<对应系统>"
 // @Tags 报警, 实时报警
 // @Produce json
+// @Param cluster query string true "集群名称"
 // @Param class query string false "报警类别, 支持 inband, outband, event, all" Enums(inband, outband, event, all) default(all)
 // @Param paging query bool false "启动分页, 当前仅支持强制分页" Enums(true) default(true)
 // @Param page query int false "页码" default(1) minimum(1)
 // @Param page_size query int false "每页报警数量, 最大仅支持100" default(20) minimum(1) maximum(100)
 // @Success 200 {object} response.Response{results=AlertsFiringResults}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/alerts/firing [get]
 func (rt *Router) HandlerGetAlertsFiring(c *gin.Context) {
 	class := c.DefaultQuery("class", "all")
@@ -66,14 +75,16 @@ func (rt *Router) HandlerGetAlertsFiring(c *gin.Context) {
 		Alerts:          make(Alerts, 0),
 	}
 
-	// TODO: 测试阶段使用固定地址（注意修正为有效查询串）
-	server, _ := url.Parse("http://192.168.2.35:9093/api/v2/alerts?active=true&inhibited=false&silenced=false&unprocessed=false")
-	rt.amClient.SetClient(nil, server, rt.logger)
-
-	// 拉取当前 firing 报警
-	alertsFromAlertmanager, err := rt.amClient.GetActiveAlerts(c.Request.Context())
+	// 拉取当前 firing 报警, 集群未配置报警平台地址时使用默认地址
+	alertsFromAlertmanager, err := rt.amClient.GetActiveAlerts(c.Request.Context(), middleware.GetCluster(c).AlertmanagerURL)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Results: results, Detail: "无法从报警平台获取报警数据"})
+		if errors.Is(err, alertmanager.ErrNoServer) {
+			c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Results: results, Detail: "集群未配置报警平台地址"})
+			return
+		}
+		status, resp := apperror.Render(err, "无法从报警平台获取报警数据")
+		resp.Results = results
+		c.JSON(status, resp)
 		return
 	}
 
@@ -87,12 +98,12 @@ func (rt *Router) HandlerGetAlertsFiring(c *gin.Context) {
 		for _, alert := range alertsFromAlertmanager {
 			alertClass, ok := alert.Labels["class"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 			alertSeverity, ok := alert.Labels["severity"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 
@@ -131,12 +142,12 @@ func (rt *Router) HandlerGetAlertsFiring(c *gin.Context) {
 		for _, alert := range alertsFromAlertmanager {
 			alertClass, ok := alert.Labels["class"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 			alertSeverity, ok := alert.Labels["severity"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 
@@ -163,12 +174,12 @@ func (rt *Router) HandlerGetAlertsFiring(c *gin.Context) {
 		for _, alert := range alertsFromAlertmanager {
 			alertClass, ok := alert.Labels["class"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 			alertSeverity, ok := alert.Labels["severity"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 
@@ -195,12 +206,12 @@ func (rt *Router) HandlerGetAlertsFiring(c *gin.Context) {
 		for _, alert := range alertsFromAlertmanager {
 			alertClass, ok := alert.Labels["class"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('class') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 			alertSeverity, ok := alert.Labels["severity"]
 			if !ok {
-				rt.logger.Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
+				middleware.Logger(c).Warn("ignore to statistic a alert, because of label('severity') noexist", "fingerprint", alert.Fingerprint)
 				continue
 			}
 
@@ -272,20 +283,20 @@ func (rt *Router) HandlerGetAlertsHistory(c *gin.Context) {
 	start := c.DefaultQuery("start", time.Now().Add(-1*time.Hour).Format(time.RFC3339Nano))
 	from, err := time.Parse(time.RFC3339Nano, start)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "start 参数格式错误"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "start 参数格式错误"})
 		return
 	}
 	end := c.DefaultQuery("end", time.Now().Format(time.RFC3339Nano))
 	to, err := time.Parse(time.RFC3339Nano, end)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "end 参数格式错误"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "end 参数格式错误"})
 		return
 	}
 	status := c.QueryArray("status")
 	for _, value := range status {
 		v := strings.ToLower(strings.TrimSpace(value))
-		if v != "firing" || v != "resolved" {
-			c.JSON(http.StatusBadRequest, response.Response{Detail: "status 值字符串错误"})
+		if v != "firing" && v != "resolved" {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "status 值字符串错误"})
 			return
 		}
 	}
@@ -294,7 +305,7 @@ func (rt *Router) HandlerGetAlertsHistory(c *gin.Context) {
 	for _, label := range labels {
 		ss := strings.Split(label, "=")
 		if len(ss) != 2 {
-			c.JSON(http.StatusBadRequest, response.Response{Detail: fmt.Sprintf("labels 参数错误: %s", label)})
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("labels 参数错误: %s", label)})
 			return
 		}
 		if _, ok := condLabels[strings.TrimSpace(ss[0])]; !ok {
@@ -308,7 +319,7 @@ func (rt *Router) HandlerGetAlertsHistory(c *gin.Context) {
 	for _, annotation := range annotations {
 		ss := strings.Split(annotation, "=")
 		if len(ss) != 2 {
-			c.JSON(http.StatusBadRequest, response.Response{Detail: fmt.Sprintf("annotations 参数错误: %s", annotation)})
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("annotations 参数错误: %s", annotation)})
 			return
 		}
 		if _, ok := condAnnotations[strings.TrimSpace(ss[0])]; !ok {
@@ -317,15 +328,15 @@ func (rt *Router) HandlerGetAlertsHistory(c *gin.Context) {
 		condAnnotations[strings.TrimSpace(ss[0])] = append(condAnnotations[strings.TrimSpace(ss[0])], ss[1])
 	}
 
-	rt.logger.Debug("query parameter", "start", start, "from", from)
-	rt.logger.Debug("query parameter", "end", end, "to", to)
-	rt.logger.Debug("query parameter", "status", status)
-	rt.logger.Debug("query parameter", "labels", strings.Join(labels, ","), "condLabels", condLabels)
-	rt.logger.Debug("query parameter", "annotations", strings.Join(annotations, ","), "condAnnotations", condAnnotations)
+	middleware.Logger(c).Debug("query parameter", "start", start, "from", from)
+	middleware.Logger(c).Debug("query parameter", "end", end, "to", to)
+	middleware.Logger(c).Debug("query parameter", "status", status)
+	middleware.Logger(c).Debug("query parameter", "labels", strings.Join(labels, ","), "condLabels", condLabels)
+	middleware.Logger(c).Debug("query parameter", "annotations", strings.Join(annotations, ","), "condAnnotations", condAnnotations)
 
 	alerts, total, err := rt.db.GetAlerts(c.Request.Context(), from, to, status, condLabels, condAnnotations, pq.Page, pq.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "数据查询异常"})
+		c.JSON(apperror.Render(err, "数据查询异常"))
 		return
 	}
 	var prev, next url.URL
@@ -333,8 +344,14 @@ func (rt *Router) HandlerGetAlertsHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, response.Response{Count: total, Previous: prev, Next: next, Results: alerts})
 }
 
+// bmcCredential 返回集群配置的 BMC 账号, 未配置时为空, 由 exec.Client 使用默认账号.
+func bmcCredential(c *gin.Context) exec.Credential {
+	cl := middleware.GetCluster(c)
+	return exec.Credential{User: cl.BMCUser, Password: cl.BMCPassword}
+}
+
 type UpperQuery struct {
-	RMUIP string  `json:"rmu_ip" binding:"required"`
+	RMUIP string  `json:"rmu_ip"` // RMU IP, 未指定时使用集群默认 RMU
 	BMUIP string  `json:"bmu_ip" binding:"required"`
 	ID    string  `json:"id" binding:"required"`
 	UNC   float32 `json:"unc" binding:"required"`
@@ -348,27 +365,38 @@ type UpperQuery struct {
 // @Tags 报警, 带外
 // @Accept json
 // @Produce json
+// @Param cluster query string false "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU"
 // @Param data body UpperQuery true "设置参数"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/alerts/outband/setting/sensor/upper/thresholds [post]
 func (rt *Router) HandlerSetUpperThreshsOfOutbandSensor(ctx *gin.Context) {
 	var uq UpperQuery
 	if err := ctx.ShouldBindJSON(&uq); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: err.Error()})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	if uq.RMUIP == "" {
+		uq.RMUIP = middleware.GetCluster(ctx).RMUAddr
+	}
+	if uq.RMUIP == "" {
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing rmu_ip and no default rmu for cluster"})
 		return
 	}
 
-	if err := rt.execClient.SetUpperThreshsOfOutbandSensor(ctx.Request.Context(), uq.RMUIP, uq.BMUIP, uq.ID, fmt.Sprintf("%f", uq.UNR), fmt.Sprintf("%f", uq.UCR), fmt.Sprintf("%f", uq.UNC)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Response{Detail: err.Error()})
+	if err := rt.execClient.SetUpperThreshsOfOutbandSensor(ctx.Request.Context(), bmcCredential(ctx), uq.RMUIP, uq.BMUIP, uq.ID, fmt.Sprintf("%f", uq.UNR), fmt.Sprintf("%f", uq.UCR), fmt.Sprintf("%f", uq.UNC)); err != nil {
+		ctx.JSON(apperror.Render(err, ""))
 		return
 	}
 	ctx.JSON(http.StatusOK, response.Response{Results: "success"})
 }
 
 type LowerQuery struct {
-	RMUIP string  `json:"rmu_ip" binding:"required"`
+	RMUIP string  `json:"rmu_ip"` // RMU IP, 未指定时使用集群默认 RMU
 	BMUIP string  `json:"bmu_ip" binding:"required"`
 	ID    string  `json:"id" binding:"required"`
 	LNC   float32 `json:"lnc" binding:"required"`
@@ -382,27 +410,38 @@ type LowerQuery struct {
 // @Tags 报警, 带外
 // @Accept json
 // @Produce json
+// @Param cluster query string false "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU"
 // @Param data body LowerQuery true "设置参数"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/alerts/outband/setting/sensor/lower/thresholds [post]
 func (rt *Router) HandlerSetLowerThreshsOfOutbandSensor(ctx *gin.Context) {
 	var lq LowerQuery
 	if err := ctx.ShouldBindJSON(&lq); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: err.Error()})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	if lq.RMUIP == "" {
+		lq.RMUIP = middleware.GetCluster(ctx).RMUAddr
+	}
+	if lq.RMUIP == "" {
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing rmu_ip and no default rmu for cluster"})
 		return
 	}
 
-	if err := rt.execClient.SetLowerThreshsOfOutbandSensor(ctx.Request.Context(), lq.RMUIP, lq.BMUIP, lq.ID, fmt.Sprintf("%f", lq.LNR), fmt.Sprintf("%f", lq.LCR), fmt.Sprintf("%f", lq.LNC)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Response{Detail: err.Error()})
+	if err := rt.execClient.SetLowerThreshsOfOutbandSensor(ctx.Request.Context(), bmcCredential(ctx), lq.RMUIP, lq.BMUIP, lq.ID, fmt.Sprintf("%f", lq.LNR), fmt.Sprintf("%f", lq.LCR), fmt.Sprintf("%f", lq.LNC)); err != nil {
+		ctx.JSON(apperror.Render(err, ""))
 		return
 	}
 	ctx.JSON(http.StatusOK, response.Response{Results: "success"})
 }
 
 type ThreshParam struct {
-	RMUIP string  `json:"rmu_ip" binding:"required"`
+	RMUIP string  `json:"rmu_ip"` // RMU IP, 未指定时使用集群默认 RMU
 	BMUIP string  `json:"bmu_ip" binding:"required"`
 	ID    string  `json:"id" binding:"required"`
 	Which string  `json:"which" binding:"required"`
@@ -415,27 +454,38 @@ type ThreshParam struct {
 // @Tags 报警, 带外
 // @Accept json
 // @Produce json
+// @Param cluster query string false "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU"
 // @Param data body ThreshParam true "设置参数"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/alerts/outband/setting/sensor/threshold [post]
 func (rt *Router) HandlerSetThreshOfOutbandSensor(ctx *gin.Context) {
 	var tq ThreshParam
 	if err := ctx.ShouldBindJSON(&tq); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: err.Error()})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	if tq.RMUIP == "" {
+		tq.RMUIP = middleware.GetCluster(ctx).RMUAddr
+	}
+	if tq.RMUIP == "" {
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing rmu_ip and no default rmu for cluster"})
 		return
 	}
 
-	if err := rt.execClient.SetThreshsOfOutbandSensor(ctx.Request.Context(), tq.RMUIP, tq.BMUIP, tq.ID, tq.Which, fmt.Sprintf("%f", tq.Value)); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Response{Detail: err.Error()})
+	if err := rt.execClient.SetThreshsOfOutbandSensor(ctx.Request.Context(), bmcCredential(ctx), tq.RMUIP, tq.BMUIP, tq.ID, tq.Which, fmt.Sprintf("%f", tq.Value)); err != nil {
+		ctx.JSON(apperror.Render(err, ""))
 		return
 	}
 	ctx.JSON(http.StatusOK, response.Response{Results: "success"})
 }
 
 type InhibitParam struct {
-	RMUIP  string `json:"rmu_ip" binding:"required"` // RMU IP
+	RMUIP  string `json:"rmu_ip"`                    // RMU IP, 未指定时使用集群默认 RMU
 	Board  string `json:"board" binding:"required"`  // 传感器编号
 	Sensor string `json:"sensor" binding:"required"` // 传感器编号或传感器名字
 	Rule   string `json:"rule" binding:"required"`   // 抑制规则
@@ -447,27 +497,38 @@ type InhibitParam struct {
 // @Tags 报警, 带外
 // @Accept json
 // @Produce json
+// @Param cluster query string false "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU"
 // @Param data body InhibitParam true "设置参数"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/alerts/outband/setting/sensor/inhibit [post]
 func (rt *Router) HandlerSetInhibitOfOutbandSensor(ctx *gin.Context) {
 	var ip InhibitParam
 	if err := ctx.ShouldBindJSON(&ip); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: err.Error()})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	if ip.RMUIP == "" {
+		ip.RMUIP = middleware.GetCluster(ctx).RMUAddr
+	}
+	if ip.RMUIP == "" {
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing rmu_ip and no default rmu for cluster"})
 		return
 	}
 
 	if err := rt.execClient.SetInhibitOfOutbandSensor(ctx.Request.Context(), ip.RMUIP, ip.Board, ip.Sensor, ip.Rule); err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Response{Detail: err.Error()})
+		ctx.JSON(apperror.Render(err, ""))
 		return
 	}
 	ctx.JSON(http.StatusOK, response.Response{Results: "success"})
 }
 
 type ThresholdQuery struct {
-	RMUIP string `form:"rmu_ip" binding:"required"`
+	RMUIP string `form:"rmu_ip"`
 	BMUIP string `form:"bmu_ip" binding:"required"`
 }
 
@@ -476,22 +537,33 @@ type ThresholdQuery struct {
 // @Description 查询指定 RMU 与 BMU 的所有带外传感器阈值信息。
 // @Tags 报警, 带外
 // @Produce json
-// @Param rmu_ip query string true "RMU IP"
+// @Param cluster query string false "集群名称, 未指定 rmu_ip 时使用该集群的默认 RMU"
+// @Param rmu_ip query string false "RMU IP"
 // @Param bmu_ip query string true "BMU IP"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/alerts/outband/sensor/thresholds [get]
 func (rt *Router) HandlerGetThreshOfOutbandSensor(ctx *gin.Context) {
 	var tq ThresholdQuery
 	if err := ctx.ShouldBindQuery(&tq); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: err.Error()})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	if tq.RMUIP == "" {
+		tq.RMUIP = middleware.GetCluster(ctx).RMUAddr
+	}
+	if tq.RMUIP == "" {
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing rmu_ip and no default rmu for cluster"})
 		return
 	}
 
-	ths, err := rt.execClient.GetThresholdOfOutbandSensor(ctx.Request.Context(), tq.RMUIP, tq.BMUIP)
+	ths, err := rt.execClient.GetThresholdOfOutbandSensor(ctx.Request.Context(), bmcCredential(ctx), tq.RMUIP, tq.BMUIP)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: err.Error()})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
 		return
 	}
 
@@ -1,11 +1,12 @@
 package alert
 
 import (
+	"csjk-bk/internal/pkg/auth"
 	"csjk-bk/internal/pkg/client/alertmanager"
 	"csjk-bk/internal/pkg/client/exec"
 	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/middleware"
 	"log/slog"
-	osexec "os/exec"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,9 +18,7 @@ type Router struct {
 	logger     *slog.Logger
 }
 
-func NewRouter(db *postgres.Client, amClient *alertmanager.Client, logger *slog.Logger) *Router {
-	execClient := &exec.Client{}
-	execClient.Set(osexec.CommandContext, logger)
+func NewRouter(db *postgres.Client, amClient *alertmanager.Client, execClient *exec.Client, logger *slog.Logger) *Router {
 	return &Router{
 		db:         db,
 		amClient:   amClient,
@@ -31,12 +30,16 @@ func NewRouter(db *postgres.Client, amClient *alertmanager.Client, logger *slog.
 func (rt *Router) Register(r *gin.Engine) {
 	v1 := r.Group("/api/v1/alerts")
 	{
-		v1.GET("/firing", rt.HandlerGetAlertsFiring)
-		v1.GET("/history", rt.HandlerGetAlertsHistory)
-		v1.POST("/outband/setting/sensor/upper/thresholds", rt.HandlerSetUpperThreshsOfOutbandSensor)
-		v1.POST("/outband/setting/sensor/lower/thresholds", rt.HandlerSetLowerThreshsOfOutbandSensor)
-		v1.POST("/outband/setting/sensor/threshold", rt.HandlerSetThreshOfOutbandSensor)
-		v1.POST("/outband/setting/sensor/inhibit", rt.HandlerSetInhibitOfOutbandSensor)
-		v1.GET("/outband/sensor/thresholds", rt.HandlerGetThreshOfOutbandSensor)
+		// 实时报警需指定集群(?cluster=xxx)以确定报警平台地址; 带外操作可通过集群获取默认 RMU.
+		required, optional := middleware.ResolveCluster(rt.db), middleware.ResolveClusterOptional(rt.db)
+		// 权限: 报警查询所有登录用户可用; 带外操作需 operator, 未指定集群时仅全局管理员可用.
+		user, operator := middleware.RequireRole(auth.ROLE_USER), middleware.RequireRole(auth.ROLE_OPERATOR)
+		v1.GET("/firing", required, user, rt.HandlerGetAlertsFiring)
+		v1.GET("/history", user, rt.HandlerGetAlertsHistory)
+		v1.POST("/outband/setting/sensor/upper/thresholds", optional, operator, rt.HandlerSetUpperThreshsOfOutbandSensor)
+		v1.POST("/outband/setting/sensor/lower/thresholds", optional, operator, rt.HandlerSetLowerThreshsOfOutbandSensor)
+		v1.POST("/outband/setting/sensor/threshold", optional, operator, rt.HandlerSetThreshOfOutbandSensor)
+		v1.POST("/outband/setting/sensor/inhibit", optional, operator, rt.HandlerSetInhibitOfOutbandSensor)
+		v1.GET("/outband/sensor/thresholds", optional, operator, rt.HandlerGetThreshOfOutbandSensor)
 	}
 }
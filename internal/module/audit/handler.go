@@ -0,0 +1,78 @@
+package audit
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/common/paging"
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerGetAuditLogs 获取修改类接口的审计日志, 按时间降序排列.
+// @Summary 获取审计日志
+// @Description 获取修改类(POST/PUT/PATCH/DELETE)接口的调用记录, 请求体中的密码等敏感字段已脱敏.
+// @Tags 审计
+// @Produce json
+// @Param start query string false "开始时间, 需符合 RFC3339 时间格式, 缺省不限制"
+// @Param end query string false "结束时间, 需符合 RFC3339 时间格式, 缺省不限制"
+// @Param actor query string false "调用者"
+// @Param route query string false "路由模板前缀, 如 /api/v1/:cluster/ldap"
+// @Param paging query bool false "是否分页" default(true)
+// @Param page query int false "页码" default(1) minimum(1)
+// @Param page_size query int false "每页数量, 最大仅支持100" default(20) minimum(1) maximum(100)
+// @Success 200 {object} response.Response{results=model.AuditLogs}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/audit [get]
+func (rt *Router) HandlerGetAuditLogs(c *gin.Context) {
+	pq := paging.PagingQuery{Paging: true}
+	if err := c.ShouldBindQuery(&pq); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	pq.SetDefaults(1, 20, 100)
+
+	var from, to time.Time
+	if start := strings.TrimSpace(c.Query("start")); start != "" {
+		t, err := time.Parse(time.RFC3339Nano, start)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "start 参数格式错误"})
+			return
+		}
+		from = t
+	}
+	if end := strings.TrimSpace(c.Query("end")); end != "" {
+		t, err := time.Parse(time.RFC3339Nano, end)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "end 参数格式错误"})
+			return
+		}
+		to = t
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "end 不能早于 start"})
+		return
+	}
+	actor := strings.TrimSpace(c.Query("actor"))
+	route := strings.TrimSpace(c.Query("route"))
+
+	list, total, err := rt.db.GetAuditLogs(c.Request.Context(), from, to, actor, route, pq.Paging, pq.Page, pq.PageSize)
+	if err != nil {
+		middleware.Logger(c).Error("unable to get audit logs", "err", err)
+		c.JSON(apperror.Render(err, "failed to query audit logs"))
+		return
+	}
+
+	var prev, next url.URL
+	if pq.Paging {
+		prev, next = response.BuildPageLinks(c.Request.URL, pq.Page, pq.PageSize, total)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: total, Previous: prev, Next: next, Results: list})
+}
@@ -0,0 +1,29 @@
+package audit
+
+import (
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/middleware"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Router struct {
+	db     *postgres.Client
+	logger *slog.Logger
+}
+
+func NewRouter(db *postgres.Client, logger *slog.Logger) *Router {
+	return &Router{db: db, logger: logger}
+}
+
+func (rt *Router) Register(r *gin.Engine) {
+	rt.logger.Debug("register audit router")
+	// 权限: 审计日志仅全局管理员可查看.
+	admin := middleware.RequireRole(auth.ROLE_ADMIN)
+	v1 := r.Group("/api/v1/audit")
+	{
+		v1.GET("", admin, rt.HandlerGetAuditLogs) // GET /api/v1/audit?start=xxx&end=xxx&actor=xxx&route=xxx&paging=xxx&page=xxx&page_size=xxx
+	}
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginParam 登录参数.
+type LoginParam struct {
+	Cluster  string `json:"cluster" binding:"required"`  // 集群名称, 使用该集群的 Ldap 校验身份
+	Username string `json:"username" binding:"required"` // Ldap 用户名
+	Password string `json:"password" binding:"required"` // 密码
+}
+
+// RefreshParam 刷新令牌参数.
+type RefreshParam struct {
+	RefreshToken string `json:"refresh_token" binding:"required"` // 登录时获取的刷新令牌
+}
+
+// HandlerLogin 登录, 使用集群 Ldap 校验用户名密码并签发令牌.
+// @Summary 登录
+// @Description 使用集群 Ldap 校验用户名密码, 成功后返回访问令牌及刷新令牌. 访问其他接口时需携带 Authorization: Bearer <access_token>.
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param data body LoginParam true "登录参数"
+// @Success 200 {object} response.Response{results=auth.TokenPair}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/auth/login [post]
+func (rt *Router) HandlerLogin(c *gin.Context) {
+	var in LoginParam
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+
+	cl, err := rt.db.GetCluster(c.Request.Context(), in.Cluster)
+	if err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_CLUSTER_NOT_FOUND), Detail: "unknown cluster: " + in.Cluster})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to resolve cluster"))
+		return
+	}
+
+	id, err := rt.verifier.Verify(c.Request.Context(), cl, in.Username, in.Password)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			middleware.Logger(c).Info("login failed", "cluster", in.Cluster, "username", in.Username)
+			c.JSON(http.StatusUnauthorized, response.Response{Code: string(apperror.CODE_UNAUTHENTICATED), Detail: "invalid username or password"})
+			return
+		}
+		middleware.Logger(c).Error("unable to verify credentials", "cluster", in.Cluster, "username", in.Username, "err", err)
+		c.JSON(apperror.Render(err, "failed to verify credentials"))
+		return
+	}
+
+	tp, err := rt.issuer.Issue(id)
+	if err != nil {
+		c.JSON(apperror.Render(err, ""))
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{Results: tp})
+}
+
+// HandlerRefresh 使用刷新令牌换取新的令牌. 刷新时重新查询用户的 Ldap 附加组, 用户已不存在时拒绝刷新.
+// @Summary 刷新令牌
+// @Description 重新查询用户在登录集群 Ldap 中的附加组, 新令牌使用最新的附加组; 用户已不存在时返回 401.
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param data body RefreshParam true "刷新令牌"
+// @Success 200 {object} response.Response{results=auth.TokenPair}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/auth/refresh [post]
+func (rt *Router) HandlerRefresh(c *gin.Context) {
+	var in RefreshParam
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+
+	id, err := rt.issuer.Parse(in.RefreshToken, auth.TOKEN_TYPE_REFRESH)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, response.Response{Code: string(apperror.CODE_UNAUTHENTICATED), Detail: "invalid or expired refresh token"})
+		return
+	}
+
+	cl, err := rt.db.GetCluster(c.Request.Context(), id.Cluster)
+	if err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.JSON(http.StatusUnauthorized, response.Response{Code: string(apperror.CODE_UNAUTHENTICATED), Detail: "cluster of refresh token no longer exists: " + id.Cluster})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to resolve cluster"))
+		return
+	}
+	// 重新查询附加组, 避免已移出 Ldap 组的用户通过刷新令牌保留权限
+	fresh, err := rt.verifier.Lookup(c.Request.Context(), cl, id.Username)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			middleware.Logger(c).Info("refresh rejected: user not found", "cluster", cl.Name, "username", id.Username)
+			c.JSON(http.StatusUnauthorized, response.Response{Code: string(apperror.CODE_UNAUTHENTICATED), Detail: "user no longer exists"})
+			return
+		}
+		middleware.Logger(c).Error("unable to lookup user", "cluster", cl.Name, "username", id.Username, "err", err)
+		c.JSON(apperror.Render(err, "failed to lookup user"))
+		return
+	}
+
+	tp, err := rt.issuer.Issue(fresh)
+	if err != nil {
+		c.JSON(apperror.Render(err, ""))
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{Results: tp})
+}
+
+// HandlerGetMe 获取当前调用者身份.
+// @Summary 当前用户
+// @Tags 认证
+// @Produce json
+// @Success 200 {object} response.Response{results=auth.Identity}
+// @Failure 401 {object} response.Response
+// @Router /api/v1/auth/me [get]
+func (rt *Router) HandlerGetMe(c *gin.Context) {
+	id, ok := middleware.GetIdentity(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, response.Response{Code: string(apperror.CODE_UNAUTHENTICATED), Detail: "not authenticated"})
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{Results: id})
+}
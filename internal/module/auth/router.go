@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/client/postgres"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Router struct {
+	db       *postgres.Client
+	verifier auth.Verifier
+	issuer   *auth.Issuer
+	logger   *slog.Logger
+}
+
+func NewRouter(db *postgres.Client, verifier auth.Verifier, issuer *auth.Issuer, logger *slog.Logger) *Router {
+	return &Router{db: db, verifier: verifier, issuer: issuer, logger: logger}
+}
+
+// PublicPaths 无需认证即可访问的接口.
+var PublicPaths = []string{
+	"/api/v1/auth/login",
+	"/api/v1/auth/refresh",
+}
+
+func (rt *Router) Register(r *gin.Engine) {
+	rt.logger.Debug("register auth router")
+	v1 := r.Group("/api/v1/auth")
+	{
+		v1.POST("/login", rt.HandlerLogin)     // POST /api/v1/auth/login
+		v1.POST("/refresh", rt.HandlerRefresh) // POST /api/v1/auth/refresh
+		v1.GET("/me", rt.HandlerGetMe)         // GET /api/v1/auth/me
+	}
+}
@@ -0,0 +1,276 @@
+package cluster
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/client/postgres/model"
+	"csjk-bk/internal/pkg/common/paging"
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClusterParam 新增/更新集群的请求参数.
+type ClusterParam struct {
+	Name              string `json:"name" binding:"required"`                                     // 集群名称, 更新时忽略
+	Description       string `json:"description"`                                                 // 描述
+	Slurmrestd        string `json:"slurmrestd" binding:"required,hostname_port"`                 // slurmrestd 服务地址(host:port)
+	SlurmrestdFlavor  string `json:"slurmrestd_flavor" binding:"omitempty,oneof=custom official"` // slurmrestd 接口类型, 默认 custom
+	SlurmrestdVersion string `json:"slurmrestd_version" binding:"omitempty,startswith=v"`         // 官方 slurmrestd 接口版本, 如 v0.0.40, 默认 v0.0.40
+	SlurmrestdUser    string `json:"slurmrestd_user"`                                             // 官方 slurmrestd 用户名(X-SLURM-USER-NAME)
+	SlurmrestdToken   string `json:"slurmrestd_token"`                                            // 官方 slurmrestd 令牌(X-SLURM-USER-TOKEN), 须为 root 或 SlurmUser 的令牌(作业以请求用户的身份提交), 更新时为空表示不修改
+	LustreServer      string `json:"lustre_server" binding:"omitempty,hostname_port"`             // KD Lustre 服务地址(host:port)
+	AlertmanagerURL   string `json:"alertmanager_url" binding:"omitempty,url"`                    // Alertmanager 地址, 如 http://host:9093
+	RMUAddr           string `json:"rmu_addr"`                                                    // 默认 RMU 地址
+	BMCUser           string `json:"bmc_user"`                                                    // 默认 BMC 用户名
+	BMCPassword       string `json:"bmc_password"`                                                // 默认 BMC 密码, 更新时为空表示不修改
+}
+
+func (p ClusterParam) toModel() model.Cluster {
+	flavor := p.SlurmrestdFlavor
+	if flavor == "" {
+		flavor = model.SLURMRESTD_FLAVOR_CUSTOM
+	}
+	return model.Cluster{
+		Name:              p.Name,
+		Description:       p.Description,
+		Slurmrestd:        p.Slurmrestd,
+		SlurmrestdFlavor:  flavor,
+		SlurmrestdVersion: p.SlurmrestdVersion,
+		SlurmrestdUser:    p.SlurmrestdUser,
+		SlurmrestdToken:   p.SlurmrestdToken,
+		LustreServer:      p.LustreServer,
+		AlertmanagerURL:   p.AlertmanagerURL,
+		RMUAddr:           p.RMUAddr,
+		BMCUser:           p.BMCUser,
+		BMCPassword:       p.BMCPassword,
+	}
+}
+
+// HandlerGetClusters 获取已注册集群列表.
+// @Summary 获取集群列表
+// @Tags 集群管理
+// @Produce json
+// @Param paging query bool false "是否分页" default(true)
+// @Param page query int false "页码" default(1) minimum(1)
+// @Param page_size query int false "每页数量, 最大仅支持100" default(20) minimum(1) maximum(100)
+// @Success 200 {object} response.Response{results=model.Clusters}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/clusters [get]
+func (rt *Router) HandlerGetClusters(c *gin.Context) {
+	pq := paging.PagingQuery{Paging: true}
+	if err := c.ShouldBindQuery(&pq); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	pq.SetDefaults(1, 20, 100)
+
+	list, total, err := rt.db.GetClusters(c.Request.Context(), pq.Paging, pq.Page, pq.PageSize)
+	if err != nil {
+		middleware.Logger(c).Error("unable to get clusters", "err", err)
+		c.JSON(apperror.Render(err, "failed to query clusters"))
+		return
+	}
+
+	var prev, next url.URL
+	if pq.Paging {
+		prev, next = response.BuildPageLinks(c.Request.URL, pq.Page, pq.PageSize, total)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: total, Previous: prev, Next: next, Results: list})
+}
+
+// HandlerGetCluster 获取某集群配置.
+// @Summary 获取集群详情
+// @Tags 集群管理
+// @Produce json
+// @Param name path string true "集群名称"
+// @Success 200 {object} response.Response{results=model.Cluster}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/clusters/{name} [get]
+func (rt *Router) HandlerGetCluster(c *gin.Context) {
+	name := c.Param("name")
+	cl, err := rt.db.GetCluster(c.Request.Context(), name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_CLUSTER_NOT_FOUND), Detail: "unknown cluster: " + name})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to query cluster"))
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{Count: 1, Results: cl})
+}
+
+// HandlerAddCluster 注册新集群.
+// @Summary 注册集群
+// @Tags 集群管理
+// @Accept json
+// @Produce json
+// @Param data body ClusterParam true "集群配置"
+// @Success 201 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/clusters [post]
+func (rt *Router) HandlerAddCluster(c *gin.Context) {
+	var in ClusterParam
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := rt.db.AddCluster(c.Request.Context(), in.toModel()); err != nil {
+		if errors.Is(err, postgres.ErrClusterExists) {
+			c.JSON(http.StatusConflict, response.Response{Code: string(apperror.CODE_CONFLICT), Detail: "cluster already exists: " + in.Name})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to add cluster"))
+		return
+	}
+	c.JSON(http.StatusCreated, response.Response{Results: "ok"})
+}
+
+// HandlerUpdateCluster 更新集群配置, 集群名称不可修改.
+// @Summary 更新集群
+// @Tags 集群管理
+// @Accept json
+// @Produce json
+// @Param name path string true "集群名称"
+// @Param data body ClusterParam true "集群配置"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/clusters/{name} [put]
+func (rt *Router) HandlerUpdateCluster(c *gin.Context) {
+	name := c.Param("name")
+	var in ClusterParam
+	// 名称以路径为准
+	in.Name = name
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := rt.db.UpdateCluster(c.Request.Context(), name, in.toModel()); err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_CLUSTER_NOT_FOUND), Detail: "unknown cluster: " + name})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to update cluster"))
+		return
+	}
+	// slurmrestd 地址或接口类型可能变化, 清除该集群的全部缓存
+	rt.backends.Invalidate(name)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+// HandlerDeleteCluster 删除集群.
+// @Summary 删除集群
+// @Tags 集群管理
+// @Produce json
+// @Param name path string true "集群名称"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/clusters/{name} [delete]
+func (rt *Router) HandlerDeleteCluster(c *gin.Context) {
+	name := c.Param("name")
+	if err := rt.db.DelCluster(c.Request.Context(), name); err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_CLUSTER_NOT_FOUND), Detail: "unknown cluster: " + name})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to delete cluster"))
+		return
+	}
+	rt.backends.Invalidate(name)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+// ClusterRolesParam 集群角色映射, 将替换集群的全部映射.
+type ClusterRolesParam struct {
+	Roles []ClusterRoleParam `json:"roles" binding:"dive"`
+}
+
+// ClusterRoleParam Ldap 组(Group)的成员在集群下拥有角色(Role).
+type ClusterRoleParam struct {
+	Group string `json:"group" binding:"required"`                                   // Ldap 组名
+	Role  string `json:"role" binding:"required,oneof=admin reviewer operator user"` // 角色
+}
+
+// HandlerGetClusterRoles 获取集群的 Ldap 组与角色映射.
+// @Summary 获取集群角色映射
+// @Tags 集群管理
+// @Produce json
+// @Param name path string true "集群名称"
+// @Success 200 {object} response.Response{results=model.ClusterRoles}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/clusters/{name}/roles [get]
+func (rt *Router) HandlerGetClusterRoles(c *gin.Context) {
+	name := c.Param("name")
+	if _, err := rt.db.GetCluster(c.Request.Context(), name); err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_CLUSTER_NOT_FOUND), Detail: "unknown cluster: " + name})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to query cluster"))
+		return
+	}
+
+	roles, err := rt.db.GetClusterRoles(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to query cluster roles"))
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{Count: len(roles), Results: roles})
+}
+
+// HandlerSetClusterRoles 设置集群的 Ldap 组与角色映射, 请求中的映射将替换原有全部映射.
+// 全局管理员由配置 auth.admin_groups 指定, 不受此映射影响.
+// @Summary 设置集群角色映射
+// @Tags 集群管理
+// @Accept json
+// @Produce json
+// @Param name path string true "集群名称"
+// @Param data body ClusterRolesParam true "角色映射"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/clusters/{name}/roles [put]
+func (rt *Router) HandlerSetClusterRoles(c *gin.Context) {
+	name := c.Param("name")
+	var in ClusterRolesParam
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+
+	roles := make(model.ClusterRoles, 0, len(in.Roles))
+	for _, r := range in.Roles {
+		roles = append(roles, model.ClusterRole{Group: r.Group, Role: r.Role})
+	}
+	if err := rt.db.SetClusterRoles(c.Request.Context(), name, roles); err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_CLUSTER_NOT_FOUND), Detail: "unknown cluster: " + name})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to set cluster roles"))
+		return
+	}
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/middleware"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Router struct {
+	db       *postgres.Client
+	backends *slurmrest.Backends
+	logger   *slog.Logger
+}
+
+func NewRouter(db *postgres.Client, backends *slurmrest.Backends, logger *slog.Logger) *Router {
+	return &Router{db: db, backends: backends, logger: logger}
+}
+
+func (rt *Router) Register(r *gin.Engine) {
+	rt.logger.Debug("register cluster router")
+	// 权限: 集群列表所有登录用户可用, 注册/修改集群及角色映射仅全局管理员可用.
+	user, admin := middleware.RequireRole(auth.ROLE_USER), middleware.RequireRole(auth.ROLE_ADMIN)
+	v1 := r.Group("/api/v1/clusters")
+	{
+		v1.GET("", user, rt.HandlerGetClusters)                  // GET /api/v1/clusters?paging=xxx&page=xxx&page_size=xxx
+		v1.GET("/:name", user, rt.HandlerGetCluster)             // GET /api/v1/clusters/:name
+		v1.POST("", admin, rt.HandlerAddCluster)                 // POST /api/v1/clusters
+		v1.PUT("/:name", admin, rt.HandlerUpdateCluster)         // PUT /api/v1/clusters/:name
+		v1.DELETE("/:name", admin, rt.HandlerDeleteCluster)      // DELETE /api/v1/clusters/:name
+		v1.GET("/:name/roles", admin, rt.HandlerGetClusterRoles) // GET /api/v1/clusters/:name/roles
+		v1.PUT("/:name/roles", admin, rt.HandlerSetClusterRoles) // PUT /api/v1/clusters/:name/roles
+	}
+}
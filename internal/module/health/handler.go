@@ -0,0 +1,142 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 依赖状态
+const (
+	STATUS_UP      = "up"
+	STATUS_DOWN    = "down"
+	STATUS_SKIPPED = "skipped" // 集群未配置该依赖
+)
+
+// 整体就绪状态
+const (
+	READINESS_READY    = "ready"    // 所有依赖可用
+	READINESS_DEGRADED = "degraded" // 数据库可用, 部分集群依赖不可用
+	READINESS_NOTREADY = "notready" // 数据库不可用
+)
+
+// Readiness 就绪检查结果.
+type Readiness struct {
+	Status       string             `json:"status"`       // ready, degraded, notready
+	Dependencies []DependencyStatus `json:"dependencies"` // 各依赖状态
+}
+
+// DependencyStatus 单个依赖的探测结果.
+type DependencyStatus struct {
+	Name      string  `json:"name"`              // 依赖名称: postgres, slurmrestd, lustre, alertmanager
+	Cluster   string  `json:"cluster,omitempty"` // 所属集群, postgres 为空
+	Target    string  `json:"target,omitempty"`  // 探测地址
+	Status    string  `json:"status"`            // up, down, skipped
+	LatencyMS float64 `json:"latency_ms"`        // 探测耗时(毫秒)
+	Error     string  `json:"error,omitempty"`   // 失败原因
+}
+
+// HandlerHealthz 存活检查, 进程能处理请求即返回 200.
+// @Summary 存活检查
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} response.Response{results=string}
+// @Router /healthz [get]
+func (rt *Router) HandlerHealthz(c *gin.Context) {
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+// HandlerReadyz 就绪检查, 探测数据库及每个已注册集群的 slurmrestd, Lustre KD 服务, Alertmanager.
+// 仅数据库不可用时返回 503, 集群依赖不可用时返回 200 且 status 为 degraded, 以区分后端整体不可用与某集群依赖不可用.
+// @Summary 就绪检查
+// @Description 探测数据库及每个已注册集群的 slurmrestd, Lustre KD 服务, Alertmanager, 返回各依赖状态及耗时.
+// @Description 仅数据库不可用时返回 503; 集群依赖不可用时返回 200, status 为 degraded.
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} response.Response{results=Readiness}
+// @Failure 503 {object} response.Response{results=Readiness}
+// @Router /readyz [get]
+func (rt *Router) HandlerReadyz(c *gin.Context) {
+	ctx := c.Request.Context()
+	result := Readiness{Status: READINESS_READY, Dependencies: make([]DependencyStatus, 0)}
+
+	db := rt.probe(ctx, "postgres", "", "", rt.db.Ping)
+	result.Dependencies = append(result.Dependencies, db)
+	if db.Status != STATUS_UP {
+		result.Status = READINESS_NOTREADY
+		c.JSON(http.StatusServiceUnavailable, response.Response{Count: len(result.Dependencies), Results: result, Detail: "database unavailable"})
+		return
+	}
+
+	clusters, _, err := rt.db.GetClusters(ctx, false, 0, 0)
+	if err != nil {
+		middleware.Logger(c).Error("unable to get clusters for readiness probe", "err", err)
+		result.Status = READINESS_NOTREADY
+		c.JSON(http.StatusServiceUnavailable, response.Response{Count: len(result.Dependencies), Results: result, Detail: "failed to query clusters: " + err.Error()})
+		return
+	}
+
+	// 并发探测各集群依赖
+	deps := make([]DependencyStatus, len(clusters)*3)
+	var wg sync.WaitGroup
+	for i, cl := range clusters {
+		probes := []struct {
+			name   string
+			target string
+			ping   func(ctx context.Context) error
+		}{
+			{"slurmrestd", cl.Slurmrestd, func(ctx context.Context) error { return rt.backends.For(cl).Ping(ctx, cl.Slurmrestd) }},
+			{"lustre", cl.LustreServer, func(ctx context.Context) error { return rt.lustreClient.Ping(ctx, cl.LustreServer) }},
+			{"alertmanager", cl.AlertmanagerURL, func(ctx context.Context) error { return rt.amClient.Ping(ctx, cl.AlertmanagerURL) }},
+		}
+		for j, p := range probes {
+			idx := i*3 + j
+			if p.target == "" {
+				deps[idx] = DependencyStatus{Name: p.name, Cluster: cl.Name, Status: STATUS_SKIPPED}
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				deps[idx] = rt.probe(ctx, p.name, cl.Name, p.target, p.ping)
+			}()
+		}
+	}
+	wg.Wait()
+
+	for _, d := range deps {
+		if d.Status == STATUS_DOWN {
+			result.Status = READINESS_DEGRADED
+		}
+	}
+	result.Dependencies = append(result.Dependencies, deps...)
+	c.JSON(http.StatusOK, response.Response{Count: len(result.Dependencies), Results: result})
+}
+
+// probe 在超时时间内执行 ping 并记录结果.
+func (rt *Router) probe(ctx context.Context, name, cluster, target string, ping func(ctx context.Context) error) DependencyStatus {
+	ctx, cancel := context.WithTimeout(middleware.WithClusterName(ctx, cluster), rt.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ping(ctx)
+	ds := DependencyStatus{
+		Name:      name,
+		Cluster:   cluster,
+		Target:    target,
+		Status:    STATUS_UP,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		ds.Status = STATUS_DOWN
+		ds.Error = err.Error()
+		rt.logger.Warn("dependency probe failed", "name", name, "cluster", cluster, "target", target, "err", err)
+	}
+	return ds
+}
@@ -0,0 +1,31 @@
+package health
+
+import (
+	"csjk-bk/internal/pkg/client/alertmanager"
+	"csjk-bk/internal/pkg/client/lustre"
+	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Router struct {
+	db           *postgres.Client
+	backends     *slurmrest.Backends
+	lustreClient *lustre.Client
+	amClient     *alertmanager.Client
+	timeout      time.Duration // 单个依赖探测超时时间
+	logger       *slog.Logger
+}
+
+func NewRouter(db *postgres.Client, backends *slurmrest.Backends, lc *lustre.Client, amClient *alertmanager.Client, timeout time.Duration, logger *slog.Logger) *Router {
+	return &Router{db: db, backends: backends, lustreClient: lc, amClient: amClient, timeout: timeout, logger: logger}
+}
+
+func (rt *Router) Register(r *gin.Engine) {
+	rt.logger.Debug("register health router")
+	r.GET("/healthz", rt.HandlerHealthz) // GET /healthz
+	r.GET("/readyz", rt.HandlerReadyz)   // GET /readyz
+}
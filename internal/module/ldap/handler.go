@@ -1,7 +1,9 @@
 package ldap
 
 import (
+	"csjk-bk/internal/pkg/apperror"
 	"csjk-bk/internal/pkg/common/paging"
+	"csjk-bk/internal/pkg/middleware"
 	"csjk-bk/internal/pkg/response"
 	"fmt"
 	"net/http"
@@ -31,24 +33,24 @@ type User struct {
 // @Param page_size query int false "每页条数" default(20)
 // @Success 200 {object} response.Response{results=[]User}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/user/list [get]
 func (rt *Router) HandlerGetUserlist(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
@@ -58,9 +60,9 @@ func (rt *Router) HandlerGetUserlist(c *gin.Context) {
 	pq.SetDefaults(1, 20, 100)
 
 	// 调用 slurmrest 获取用户列表和总数
-	items, total, err := rt.slurmrestc.GetLdapUsers(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
+	items, total, err := rt.slurmrestc(c).GetLdapUsers(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch ldap users: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch ldap users"))
 		return
 	}
 
@@ -78,7 +80,7 @@ func (rt *Router) HandlerGetUserlist(c *gin.Context) {
 			OU:            firstNonEmpty(m["ou"], m["department"], m["OU"], m["Department"]),
 		}
 		if strings.TrimSpace(name) != "" {
-			groups, err := rt.slurmrestc.GetAdditionalGroupsOfUser(c.Request.Context(), addr, name)
+			groups, err := rt.slurmrestc(c).GetAdditionalGroupsOfUser(c.Request.Context(), addr, name)
 			if err == nil {
 				u.AdditionalGroups = groups
 			} else {
@@ -122,37 +124,37 @@ type AddUser struct {
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/user [post]
 func (rt *Router) HandlerPostUser(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 接收参数
 	var in AddUser
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 
 	// 基础校验（Uid 已为 int 类型）
 	if strings.TrimSpace(in.Name) == "" || strings.TrimSpace(in.Passwd) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "uid, name and passwd are required"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "uid, name and passwd are required"})
 		return
 	}
 
@@ -183,15 +185,15 @@ func (rt *Router) HandlerPostUser(c *gin.Context) {
 	}
 
 	// 创建用户
-	if err := rt.slurmrestc.AddUser(c.Request.Context(), addr, payload); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to create ldap user: " + err.Error()})
+	if err := rt.slurmrestc(c).AddUser(c.Request.Context(), addr, payload); err != nil {
+		c.JSON(apperror.Render(err, "failed to create ldap user"))
 		return
 	}
 
 	// 设置用户的附加组（如果提供）
 	if len(in.AdditionalGroup) > 0 {
-		if err := rt.slurmrestc.AddMemberForGroups(c.Request.Context(), addr, in.Name, in.AdditionalGroup); err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "user created but failed to set additional groups: " + err.Error()})
+		if err := rt.slurmrestc(c).AddMemberForGroups(c.Request.Context(), addr, in.Name, in.AdditionalGroup); err != nil {
+			c.JSON(apperror.Render(err, "user created but failed to set additional groups"))
 			return
 		}
 	}
@@ -218,24 +220,24 @@ type UpdateUser struct {
 // @Param name path string true "用户名"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/user/:name [put]
 func (rt *Router) HandlerPutUser(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
@@ -246,14 +248,14 @@ func (rt *Router) HandlerPutUser(c *gin.Context) {
 		name = c.Param("user")
 	}
 	if strings.TrimSpace(name) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing user name in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing user name in path"})
 		return
 	}
 
 	// 解析 body
 	var in UpdateUser
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 
@@ -288,8 +290,8 @@ func (rt *Router) HandlerPutUser(c *gin.Context) {
 
 	// 先更新用户属性
 	if len(attr) > 0 {
-		if err := rt.slurmrestc.UpdateLdapUser(c.Request.Context(), addr, name, attr); err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update ldap user: " + err.Error()})
+		if err := rt.slurmrestc(c).UpdateLdapUser(c.Request.Context(), addr, name, attr); err != nil {
+			c.JSON(apperror.Render(err, "failed to update ldap user"))
 			return
 		}
 	}
@@ -304,24 +306,24 @@ func (rt *Router) HandlerPutUser(c *gin.Context) {
 // @Param name path string true "用户名"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/user/:name [delete]
 func (rt *Router) HandlerDeleteUser(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
@@ -332,13 +334,13 @@ func (rt *Router) HandlerDeleteUser(c *gin.Context) {
 		name = c.Param("user")
 	}
 	if strings.TrimSpace(name) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing user name in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing user name in path"})
 		return
 	}
 
 	// 调用 slurmrest 客户端删除用户
-	if err := rt.slurmrestc.DelLdapUser(c.Request.Context(), addr, name); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to delete ldap user: " + err.Error()})
+	if err := rt.slurmrestc(c).DelLdapUser(c.Request.Context(), addr, name); err != nil {
+		c.JSON(apperror.Render(err, "failed to delete ldap user"))
 		return
 	}
 
@@ -363,24 +365,24 @@ type GroupListItem struct {
 // @Param page_size query int false "每页条数" default(20)
 // @Success 200 {object} response.Response{results=GroupList}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/group/list [get]
 func (rt *Router) HandlerGetGroupList(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
@@ -393,9 +395,9 @@ func (rt *Router) HandlerGetGroupList(c *gin.Context) {
 	pq.SetDefaults(1, 20, 100)
 
 	// 调用 slurmrest 获取组列表
-	items, total, err := rt.slurmrestc.GetLdapGroups(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
+	items, total, err := rt.slurmrestc(c).GetLdapGroups(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch ldap groups: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch ldap groups"))
 		return
 	}
 
@@ -444,35 +446,35 @@ type AddGroup struct {
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/group [post]
 func (rt *Router) HandlerAddGroup(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 解析 body
 	var in AddGroup
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 	if strings.TrimSpace(in.Name) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "group name is required"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "group name is required"})
 		return
 	}
 
@@ -486,8 +488,8 @@ func (rt *Router) HandlerAddGroup(c *gin.Context) {
 	}
 
 	// 调用 slurmrest 新增用户组
-	if err := rt.slurmrestc.AddGroup(c.Request.Context(), addr, payload); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to add ldap group: " + err.Error()})
+	if err := rt.slurmrestc(c).AddGroup(c.Request.Context(), addr, payload); err != nil {
+		c.JSON(apperror.Render(err, "failed to add ldap group"))
 		return
 	}
 
@@ -506,38 +508,38 @@ type UpdateGroup struct {
 // @Param name path string true "用户组名称"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/group/:name [put]
 func (rt *Router) HandlerUpdateGroup(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 路径中的组名
 	name := c.Param("name")
 	if strings.TrimSpace(name) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing group name in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing group name in path"})
 		return
 	}
 
 	// 解析 body
 	var in UpdateGroup
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 
@@ -552,12 +554,12 @@ func (rt *Router) HandlerUpdateGroup(c *gin.Context) {
 
 	// 若没有任何属性需要更新，直接返回
 	if len(attr) == 0 {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "no attributes to update"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "no attributes to update"})
 		return
 	}
 
-	if err := rt.slurmrestc.UpdateLdapGroup(c.Request.Context(), addr, name, attr); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update ldap group: " + err.Error()})
+	if err := rt.slurmrestc(c).UpdateLdapGroup(c.Request.Context(), addr, name, attr); err != nil {
+		c.JSON(apperror.Render(err, "failed to update ldap group"))
 		return
 	}
 
@@ -571,37 +573,37 @@ func (rt *Router) HandlerUpdateGroup(c *gin.Context) {
 // @Param name path string true "用户组名称"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/ldap/group/:name [delete]
 func (rt *Router) HandlerDeleteGroup(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 解析组名
 	name := c.Param("name")
 	if strings.TrimSpace(name) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing group name in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing group name in path"})
 		return
 	}
 
 	// 删除组
-	if err := rt.slurmrestc.DelLdapGroup(c.Request.Context(), addr, name); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to delete ldap group: " + err.Error()})
+	if err := rt.slurmrestc(c).DelLdapGroup(c.Request.Context(), addr, name); err != nil {
+		c.JSON(apperror.Render(err, "failed to delete ldap group"))
 		return
 	}
 
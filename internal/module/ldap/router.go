@@ -1,38 +1,47 @@
 package ldap
 
 import (
+	"csjk-bk/internal/pkg/auth"
 	"csjk-bk/internal/pkg/client/postgres"
 	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/middleware"
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
-	db         *postgres.Client
-	slurmrestc *slurmrest.Client
-	logger     *slog.Logger
+	db       *postgres.Client
+	backends *slurmrest.Backends
+	logger   *slog.Logger
 }
 
-func NewRouter(db *postgres.Client, slurmrestc *slurmrest.Client, logger *slog.Logger) *Router {
+func NewRouter(db *postgres.Client, backends *slurmrest.Backends, logger *slog.Logger) *Router {
 	return &Router{
-		db:         db,
-		slurmrestc: slurmrestc,
-		logger:     logger,
+		db:       db,
+		backends: backends,
+		logger:   logger,
 	}
 }
 
 func (rt *Router) Register(r *gin.Engine) {
 	rt.logger.Debug("register ldap router")
-	v1 := r.Group("/api/v1/:cluster/ldap")
+	// 权限: 运维人员可查看 Ldap 用户及组, 仅管理员可修改.
+	operator, admin := middleware.RequireRole(auth.ROLE_OPERATOR), middleware.RequireRole(auth.ROLE_ADMIN)
+	v1 := r.Group("/api/v1/:cluster/ldap", middleware.ResolveCluster(rt.db))
 	{
-		v1.GET("/user/list", rt.HandlerGetUserlist)      // GET /api/v1/:cluster/ldap/user/list
-		v1.POST("/user", rt.HandlerPostUser)             // POST /api/v1/:cluster/ldap/user
-		v1.PUT("/user/:name", rt.HandlerPutUser)         // PUT /api/v1/:cluster/ldap/user/:name
-		v1.DELETE("/user/:name", rt.HandlerDeleteUser)   // DELETE /api/v1/:cluster/ldap/user/:name
-		v1.GET("/group/list", rt.HandlerGetGroupList)    // GET /api/v1/:cluster/ldap/group/list
-		v1.POST("/group", rt.HandlerAddGroup)            // POST /api/v1/:cluster/ldap/group
-		v1.PUT("/group/:name", rt.HandlerUpdateGroup)    // PUT /api/v1/:cluster/ldap/group/:name
-		v1.DELETE("/group/:name", rt.HandlerDeleteGroup) // DELETE /api/v1/:cluster/ldap/group/:name
+		v1.GET("/user/list", operator, rt.HandlerGetUserlist)   // GET /api/v1/:cluster/ldap/user/list
+		v1.POST("/user", admin, rt.HandlerPostUser)             // POST /api/v1/:cluster/ldap/user
+		v1.PUT("/user/:name", admin, rt.HandlerPutUser)         // PUT /api/v1/:cluster/ldap/user/:name
+		v1.DELETE("/user/:name", admin, rt.HandlerDeleteUser)   // DELETE /api/v1/:cluster/ldap/user/:name
+		v1.GET("/group/list", operator, rt.HandlerGetGroupList) // GET /api/v1/:cluster/ldap/group/list
+		v1.POST("/group", admin, rt.HandlerAddGroup)            // POST /api/v1/:cluster/ldap/group
+		v1.PUT("/group/:name", admin, rt.HandlerUpdateGroup)    // PUT /api/v1/:cluster/ldap/group/:name
+		v1.DELETE("/group/:name", admin, rt.HandlerDeleteGroup) // DELETE /api/v1/:cluster/ldap/group/:name
 	}
 }
+
+// slurmrestc 返回当前集群(由 ResolveCluster 解析)接口类型对应的 slurmrestd 客户端.
+func (rt *Router) slurmrestc(c *gin.Context) slurmrest.Backend {
+	return rt.backends.For(middleware.GetCluster(c))
+}
@@ -1,10 +1,14 @@
 package lustre
 
 import (
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/auth"
 	"csjk-bk/internal/pkg/common/paging"
 	"csjk-bk/internal/pkg/common/time"
+	"csjk-bk/internal/pkg/middleware"
 	"csjk-bk/internal/pkg/response"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -61,7 +65,11 @@ type UserQuota struct {
 // @Param page_size query int false "每页条数" default(20)
 // @Success 200 {object} response.Response{results=UserQuotaList}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quotas [get]
 func (rt *Router) HandlerGetQuotas(c *gin.Context) {
 	// Summary: 获取某集群 Lustre 配额信息（支持按用户和分页）
@@ -74,17 +82,13 @@ func (rt *Router) HandlerGetQuotas(c *gin.Context) {
 
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
-	addr, err := rt.db.GetLustreServer(cluster)
-	if err != nil || strings.TrimSpace(addr) == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).LustreServer
+	if strings.TrimSpace(addr) == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty lustre server address for cluster"})
 		return
 	}
 
@@ -97,18 +101,14 @@ func (rt *Router) HandlerGetQuotas(c *gin.Context) {
 	users := c.QueryArray("user")
 	if len(users) == 0 {
 		// Fetch all users without paging
-		addr, err := rt.db.GetSlurmrestdAddr(cluster)
-		if err != nil || addr == "" {
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-			} else {
-				c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-			}
+		addr := middleware.GetCluster(c).Slurmrestd
+		if addr == "" {
+			c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 			return
 		}
-		items, _, err := rt.slurmrestc.GetLdapUsers(c.Request.Context(), addr, false, 0, 0)
+		items, _, err := rt.slurmrestc(c).GetLdapUsers(c.Request.Context(), addr, false, 0, 0)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch ldap users: " + err.Error()})
+			c.JSON(apperror.Render(err, "failed to fetch ldap users"))
 			return
 		}
 		for _, m := range items {
@@ -121,7 +121,7 @@ func (rt *Router) HandlerGetQuotas(c *gin.Context) {
 	// Get mounts and defaults
 	mounts, err := rt.lustreClient.GetMounts(c.Request.Context(), addr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch lustre mounts: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch lustre mounts"))
 		return
 	}
 	// Build mount list by key LUSTRE_MOUNTED
@@ -245,13 +245,17 @@ func max(a, b int) int {
 // @Param body body UserQuota true "配额参数（需包含 filesystem，其他字段留空表示不更新）"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/lustre/:user/quota [put]
 func (rt *Router) HandlerUpdateUserQuota(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
@@ -262,29 +266,25 @@ func (rt *Router) HandlerUpdateUserQuota(c *gin.Context) {
 		user = c.Param("name")
 	}
 	if strings.TrimSpace(user) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing user in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing user in path"})
 		return
 	}
 
 	// 解析 body
 	var in UserQuota
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 	mount := strings.TrimSpace(in.FileSystem)
 	if mount == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "filesystem is required"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "filesystem is required"})
 		return
 	}
 
-	addr, err := rt.db.GetLustreServer(cluster)
-	if err != nil || strings.TrimSpace(addr) == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).LustreServer
+	if strings.TrimSpace(addr) == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty lustre server address for cluster"})
 		return
 	}
 
@@ -309,12 +309,12 @@ func (rt *Router) HandlerUpdateUserQuota(c *gin.Context) {
 
 	// 若未提供任何限额参数，视为无可更新内容
 	if len(parts) == 5 { // 仅基础 5 段: lfs setquota -u 'user' 'mount'
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "no quota limits to update"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "no quota limits to update"})
 		return
 	}
 
 	if err := rt.lustreClient.Control(c.Request.Context(), addr, cmdSet); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update user quota: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to update user quota"))
 		return
 	}
 
@@ -333,7 +333,7 @@ func (rt *Router) HandlerUpdateUserQuota(c *gin.Context) {
 	// 	p2 = append(p2, fmt.Sprintf("'%s'", mount))
 	// 	cmdGrace := strings.Join(p2, " ")
 	// 	if err := rt.lustreClient.Control(c.Request.Context(), addr, cmdGrace); err != nil {
-	// 		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update grace: " + err.Error()})
+	// 		c.JSON(apperror.Render(err, "failed to update grace"))
 	// 		return
 	// 	}
 	// }
@@ -358,36 +358,36 @@ func (rt *Router) HandlerUpdateUserQuota(c *gin.Context) {
 // @Param body body UserQuota true "默认配额（仅使用 filesystem 与限额字段，user 忽略）"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quota [put]
 func (rt *Router) HandlerUpdateQuota(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 解析 body
 	var in UserQuota
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 	mount := strings.TrimSpace(in.FileSystem)
 	if mount == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "filesystem is required"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "filesystem is required"})
 		return
 	}
 
 	// 解析转发地址
-	addr, err := rt.db.GetLustreServer(cluster)
-	if err != nil || strings.TrimSpace(addr) == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).LustreServer
+	if strings.TrimSpace(addr) == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty lustre server address for cluster"})
 		return
 	}
 
@@ -408,12 +408,12 @@ func (rt *Router) HandlerUpdateQuota(c *gin.Context) {
 	}
 	parts = append(parts, fmt.Sprintf("%s", mount))
 	if len(parts) == base+1 { // 仅有挂载点，无任何限额参数
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "no quota limits to update"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "no quota limits to update"})
 		return
 	}
 	cmdSet := strings.Join(parts, " ")
 	if err := rt.lustreClient.Control(c.Request.Context(), addr, cmdSet); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update default quota: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to update default quota"))
 		return
 	}
 
@@ -431,7 +431,7 @@ func (rt *Router) HandlerUpdateQuota(c *gin.Context) {
 		p2 = append(p2, fmt.Sprintf("%s", mount))
 		cmdGrace := strings.Join(p2, " ")
 		if err := rt.lustreClient.Control(c.Request.Context(), addr, cmdGrace); err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update default grace: " + err.Error()})
+			c.JSON(apperror.Render(err, "failed to update default grace"))
 			return
 		}
 	}
@@ -467,7 +467,7 @@ var ApplicationStateMaps = map[int]string{
 //   - 根据返回的数据构造QuotaApplicationList, 其中 Application.content 对应解析为 UserQuota.
 //
 // @Summary 获取配额申请列表
-// @Description 获取配额申请列表，支持按申请人筛选与分页；Actual 字段依据 KD 查询当前实际配额并以默认值兜底
+// @Description 获取路径中集群的配额申请列表，支持按申请人筛选与分页；Actual 字段依据 KD 查询当前实际配额并以默认值兜底
 // @Tags 资源管理, 存储管理
 // @Produce json
 // @Param cluster path string true "集群名称" example("test")
@@ -477,13 +477,16 @@ var ApplicationStateMaps = map[int]string{
 // @Param page_size query int false "每页条数" default(20)
 // @Success 200 {object} response.Response{results=QuotaApplicationList}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quota/applications [get]
 func (rt *Router) HandlerGetQuotaApps(c *gin.Context) {
 	// 1) 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
@@ -494,22 +497,22 @@ func (rt *Router) HandlerGetQuotaApps(c *gin.Context) {
 
 	// 3) 解析 aid，缺省或 -1 表示全部
 	applier := strings.TrimSpace(c.Query("name"))
+	// 普通用户仅能查看本人的申请
+	if !middleware.HasRole(c, auth.ROLE_REVIEWER) {
+		applier = middleware.Username(c)
+	}
 
 	// 4) 查询申请数据（含总数）
-	apps, total, err := rt.db.GetApplications(c.Request.Context(), dbpg.APPLICATION_CLASS_QUOTA, applier, pq.Paging, pq.Page, pq.PageSize)
+	apps, total, err := rt.db.GetApplications(c.Request.Context(), dbpg.APPLICATION_CLASS_QUOTA, middleware.GetCluster(c).Name, applier, pq.Paging, pq.Page, pq.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch quota applications: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch quota applications"))
 		return
 	}
 
 	// 5) 解析内容并补充 Actual（需要 slurmrestd 转发 KD）
-	addr, err := rt.db.GetLustreServer(cluster)
-	if err != nil || strings.TrimSpace(addr) == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).LustreServer
+	if strings.TrimSpace(addr) == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty lustre server address for cluster"})
 		return
 	}
 
@@ -579,32 +582,37 @@ func (rt *Router) HandlerGetQuotaApps(c *gin.Context) {
 // @Param id path int true "申请ID"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quota/application/{id}/decision [get]
 func (rt *Router) HandlerGetQuotaAppDecision(c *gin.Context) {
 	// 解析 cluster（保持路径规范一致）
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 解析申请 ID
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
 		return
 	}
 
+	if !rt.checkApplicationOwner(c, id) {
+		return
+	}
 	// 调用数据库获取审核结果
 	decision, err := rt.db.GetApplicationDecision(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch application decision: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch application decision"))
 		return
 	}
 
@@ -620,7 +628,7 @@ func (rt *Router) HandlerGetQuotaAppDecision(c *gin.Context) {
 //     构造 postgres.Application 时, applyid = aid, content=json.Marshal(userquota), state = APPLICATION_STATE_REVIEWING
 //   - 返回响应.
 //
-// @Description 参数 cluster 为保留参数, 当前整体设计就不支持多集群.
+// @Description 申请属于路径中的集群, 仅该集群的审核人可审核.
 // @Summary 提交配额申请
 // @Tags 资源管理, 存储管理
 // @Produce json
@@ -628,37 +636,44 @@ func (rt *Router) HandlerGetQuotaAppDecision(c *gin.Context) {
 // @Param body body UserQuota true "申请内容（用户配额信息）"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quota/application [post]
 func (rt *Router) HandlerCreateQuotaApplication(c *gin.Context) {
 	// 解析 cluster（保留参数）
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 解析申请内容（用户配额）
 	var in UserQuota
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 	b, err := json.Marshal(in)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to marshal application content: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to marshal application content"))
 		return
 	}
 
 	// 构造申请并入库
+	// 申请人取自登录身份, 未启用认证时使用申请内容中的用户
+	applier := middleware.Username(c)
+	if applier == "" {
+		applier = in.User
+	}
 	app := dbpg.Application{
-		Applier: in.User,
+		Applier: applier,
 		Class:   dbpg.APPLICATION_CLASS_QUOTA,
+		Cluster: middleware.GetCluster(c).Name,
 		State:   dbpg.APPLICATION_STATE_REVIEWING,
 		Content: string(b),
 	}
 	if err := rt.db.AddApplication(c.Request.Context(), app); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to add application: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to add application"))
 		return
 	}
 
@@ -681,43 +696,48 @@ func (rt *Router) HandlerCreateQuotaApplication(c *gin.Context) {
 // @Param body body UserQuota true "申请内容（用户配额信息）"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quota/application/{id} [put]
 func (rt *Router) HandlerUpdateQuotaApplication(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 解析申请 ID
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
 		return
 	}
 
+	if !rt.checkApplicationOwner(c, id) {
+		return
+	}
 	// 解析请求体
 	var in UserQuota
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 	b, err := json.Marshal(in)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to marshal application content: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to marshal application content"))
 		return
 	}
 
 	// 更新申请
 	if err := rt.db.UpdateApplication(c.Request.Context(), id, string(b)); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update application: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to update application"))
 		return
 	}
 
@@ -738,36 +758,77 @@ func (rt *Router) HandlerUpdateQuotaApplication(c *gin.Context) {
 // @Param id path int true "申请ID"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quota/application/{id} [delete]
 func (rt *Router) HandleDelQuotaApplication(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 解析申请 ID
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
 		return
 	}
 
+	if !rt.checkApplicationOwner(c, id) {
+		return
+	}
 	if err := rt.db.DelApplication(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to delete application: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to delete application"))
 		return
 	}
 
 	c.JSON(http.StatusOK, response.Response{Results: "ok"})
 }
 
+// checkApplicationOwner 校验申请(id)为路径中集群的 APPLICATION_CLASS_QUOTA 类申请且属于调用者, reviewer 可访问该集群的全部申请.
+// 校验失败时写入响应并返回 false.
+func (rt *Router) checkApplicationOwner(c *gin.Context, id int) bool {
+	app, ok := rt.getQuotaApplication(c, id)
+	if !ok {
+		return false
+	}
+	if middleware.HasRole(c, auth.ROLE_REVIEWER) {
+		return true
+	}
+	if app.Applier != middleware.Username(c) {
+		c.JSON(http.StatusForbidden, response.Response{Code: string(apperror.CODE_PERMISSION_DENIED), Detail: "permission denied: not the applier of the application"})
+		return false
+	}
+	return true
+}
+
+// getQuotaApplication 获取申请(id), 申请不存在, 不是 APPLICATION_CLASS_QUOTA 类申请或不属于路径中的集群时
+// 写入 404 响应并返回 false. 角色按集群分配, 需在角色校验之前调用.
+func (rt *Router) getQuotaApplication(c *gin.Context, id int) (dbpg.Application, bool) {
+	app, err := rt.db.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, dbpg.ErrApplicationNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_APPLICATION_NOT_FOUND), Detail: fmt.Sprintf("application not found: id=%d", id)})
+			return app, false
+		}
+		c.JSON(apperror.Render(err, "failed to fetch application"))
+		return app, false
+	}
+	if app.Class != dbpg.APPLICATION_CLASS_QUOTA || !app.InCluster(middleware.GetCluster(c).Name) {
+		c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_APPLICATION_NOT_FOUND), Detail: fmt.Sprintf("application not found: id=%d", id)})
+		return app, false
+	}
+	return app, true
+}
+
 type Review struct {
 	Approve   bool   `json:"approve"`  // 是否通过审核
 	Descision string `json:"decision"` // 审核意见
@@ -786,7 +847,7 @@ type Review struct {
 //   - db.DoQuotaReview 更新数据库中的申请数据. 若 approve == true, state = APPLICATION_STATE_PASSED. approve==false, state = APPLICATION_STATE_REJECTED
 //
 // @Summary 审核配额申请
-// @Description 根据审核结果执行实际配额配置（通过时）并更新申请的审核状态与内容
+// @Description 根据审核结果执行实际配额配置（通过时）并更新申请的审核状态与内容; 申请不属于路径中的集群时返回 404
 // @Tags 资源管理, 存储管理
 // @Produce json
 // @Param cluster path string true "集群名称" example("test")
@@ -794,32 +855,39 @@ type Review struct {
 // @Param body body Review true "审核结果与配额内容"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/:cluster/lustre/quota/application/{id}/review [post]
 func (rt *Router) HandlerPostQuotaAppReview(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 解析申请 ID
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
 		return
 	}
 
 	// 解析审核请求体
 	var in Review
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	if _, ok := rt.getQuotaApplication(c, id); !ok {
 		return
 	}
 
@@ -828,17 +896,13 @@ func (rt *Router) HandlerPostQuotaAppReview(c *gin.Context) {
 		user := strings.TrimSpace(in.User)
 		mount := strings.TrimSpace(in.FileSystem)
 		if user == "" || mount == "" {
-			c.JSON(http.StatusBadRequest, response.Response{Detail: "user and filesystem are required when approve=true"})
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "user and filesystem are required when approve=true"})
 			return
 		}
 
-		addr, err := rt.db.GetLustreServer(cluster)
-		if err != nil || strings.TrimSpace(addr) == "" {
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-			} else {
-				c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-			}
+		addr := middleware.GetCluster(c).LustreServer
+		if strings.TrimSpace(addr) == "" {
+			c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty lustre server address for cluster"})
 			return
 		}
 
@@ -862,7 +926,7 @@ func (rt *Router) HandlerPostQuotaAppReview(c *gin.Context) {
 
 		if len(parts) > 5 { // 有更新项才执行（基础段为5）
 			if err := rt.lustreClient.Control(c.Request.Context(), addr, cmdSet); err != nil {
-				c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to apply quota: " + err.Error()})
+				c.JSON(apperror.Render(err, "failed to apply quota"))
 				return
 			}
 		}
@@ -882,7 +946,7 @@ func (rt *Router) HandlerPostQuotaAppReview(c *gin.Context) {
 			p2 = append(p2, fmt.Sprintf("'%s'", mount))
 			cmdGrace := strings.Join(p2, " ")
 			if err := rt.lustreClient.Control(c.Request.Context(), addr, cmdGrace); err != nil {
-				c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to apply grace: " + err.Error()})
+				c.JSON(apperror.Render(err, "failed to apply grace"))
 				return
 			}
 		}
@@ -896,11 +960,11 @@ func (rt *Router) HandlerPostQuotaAppReview(c *gin.Context) {
 	// 仅存储申请的配额内容（不含 Approve 字段）
 	contentBytes, err := json.Marshal(in.UserQuota)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to marshal review content: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to marshal review content"))
 		return
 	}
-	if err := rt.db.DoReview(c.Request.Context(), id, decision, in.Descision, string(contentBytes)); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update review: " + err.Error()})
+	if err := rt.db.DoReview(c.Request.Context(), id, decision, middleware.Username(c), in.Descision, string(contentBytes)); err != nil {
+		c.JSON(apperror.Render(err, "failed to update review"))
 		return
 	}
 
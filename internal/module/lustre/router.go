@@ -1,9 +1,11 @@
 package lustre
 
 import (
+	"csjk-bk/internal/pkg/auth"
 	"csjk-bk/internal/pkg/client/lustre"
 	"csjk-bk/internal/pkg/client/postgres"
 	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/middleware"
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
@@ -11,26 +13,35 @@ import (
 
 type Router struct {
 	db           *postgres.Client
-	slurmrestc   *slurmrest.Client
+	backends     *slurmrest.Backends
 	lustreClient *lustre.Client
 	logger       *slog.Logger
 }
 
-func NewRouter(db *postgres.Client, slurmrestc *slurmrest.Client, lc *lustre.Client, logger *slog.Logger) *Router {
-	return &Router{db: db, slurmrestc: slurmrestc, lustreClient: lc, logger: logger}
+func NewRouter(db *postgres.Client, backends *slurmrest.Backends, lc *lustre.Client, logger *slog.Logger) *Router {
+	return &Router{db: db, backends: backends, lustreClient: lc, logger: logger}
 }
 
 func (rt *Router) Register(r *gin.Engine) {
-	v1 := r.Group("/api/v1/:cluster/lustre")
+	// 权限: 配额申请所有登录用户可用(handler 中限制普通用户仅能操作本人的申请), 审核申请需 reviewer;
+	// 查看配额需 reviewer 或 operator, 直接修改配额仅管理员可用.
+	user, reviewer := middleware.RequireRole(auth.ROLE_USER), middleware.RequireRole(auth.ROLE_REVIEWER)
+	staff, admin := middleware.RequireRole(auth.ROLE_REVIEWER, auth.ROLE_OPERATOR), middleware.RequireRole(auth.ROLE_ADMIN)
+	v1 := r.Group("/api/v1/:cluster/lustre", middleware.ResolveCluster(rt.db))
 	{
-		v1.GET("/quotas", rt.HandlerGetQuotas)                                   // GET /api/v1/:cluster/lustre/quotas?user=xxx&userxxx&paging=xxx&page=xxx&page_size=xxx
-		v1.PUT("/:user/quota", rt.HandlerUpdateUserQuota)                        // PUT /api/v1/:cluster/lustre/:user/quota
-		v1.PUT("/quota", rt.HandlerUpdateQuota)                                  // PUT /api/v1/:cluster/lustre/quota
-		v1.GET("/quota/applications", rt.HandlerGetQuotaApps)                    // GET /api/v1/:cluster/lustre/quota/applications?user_id=xxx
-		v1.GET("/quota/application/:id/decision", rt.HandlerGetQuotaAppDecision) // GET /api/v1/:cluster/lustre/quota/application/:id/decision
-		v1.POST("/quota/application", rt.HandlerCreateQuotaApplication)          // POST /api/v1/:cluster/lustre/quota/application/:id
-		v1.PUT("/quota/application/:id", rt.HandlerUpdateQuotaApplication)       // PUT /api/v1/:cluster/lustre/quota/application/:id
-		v1.DELETE("/quota/application/:id", rt.HandleDelQuotaApplication)        // DELETE /api/v1/:cluster/lustre/quota/application/:id
-		v1.POST("/quota/application/:id/review", rt.HandlerPostQuotaAppReview)   // POST /api/v1/:cluster/lustre/quota/application/:id/review
+		v1.GET("/quotas", staff, rt.HandlerGetQuotas)                                    // GET /api/v1/:cluster/lustre/quotas?user=xxx&userxxx&paging=xxx&page=xxx&page_size=xxx
+		v1.PUT("/:user/quota", admin, rt.HandlerUpdateUserQuota)                         // PUT /api/v1/:cluster/lustre/:user/quota
+		v1.PUT("/quota", admin, rt.HandlerUpdateQuota)                                   // PUT /api/v1/:cluster/lustre/quota
+		v1.GET("/quota/applications", user, rt.HandlerGetQuotaApps)                      // GET /api/v1/:cluster/lustre/quota/applications?user_id=xxx
+		v1.GET("/quota/application/:id/decision", user, rt.HandlerGetQuotaAppDecision)   // GET /api/v1/:cluster/lustre/quota/application/:id/decision
+		v1.POST("/quota/application", user, rt.HandlerCreateQuotaApplication)            // POST /api/v1/:cluster/lustre/quota/application/:id
+		v1.PUT("/quota/application/:id", user, rt.HandlerUpdateQuotaApplication)         // PUT /api/v1/:cluster/lustre/quota/application/:id
+		v1.DELETE("/quota/application/:id", user, rt.HandleDelQuotaApplication)          // DELETE /api/v1/:cluster/lustre/quota/application/:id
+		v1.POST("/quota/application/:id/review", reviewer, rt.HandlerPostQuotaAppReview) // POST /api/v1/:cluster/lustre/quota/application/:id/review
 	}
 }
+
+// slurmrestc 返回当前集群(由 ResolveCluster 解析)接口类型对应的 slurmrestd 客户端.
+func (rt *Router) slurmrestc(c *gin.Context) slurmrest.Backend {
+	return rt.backends.For(middleware.GetCluster(c))
+}
@@ -2,16 +2,25 @@ package slurm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/auth"
 	dbpg "csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
 	"csjk-bk/internal/pkg/common/paging"
 	"csjk-bk/internal/pkg/common/slurm"
+	"csjk-bk/internal/pkg/common/slurm/hostlist"
+	"csjk-bk/internal/pkg/common/slurm/tres"
 	"csjk-bk/internal/pkg/common/time"
+	"csjk-bk/internal/pkg/middleware"
 	"csjk-bk/internal/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -34,47 +43,46 @@ type Overview struct {
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=Overview}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/overview [get]
 func (rt *Router) HandlerGetOverview(c *gin.Context) {
 	// 获取路径参数 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 查询 cluster 对应的服务地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	ctx := c.Request.Context()
 	// 获取节点(不分页)
-	nodes, _, err := rt.slurmrestc.GetNodes(ctx, addr, nil, false, 0, 0)
+	nodes, _, err := rt.slurmrestc(c).GetNodes(ctx, addr, nil, false, 0, 0)
 	if err != nil {
-		rt.logger.Error("unable to get nodes information", "err", err)
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "无法获取节点信息数据"})
+		middleware.Logger(c).Error("unable to get nodes information", "err", err)
+		c.JSON(apperror.Render(err, "无法获取节点信息数据"))
 		return
 	}
 
-	_, runningCount, err := rt.slurmrestc.GetSchedulingJobs(ctx, addr, true, 1, 1)
+	_, runningCount, err := rt.slurmrestc(c).GetSchedulingJobs(ctx, addr, model.SchedulingJobFilter{}, true, 1, 1)
 	if err != nil {
-		rt.logger.Error("unable to get job information from slurm scheduling", "err", err)
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "无法获取运行作业数据"})
+		middleware.Logger(c).Error("unable to get job information from slurm scheduling", "err", err)
+		c.JSON(apperror.Render(err, "无法获取运行作业数据"))
 		return
 	}
 
-	_, totalCount, err := rt.slurmrestc.GetAccountingJobs(ctx, addr, true, 1, 1)
+	_, totalCount, err := rt.slurmrestc(c).GetJobsFromAccounting(ctx, addr, model.JobFilter{}, true, 1, 1)
 	if err != nil {
-		rt.logger.Error("unable to get job information from slurm accouting", "err", err)
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "无法获取账户系统中作业信息"})
+		middleware.Logger(c).Error("unable to get job information from slurm accouting", "err", err)
+		c.JSON(apperror.Render(err, "无法获取账户系统中作业信息"))
 		return
 	}
 
@@ -98,7 +106,7 @@ func (rt *Router) HandlerGetOverview(c *gin.Context) {
 		Cores:       cores,
 		Mems:        mems,
 		RunningJobs: runningCount,
-		TotalJobs:   totalCount,
+		TotalJobs:   int64(totalCount),
 	}
 	c.JSON(http.StatusOK, response.Response{Results: ov})
 }
@@ -134,14 +142,17 @@ type PartitionListItem struct {
 // @Param page_size query int false "每页数量" example("20") default(20) minimum(1) maximum(100)
 // @Success 200 {object} response.Response{results=PartitionsList}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/partition/list [get]
 func (rt *Router) HandlerGetPartitionList(c *gin.Context) {
 	list := make(PartitionsList, 0)
 	// 获取路径参数 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
@@ -150,21 +161,17 @@ func (rt *Router) HandlerGetPartitionList(c *gin.Context) {
 	pq.SetDefaults(1, 20, 100)
 
 	// 查询 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 获取 Partition List
-	partitions, total, err := rt.slurmrestc.GetPartitions(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
+	partitions, total, err := rt.slurmrestc(c).GetPartitions(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
 	if err != nil {
-		rt.logger.Error("unable to get all partitions information", "err", err)
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "无法获取 Partition 元数据"})
+		middleware.Logger(c).Error("unable to get all partitions information", "err", err)
+		c.JSON(apperror.Render(err, "无法获取 Partition 元数据"))
 		return
 	}
 
@@ -228,37 +235,36 @@ type PartitionDetail struct {
 // @Param name path string true "分区名称" example("p1")
 // @Success 200 {object} response.Response{results=PartitionDetail}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/partition/{name}/detail [get]
 func (rt *Router) HandlerGetPartitionDetail(c *gin.Context) {
 	// 参数解析
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	name := c.Param("name")
 	if strings.TrimSpace(name) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing required query: partition"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing required query: partition"})
 		return
 	}
 
 	// 查询 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
-	partition, err := rt.slurmrestc.GetPartitionByName(c.Request.Context(), addr, name)
+	partition, err := rt.slurmrestc(c).GetPartitionByName(c.Request.Context(), addr, name)
 	if err != nil {
 		// TODO 存在分区不存在的情况也会报这个错误.
-		rt.logger.Error("unable to get partition informatio", "err", err)
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "无法获取分区详情"})
+		middleware.Logger(c).Error("unable to get partition informatio", "err", err)
+		c.JSON(apperror.Render(err, "无法获取分区详情"))
 		return
 	}
 
@@ -310,6 +316,7 @@ type LdapUser struct {
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=LdapUsers}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/ldap/users [get]
 func (rt *Router) HandlerGetLdapUsers(c *gin.Context) {}
@@ -320,6 +327,7 @@ func (rt *Router) HandlerGetLdapUsers(c *gin.Context) {}
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/ldap/user [post]
 func (rt *Router) HandlerCreateLdapUser(c *gin.Context) {}
@@ -330,6 +338,7 @@ func (rt *Router) HandlerCreateLdapUser(c *gin.Context) {}
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/ldap/user [put]
 func (rt *Router) HandlerUpdateLdapUser(c *gin.Context) {}
@@ -340,6 +349,7 @@ func (rt *Router) HandlerUpdateLdapUser(c *gin.Context) {}
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/ldap/user [delete]
 func (rt *Router) HandlerDeleteLdapUser(c *gin.Context) {}
@@ -350,6 +360,7 @@ func (rt *Router) HandlerDeleteLdapUser(c *gin.Context) {}
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=AccountNameList}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/accounting/accounts/name [get]
 func (rt *Router) HandlerGetAccountingAcctsname(c *gin.Context) {}
@@ -367,6 +378,7 @@ type LdapGroup struct {
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=LdapGroups}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/ldap/groups [get]
 func (rt *Router) HandlerGetLdapGroups(c *gin.Context) {}
@@ -377,6 +389,7 @@ func (rt *Router) HandlerGetLdapGroups(c *gin.Context) {}
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/ldap/group [post]
 func (rt *Router) HandlerPostLdapGroups(c *gin.Context) {}
@@ -387,6 +400,7 @@ func (rt *Router) HandlerPostLdapGroups(c *gin.Context) {}
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/ldap/group [delete]
 func (rt *Router) HandlerDeleteLdapGroups(c *gin.Context) {}
@@ -394,83 +408,133 @@ func (rt *Router) HandlerDeleteLdapGroups(c *gin.Context) {}
 type JobListOfScheduling []JobListItemOfScheduling
 
 type JobListItemOfScheduling struct {
-	Jobid     string `json:"jobid"`     // 作业 ID
-	State     string `json:"state"`     // 状态
-	User      string `json:"user"`      // 账户 user(account)
-	CPUs      string `json:"cpus"`      // 资源个数
-	Nodelist  string `json:"nodelist"`  // 节点列表
-	Partition string `json:"partition"` // 分区
-	QoS       string `json:"qos"`       // QoS
-	Reason    string `json:"reason"`    // 原因
+	Jobid      string `json:"jobid"`       // 作业 ID
+	State      string `json:"state"`       // 状态
+	User       string `json:"user"`        // 账户 user(account)
+	CPUs       string `json:"cpus"`        // 资源个数
+	Nodelist   string `json:"nodelist"`    // 节点列表
+	Partition  string `json:"partition"`   // 分区
+	QoS        string `json:"qos"`         // QoS
+	Reason     string `json:"reason"`      // 原因
+	Priority   int64  `json:"priority"`    // 优先级
+	TimeSubmit int64  `json:"time_submit"` // 提交时间(Unix 时间戳)
+}
+
+// SchedulingJobQueue 调度队列作业列表及统计, 统计范围为满足查询条件的全部作业(不限于当前页).
+type SchedulingJobQueue struct {
+	Jobs    JobListOfScheduling `json:"jobs"`    // 作业列表
+	States  map[string]int      `json:"states"`  // 各状态的作业数, 始终包含 PENDING, RUNNING, COMPLETING
+	Reasons map[string]int      `json:"reasons"` // 排队(PENDING)作业按原因统计的作业数, 如 {"Resources": 120, "QOSMaxJobsPerUserLimit": 40}
 }
 
 // HandlerGetSchedulingJobList 获取某集群调度队列中的作业列表
 // @Summary 获取某集群调度列表中作业列表
-// @Description 返回当前调度队列中的作业列表，可选分页参数
+// @Description 返回当前调度队列中的作业列表及各状态, 各排队原因的作业数, 支持按用户, 账户, 分区, QoS, 状态, 原因及节点过滤, 列表条件以逗号分隔, 匹配其中任一值.
+// @Description 作业数统计满足查询条件的全部作业, 不受分页影响. 调度队列在服务端缓存数秒(见配置 cache.ttl.jobs), 提交及控制作业后清除.
 // @Tags 资源管理, 作业管理
 // @Produce json
 // @Param cluster path string true "集群名称" example("test")
-// @Param paging query bool false "是否开启分页, 当前仅支持true" default(true)
+// @Param paging query bool false "是否开启分页" default(true)
 // @Param page query int false "页号(从1开始)" example("1") default(1) minimum(1)
 // @Param page_size query int false "每页数量" example("20") default(20) minimum(1)
-// @Success 200 {object} response.Response{results=JobListOfScheduling}
+// @Param user query string false "用户名" example("alice,bob")
+// @Param account query string false "账户" example("physics")
+// @Param partition query string false "分区" example("gpu")
+// @Param qos query string false "QoS 名称" example("normal")
+// @Param state query string false "状态, 大小写不敏感" example("PENDING")
+// @Param reason query string false "原因, 大小写不敏感" example("Resources,Priority")
+// @Param node query string false "节点, 支持 hostlist 表达式, 返回分配的节点包含其中任一节点的作业" example("cn[01-04]")
+// @Param sort query string false "排序字段: priority, submit, jobid, 前缀 - 表示降序" example("-priority")
+// @Success 200 {object} response.Response{results=SchedulingJobQueue}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/scheduling/job/list [get]
 func (rt *Router) HandlerGetSchedulingJobList(c *gin.Context) {
-	list := make(JobListOfScheduling, 0)
+	queue := SchedulingJobQueue{
+		Jobs:    make(JobListOfScheduling, 0),
+		States:  map[string]int{"PENDING": 0, "RUNNING": 0, "COMPLETING": 0},
+		Reasons: make(map[string]int),
+	}
 
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Results: list, Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Results: queue, Detail: "missing cluster in path"})
 		return
 	}
 
-	// 解析分页参数
+	// 解析分页参数, 未指定 paging 时默认分页
 	var pq paging.PagingQuery
 	_ = c.ShouldBindQuery(&pq)
 	pq.SetDefaults(1, 20, 100)
-	pq.Paging = true
+	if c.Query("paging") == "" {
+		pq.Paging = true
+	}
+
+	// 解析查询条件
+	var sq SchedulingJobQuery
+	if err := c.ShouldBindQuery(&sq); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Results: queue, Detail: "invalid query: " + err.Error()})
+		return
+	}
+	filter, detail := sq.filter()
+	if detail != "" {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Results: queue, Detail: detail})
+		return
+	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Results: list, Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Results: list, Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Results: queue, Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
-	// 查询调度作业
-	items, total, err := rt.slurmrestc.GetSchedulingJobs(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
+	// 查询满足条件的全部调度作业, 统计后在本地分页
+	items, _, err := rt.slurmrestc(c).GetSchedulingJobs(c.Request.Context(), addr, filter, false, 0, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Results: list, Detail: "failed to fetch scheduling jobs: " + err.Error()})
+		status, resp := apperror.Render(err, "failed to fetch scheduling jobs")
+		resp.Results = queue
+		c.JSON(status, resp)
 		return
 	}
 
 	for _, item := range items {
-		// 获取 QoS 名称，失败则回退为 ID
-		list = append(list, JobListItemOfScheduling{
-			Jobid:     item.Jobid,
-			State:     item.State,
-			User:      fmt.Sprintf("%d(%s)", item.User, item.Account),
-			CPUs:      item.CPUs,
-			Nodelist:  item.Nodelist,
-			Partition: item.Partition,
-			QoS:       item.QoS,
-			Reason:    item.Reason,
+		queue.States[item.State]++
+		if item.State == "PENDING" && item.Reason != "" {
+			queue.Reasons[item.Reason]++
+		}
+	}
+
+	total := len(items)
+	if pq.Paging {
+		lo := min((pq.Page-1)*pq.PageSize, total)
+		items = items[lo:min(lo+pq.PageSize, total)]
+	}
+	for _, item := range items {
+		queue.Jobs = append(queue.Jobs, JobListItemOfScheduling{
+			Jobid:      item.Jobid,
+			State:      item.State,
+			User:       fmt.Sprintf("%s(%s)", item.User, item.Account),
+			CPUs:       item.CPUs,
+			Nodelist:   item.Nodelist,
+			Partition:  item.Partition,
+			QoS:        item.QoS,
+			Reason:     item.Reason,
+			Priority:   item.Priority,
+			TimeSubmit: item.TimeSubmit,
 		})
 	}
 
 	var prev, next url.URL
 	if pq.Paging {
-		prev, next = response.BuildPageLinks(c.Request.URL, pq.Page, pq.PageSize, int(total))
+		prev, next = response.BuildPageLinks(c.Request.URL, pq.Page, pq.PageSize, total)
 	}
 
-	c.JSON(http.StatusOK, response.Response{Count: int(total), Previous: prev, Next: next, Results: list})
+	c.JSON(http.StatusOK, response.Response{Count: total, Previous: prev, Next: next, Results: queue})
 }
 
 // @Summary 获取某集群调度列表中某作业详情
@@ -480,36 +544,35 @@ func (rt *Router) HandlerGetSchedulingJobList(c *gin.Context) {
 // @Param jobid path int true "作业号" example("1")
 // @Success 200 {object} response.Response{results=model.JobsStepsInScheduling}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/scheduling/job/{jobid}/detail [get]
 func (rt *Router) HandlerGetSchedulingJobsDetail(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	jobid := c.Param("jobid")
 	if jobid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing jobid in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing jobid in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
-	detail, err := rt.slurmrestc.GetJobStepsOfScheduling(c.Request.Context(), addr, jobid)
+	detail, err := rt.slurmrestc(c).GetJobStepsOfScheduling(c.Request.Context(), addr, jobid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: fmt.Sprintf("%s", err)})
+		c.JSON(apperror.Render(err, ""))
 		return
 	}
 
@@ -518,27 +581,53 @@ func (rt *Router) HandlerGetSchedulingJobsDetail(c *gin.Context) {
 
 type JobListOfAccounting []JobListItem
 type JobListItem struct {
-	JobID     uint32 `json:"jobid"`      // 作业ID
-	State     string `json:"state"`      // 状态
-	Account   string `json:"account"`    // 账号
-	TresAlloc string `json:"tres_alloc"` // 资源个数
-	Nodelist  string `json:"nodelist"`   // 节点列表
-	Partition string `json:"partition"`  // 分区
-	QoS       string `json:"qos"`        // QoS
-	Reason    string `json:"reason"`     // 原因
+	JobID      uint32 `json:"jobid"`       // 作业ID
+	Name       string `json:"name"`        // 作业名称
+	State      string `json:"state"`       // 状态
+	Account    string `json:"account"`     // 账号
+	TresAlloc  string `json:"tres_alloc"`  // 资源个数
+	Nodelist   string `json:"nodelist"`    // 节点列表
+	Partition  string `json:"partition"`   // 分区
+	QoS        string `json:"qos"`         // QoS
+	Reason     string `json:"reason"`      // 原因
+	ExitCode   uint32 `json:"exit_code"`   // 退出码
+	TimeSubmit int64  `json:"time_submit"` // 提交时间(Unix 时间戳)
+	TimeStart  int64  `json:"time_start"`  // 开始时间(Unix 时间戳), 0 表示未开始
+	TimeEnd    int64  `json:"time_end"`    // 结束时间(Unix 时间戳), 0 表示未结束
+
+	TresResources map[string]tres.Resources `json:"tres_resources"` // tres_alloc 的结构化表示, 键为 tres_alloc
 }
 
 // @Summary 获取某集群账户中作业列表
-// @Description 返回账户历史作业列表，可选分页参数
+// @Description 返回账户历史作业列表, 支持按用户, 账户, 分区, QoS, 状态, 时间范围, 作业名称, 退出码及节点过滤, 列表条件以逗号分隔, 匹配其中任一值.
+// @Description 时间参数支持 Unix 时间戳, YYYY-MM-DD[THH:MM[:SS]] 及 now+count[seconds|minutes|hours|days|weeks].
 // @Tags 资源管理, 作业管理
 // @Produce json
 // @Param cluster path string true "集群名称" example("test")
 // @Param paging query bool false "是否开启分页, 当前仅支持true" default(true)
 // @Param page query int false "页号(从1开始)" example("1") default(1) minimum(1)
 // @Param page_size query int false "每页数量" example("20") default(20) minimum(1)
+// @Param user query string false "用户名" example("alice,bob")
+// @Param account query string false "账户" example("physics")
+// @Param partition query string false "分区" example("gpu")
+// @Param qos query string false "QoS 名称" example("normal")
+// @Param state query string false "状态, 大小写不敏感" example("COMPLETED,FAILED")
+// @Param submit_from query string false "提交时间下限" example("2025-01-01")
+// @Param submit_to query string false "提交时间上限" example("2025-01-31T23:59:59")
+// @Param start_from query string false "开始时间下限"
+// @Param start_to query string false "开始时间上限"
+// @Param end_from query string false "结束时间下限"
+// @Param end_to query string false "结束时间上限"
+// @Param name query string false "作业名称, 支持 * 及 ? 通配符" example("train-*")
+// @Param exit_code query int false "退出码" example("0")
+// @Param node query string false "节点, 支持 hostlist 表达式" example("cn[01-04]")
+// @Param sort query string false "排序字段: jobid, submit, start, end, 前缀 - 表示降序" example("-submit")
 // @Success 200 {object} response.Response{results=JobListOfAccounting}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/accounting/job/list [get]
 func (rt *Router) HandlerGetJobListFromAccounting(c *gin.Context) {
 	list := make(JobListOfAccounting, 0)
@@ -554,48 +643,58 @@ func (rt *Router) HandlerGetJobListFromAccounting(c *gin.Context) {
 	_ = c.ShouldBindQuery(&pq)
 	pq.SetDefaults(1, 20, 100)
 	pq.Paging = true
+
+	// 解析查询条件
+	var jq AccountingJobQuery
+	if err := c.ShouldBindQuery(&jq); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Results: list, Detail: "invalid query: " + err.Error()})
+		return
+	}
+	filter, detail := jq.filter()
+	if detail != "" {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Results: list, Detail: detail})
+		return
+	}
+
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Results: list, Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Results: list, Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Results: list, Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 查询作业
-	items, total, err := rt.slurmrestc.GetJobsFromAccounting(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
+	items, total, err := rt.slurmrestc(c).GetJobsFromAccounting(c.Request.Context(), addr, filter, pq.Paging, pq.Page, pq.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Results: list, Detail: "failed to fetch accounting jobs: " + err.Error()})
+		status, resp := apperror.Render(err, "failed to fetch accounting jobs")
+		resp.Results = list
+		c.JSON(status, resp)
 		return
 	}
 
+	table := rt.tresTable(c)
 	for _, item := range items {
-		if qosName, err := rt.slurmrestc.GetQos(c.Request.Context(), addr, item.IDQOS); err != nil {
-			list = append(list, JobListItem{
-				JobID:     item.IDJob,
-				State:     slurm.PrintJobStateString(item.State),
-				Account:   item.Account,
-				TresAlloc: item.TresAlloc,
-				Nodelist:  item.Nodelist,
-				Partition: item.Partition,
-				QoS:       fmt.Sprintf("%d", item.IDQOS),
-				Reason:    slurm.PrintJobStateReasonStr(item.StateReasonPrev),
-			})
-		} else {
-			list = append(list, JobListItem{
-				JobID:     item.IDJob,
-				State:     slurm.PrintJobStateString(item.State),
-				Account:   item.Account,
-				TresAlloc: item.TresAlloc,
-				Nodelist:  item.Nodelist,
-				Partition: item.Partition,
-				QoS:       qosName.Name,
-				Reason:    slurm.PrintJobStateReasonStr(item.StateReasonPrev),
-			})
+		job := JobListItem{
+			JobID:      item.IDJob,
+			Name:       item.JobName,
+			State:      slurm.PrintJobStateString(item.State),
+			Account:    item.Account,
+			TresAlloc:  item.TresAlloc,
+			Nodelist:   item.Nodelist,
+			Partition:  item.Partition,
+			QoS:        fmt.Sprintf("%d", item.IDQOS),
+			Reason:     slurm.PrintJobStateReasonStr(item.StateReasonPrev),
+			ExitCode:   item.ExitCode,
+			TimeSubmit: int64(item.TimeSubmit),
+			TimeStart:  int64(item.TimeStart),
+			TimeEnd:    int64(item.TimeEnd),
+
+			TresResources: tresResources(table, map[string]string{"tres_alloc": item.TresAlloc}),
+		}
+		if qosName, err := rt.slurmrestc(c).GetQos(c.Request.Context(), addr, item.IDQOS); err == nil {
+			job.QoS = qosName.Name
 		}
+		list = append(list, job)
 	}
 
 	var prev, next url.URL
@@ -616,6 +715,8 @@ type DetailOfJobFromAccounting struct {
 type StepOfJobFromAccounting struct {
 	Name  string `json:"name"`  // 作业步名称
 	State string `json:"state"` // 作业步状态
+
+	TresResources map[string]tres.Resources `json:"tres_resources"` // 各任务 TRES 用量的结构化表示, 键为 tres_usage_{in,out}_{min,max,ave,tot}, 为空的字段不包含; cpu 为 CPU 时间(秒), mem_mb 为内存(MB)
 }
 
 type MetadataOfJobFromAccounting struct {
@@ -630,35 +731,38 @@ type MetadataOfJobFromAccounting struct {
 	NodesAlloc uint32 `json:"nodes_alloc"` // 节点数
 	Partition  string `json:"partition"`   // 分区
 	QoS        string `json:"qos"`         // QoS 名称
+	TresAlloc  string `json:"tres_alloc"`  // 已分配资源
+	TresReq    string `json:"tres_req"`    // 申请资源
+
+	TresResources map[string]tres.Resources `json:"tres_resources"` // tres_alloc 及 tres_req 的结构化表示, 键为字段名, 为空的字段不包含
 }
 
 // HandlerGetAccountingJobDetail 获取某集群账户中某作业详情
 // @Summary 获取某集群账户中某作业详情
-// @Description 返回作业基础元信息与所有作业步列表
+// @Description 返回作业基础元信息与所有作业步列表, 作业步包含各任务 TRES 用量(最小, 最大, 平均及总量)的结构化表示
 // @Tags 资源管理, 作业管理
 // @Produce json
 // @Param cluster path string true "集群名称" example("test")
 // @Param jobid path int true "作业号" example("1")
 // @Success 200 {object} response.Response{results=DetailOfJobFromAccounting}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/accounting/job/{jobid}/detail [get]
 func (rt *Router) HandlerGetAccountingJobDetail(c *gin.Context) {
 	// 解析 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
@@ -666,35 +770,46 @@ func (rt *Router) HandlerGetAccountingJobDetail(c *gin.Context) {
 	jobidStr := c.Param("jobid")
 	jobid64, err := strconv.ParseUint(jobidStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid jobid in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid jobid in path"})
 		return
 	}
 	jobid := uint32(jobid64)
 
 	// 查询作业与步骤
-	job, err := rt.slurmrestc.GetJobFromAccounting(c.Request.Context(), addr, jobid)
+	job, err := rt.slurmrestc(c).GetJobFromAccounting(c.Request.Context(), addr, jobid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch job from accounting: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch job from accounting"))
 		return
 	}
-	steps, err := rt.slurmrestc.GetStepsOfJobFromAccounting(c.Request.Context(), addr, jobid)
+	steps, err := rt.slurmrestc(c).GetStepsOfJobFromAccounting(c.Request.Context(), addr, jobid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch steps of job from accounting: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch steps of job from accounting"))
 		return
 	}
 
 	// 构造 steps 结果
+	table := rt.tresTable(c)
 	stepsOut := make([]StepOfJobFromAccounting, 0, len(steps))
 	for _, s := range steps {
 		stepsOut = append(stepsOut, StepOfJobFromAccounting{
 			Name:  s.StepName,
 			State: slurm.PrintJobStateString(s.State),
+			TresResources: tresUsage(table, map[string]string{
+				"tres_usage_in_min":  s.TRESUsageInMin,
+				"tres_usage_in_max":  s.TRESUsageInMax,
+				"tres_usage_in_ave":  s.TRESUsageInAve,
+				"tres_usage_in_tot":  s.TRESUsageInTot,
+				"tres_usage_out_min": s.TRESUsageOutMin,
+				"tres_usage_out_max": s.TRESUsageOutMax,
+				"tres_usage_out_ave": s.TRESUsageOutAve,
+				"tres_usage_out_tot": s.TRESUsageOutTot,
+			}),
 		})
 	}
 
 	// QoS 名称查询，失败则回退为 ID 字符串
 	qosName := fmt.Sprintf("%d", job.IDQOS)
-	if q, e := rt.slurmrestc.GetQos(c.Request.Context(), addr, job.IDQOS); e == nil && q.Name != "" {
+	if q, e := rt.slurmrestc(c).GetQos(c.Request.Context(), addr, job.IDQOS); e == nil && q.Name != "" {
 		qosName = q.Name
 	}
 
@@ -715,6 +830,10 @@ func (rt *Router) HandlerGetAccountingJobDetail(c *gin.Context) {
 			NodesAlloc: job.NodesAlloc,
 			Partition:  job.Partition,
 			QoS:        qosName,
+			TresAlloc:  job.TresAlloc,
+			TresReq:    job.TresReq,
+
+			TresResources: tresResources(table, map[string]string{"tres_alloc": job.TresAlloc, "tres_req": job.TresReq}),
 		},
 	}
 
@@ -722,18 +841,24 @@ func (rt *Router) HandlerGetAccountingJobDetail(c *gin.Context) {
 }
 
 var ApplicationStateMaps = map[int]string{
-	dbpg.APPLICATION_STATE_PASSED:    "通过",
-	dbpg.APPLICATION_STATE_REJECTED:  "拒绝",
-	dbpg.APPLICATION_STATE_REVIEWING: "审核中",
+	dbpg.APPLICATION_STATE_PASSED:           "通过",
+	dbpg.APPLICATION_STATE_REJECTED:         "拒绝",
+	dbpg.APPLICATION_STATE_REVIEWING:        "审核中",
+	dbpg.APPLICATION_STATE_PASSED_UNSUCCESS: "通过, 但预约创建失败",
+	dbpg.APPLICATION_STATE_ACTIVE:           "生效中",
+	dbpg.APPLICATION_STATE_EXPIRED:          "已到期",
+	dbpg.APPLICATION_STATE_CANCELLED:        "已取消",
 }
 
 type ApplicationList []Application
 type Application struct {
-	ID       int       `json:"id"`
-	State    string    `json:"state"`     // 审核状态
-	ApplyAt  time.Time `json:"apply_at"`  // 申请日期
-	ReviewAt time.Time `json:"review_at"` // 审核日期
-	Decision string    `json:"decision"`  // 审核结果
+	ID             int       `json:"id"`
+	Cluster        string    `json:"cluster"`          // 申请的集群
+	State          string    `json:"state"`            // 申请状态, 通过后由后台依次变更为生效中, 已到期或已取消
+	ApplyAt        time.Time `json:"apply_at"`         // 申请日期
+	ReviewAt       time.Time `json:"review_at"`        // 审核日期
+	StateChangedAt time.Time `json:"state_changed_at"` // 后台最近一次变更状态的时间
+	Decision       string    `json:"decision"`         // 审核结果
 	ApplicationContent
 }
 type ApplicationContent struct {
@@ -756,7 +881,7 @@ type ApplicationContent struct {
 //
 // 执行流程:
 //   - 解析参数;
-//   - 根据参数调用 db.GetApplications 申请类型为 APPLICATION_CLASS_RESOURCE. Application.Content 对应 ApplicationContent;
+//   - 根据参数调用 db.GetApplications 申请类型为 APPLICATION_CLASS_RESOURCE, 仅返回路径中集群的申请. Application.Content 对应 ApplicationContent;
 //   - 构造返回响应;
 //
 // @Summary 获取资源预约申请列表
@@ -769,33 +894,40 @@ type ApplicationContent struct {
 // @Param page_size query int false "每页条数" default(20)
 // @Success 200 {object} response.Response{results=ApplicationList}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/reservation/applications [get]
 func (rt *Router) HandlerGetReservationApps(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	var pq paging.PagingQuery
 	_ = c.ShouldBindQuery(&pq)
 	pq.SetDefaults(1, 20, 100)
 	applier := strings.TrimSpace(c.Query("applier"))
+	// 普通用户仅能查看本人的申请
+	if !middleware.HasRole(c, auth.ROLE_REVIEWER) {
+		applier = middleware.Username(c)
+	}
 
-	apps, total, err := rt.db.GetApplications(c.Request.Context(), dbpg.APPLICATION_CLASS_RESOURCE, applier, pq.Paging, pq.Page, pq.PageSize)
+	apps, total, err := rt.db.GetApplications(c.Request.Context(), dbpg.APPLICATION_CLASS_RESOURCE, middleware.GetCluster(c).Name, applier, pq.Paging, pq.Page, pq.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch reservation applications: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch reservation applications"))
 		return
 	}
 
 	list := make(ApplicationList, 0, len(apps))
 	for _, a := range apps {
 		item := Application{
-			ID:       a.ID,
-			State:    ApplicationStateMaps[a.State],
-			ApplyAt:  time.Time(a.ApplyAt),
-			ReviewAt: time.Time(a.ReviewAt),
-			Decision: a.Decision,
+			ID:             a.ID,
+			Cluster:        a.Cluster,
+			State:          ApplicationStateMaps[a.State],
+			ApplyAt:        time.Time(a.ApplyAt),
+			ReviewAt:       time.Time(a.ReviewAt),
+			StateChangedAt: time.Time(a.StateChangedAt),
+			Decision:       a.Decision,
 		}
 		if strings.TrimSpace(a.Content) != "" {
 			var ac ApplicationContent
@@ -815,7 +947,7 @@ func (rt *Router) HandlerGetReservationApps(c *gin.Context) {
 // HandlerGetApplicationDecision 获取某个申请的审查结果
 // API: GET /api/v1/:cluster/slurm/reservation/application/:id/decision
 // 请求参数解释:
-//   - cluster: 对应某个集群的申请, 该字段不会被使用仅为了保证路径命名一致性. 其可靠性由查询保证.
+//   - cluster: 申请所属的集群, 与申请的集群不一致时视为申请不存在(集群为空的历史申请除外).
 //   - id: 申请ID, 对应数据库中 Application.ID 字段
 //
 // 执行流程：
@@ -830,27 +962,32 @@ func (rt *Router) HandlerGetReservationApps(c *gin.Context) {
 // @Param id path int true "申请ID"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/reservation/application/{id}/decision [get]
 func (rt *Router) HandlerGetApplicationDecision(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
+		return
+	}
+	if !rt.checkApplicationOwner(c, id) {
 		return
 	}
 	dec, err := rt.db.GetApplicationDecision(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch application decision: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch application decision"))
 		return
 	}
 	c.JSON(http.StatusOK, response.Response{Results: dec})
@@ -872,32 +1009,39 @@ func (rt *Router) HandlerGetApplicationDecision(c *gin.Context) {
 // @Param body body ApplicationContent true "申请内容"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/reservation/application [post]
 func (rt *Router) HandlerCreateApplication(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	var in ApplicationContent
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 	b, err := json.Marshal(in)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to marshal application content: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to marshal application content"))
 		return
 	}
+	// 申请人取自登录身份, 未启用认证时使用申请内容中的用户
+	applier := middleware.Username(c)
+	if applier == "" {
+		applier = in.User
+	}
 	app := dbpg.Application{
 		Class:   dbpg.APPLICATION_CLASS_RESOURCE,
+		Cluster: cluster,
 		State:   dbpg.APPLICATION_STATE_REVIEWING,
-		Applier: in.User,
+		Applier: applier,
 		Content: string(b),
 	}
 	if err := rt.db.AddApplication(c.Request.Context(), app); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to add application: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to add application"))
 		return
 	}
 	c.JSON(http.StatusOK, response.Response{Results: "ok"})
@@ -906,7 +1050,7 @@ func (rt *Router) HandlerCreateApplication(c *gin.Context) {
 // HandlerCreateApplication 更新申请
 // API: /api/v1/:cluster/slurm/reservation/application/:id
 // 请求参数解释:
-//   - cluster: 对应某个集群的申请, 该字段不会被使用仅为了保证路径命名一致性. 其可靠性由查询保证.
+//   - cluster: 申请所属的集群, 与申请的集群不一致时视为申请不存在(集群为空的历史申请除外).
 //   - id: 申请ID, 对应数据库中 Application.ID 字段
 //
 // 请求体参数: ApplicationContent
@@ -925,36 +1069,41 @@ func (rt *Router) HandlerCreateApplication(c *gin.Context) {
 // @Param body body ApplicationContent true "申请内容"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/reservation/application/{id} [put]
 func (rt *Router) HandlerUpdateApplication(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
+		return
+	}
+	if !rt.checkApplicationOwner(c, id) {
 		return
 	}
 	var in ApplicationContent
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
 	b, err := json.Marshal(in)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to marshal application content: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to marshal application content"))
 		return
 	}
 	if err := rt.db.UpdateApplication(c.Request.Context(), id, string(b)); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update application: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to update application"))
 		return
 	}
 	c.JSON(http.StatusOK, response.Response{Results: "ok"})
@@ -963,7 +1112,7 @@ func (rt *Router) HandlerUpdateApplication(c *gin.Context) {
 // HandlerDelApplication 删除某个申请
 // API: /api/v1/:cluster/slurm/reservation/application/:id
 // 请求参数解释:
-//   - cluster: 对应某个集群的申请, 该字段不会被使用仅为了保证路径命名一致性. 其可靠性由查询保证.
+//   - cluster: 申请所属的集群, 与申请的集群不一致时视为申请不存在(集群为空的历史申请除外).
 //   - id: 申请ID, 对应数据库中 Application.ID 字段
 //
 // 执行流程:
@@ -978,31 +1127,63 @@ func (rt *Router) HandlerUpdateApplication(c *gin.Context) {
 // @Param id path int true "申请ID"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/reservation/application/{id} [delete]
 func (rt *Router) HandlerDelApplication(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
+		return
+	}
+	if !rt.checkApplicationOwner(c, id) {
 		return
 	}
 	if err := rt.db.DelApplication(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to delete application: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to delete application"))
 		return
 	}
 	c.JSON(http.StatusOK, response.Response{Results: "ok"})
 }
 
+// checkApplicationOwner 校验申请(id)为路径中集群的 APPLICATION_CLASS_RESOURCE 类申请且属于调用者, reviewer 可访问该集群的全部申请.
+// 校验失败时写入响应并返回 false.
+func (rt *Router) checkApplicationOwner(c *gin.Context, id int) bool {
+	app, err := rt.db.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, dbpg.ErrApplicationNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_APPLICATION_NOT_FOUND), Detail: fmt.Sprintf("application not found: id=%d", id)})
+			return false
+		}
+		c.JSON(apperror.Render(err, "failed to fetch application"))
+		return false
+	}
+	// 角色按集群分配, 需先校验申请属于路径中的集群
+	if app.Class != dbpg.APPLICATION_CLASS_RESOURCE || !app.InCluster(middleware.GetCluster(c).Name) {
+		c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_APPLICATION_NOT_FOUND), Detail: fmt.Sprintf("application not found: id=%d", id)})
+		return false
+	}
+	if middleware.HasRole(c, auth.ROLE_REVIEWER) {
+		return true
+	}
+	if app.Applier != middleware.Username(c) {
+		c.JSON(http.StatusForbidden, response.Response{Code: string(apperror.CODE_PERMISSION_DENIED), Detail: "permission denied: not the applier of the application"})
+		return false
+	}
+	return true
+}
+
 type Review struct {
 	Approve  bool `json:"approve"`
 	Decision string
@@ -1012,18 +1193,24 @@ type Review struct {
 // HandlerRevireApplication 审核某预约
 // API: PUT /api/v1/:cluster/slurm/reservation/application/:id/review
 // 请求参数解释:
-//   - cluster: 对应某个集群的申请, 该字段不会被使用仅为了保证路径命名一致性. 其可靠性由查询保证.
+//   - cluster: 申请所属的集群, 与申请的集群不一致时视为申请不存在(集群为空的历史申请除外).
 //   - id: 申请ID, 对应数据库中 Application.ID 字段
 //
 // 请求体参数: Review
-// approve: 为bool类型, 表示是否通过审核, 若通过则 nodelist 参数生效, 为节点参数列表不能为空. 若 approve=false, 则不需要nodelist参数.
+// approve: 为bool类型, 表示是否通过审核, 若通过则按申请内容创建预约, 需指定 start, duration 以及 nodes 或 node_cnt.
+// 若 approve=false, 则不需要预约参数.
 //
 // 执行流程
-//   - 解析参数
-//   - 若 approve == true, 则调用 DoReservation 执行实际预约操作.
-//   - 调用 db.DoReview, approve=true 对应 state = APPLICATION_STATE_PASSED. approve=false, 对应 state = APPLICATION_STATE_REJECTED.
+//   - 解析参数, 已通过的申请不能重复审核.
+//   - 若 approve == true, 则调用 CreateReservation 创建预约, 预约名称为空时使用 resv_app<id>, 预约用户为空时使用申请人.
+//     创建成功时 state = APPLICATION_STATE_PASSED, 申请内容中的 reservation_name 为实际创建的预约名称;
+//     Slurm 拒绝时 state = APPLICATION_STATE_PASSED_UNSUCCESS, Slurm 的错误信息追加到审核意见;
+//     无法连接 slurmrestd 或超时时返回错误, 申请保持原状态.
+//   - 调用 db.DoReview, approve=false 对应 state = APPLICATION_STATE_REJECTED.
 //
 // @Summary 审核资源预约申请
+// @Description 通过时按申请内容创建 Slurm 预约(scontrol create reservation). Slurm 拒绝时申请状态为"通过, 但预约创建失败",
+// @Description 审核意见中包含 Slurm 的错误信息.
 // @Tags 资源管理, 资源预约
 // @Accept json
 // @Produce json
@@ -1032,104 +1219,175 @@ type Review struct {
 // @Param body body Review true "审核结论"
 // @Success 200 {object} response.Response{results=string}
 // @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/reservation/application/{id}/review [put]
 func (rt *Router) HandlerRevireApplication(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if strings.TrimSpace(cluster) == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	sid := strings.TrimSpace(c.Param("id"))
 	if sid == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing application id in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing application id in path"})
 		return
 	}
 	id, err := strconv.Atoi(sid)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid application id: must be integer"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
 		return
 	}
 	var in Review
 	if err := c.ShouldBindJSON(&in); err != nil {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid request body: " + err.Error()})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
 		return
 	}
+	app, err := rt.db.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, dbpg.ErrApplicationNotFound) {
+			c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_APPLICATION_NOT_FOUND), Detail: fmt.Sprintf("application not found: id=%d", id)})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to fetch application"))
+		return
+	}
+	if app.Class != dbpg.APPLICATION_CLASS_RESOURCE || !app.InCluster(middleware.GetCluster(c).Name) {
+		c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_APPLICATION_NOT_FOUND), Detail: fmt.Sprintf("application not found: id=%d", id)})
+		return
+	}
+	switch app.State {
+	case dbpg.APPLICATION_STATE_PASSED, dbpg.APPLICATION_STATE_ACTIVE, dbpg.APPLICATION_STATE_EXPIRED, dbpg.APPLICATION_STATE_CANCELLED:
+		c.JSON(http.StatusConflict, response.Response{Code: string(apperror.CODE_CONFLICT), Detail: fmt.Sprintf("application already reviewed: id=%d", id)})
+		return
+	}
+
 	state := dbpg.APPLICATION_STATE_REJECTED
+	decision := in.Decision
 	if in.Approve {
-		if strings.TrimSpace(in.Nodes) == "" {
-			c.JSON(http.StatusBadRequest, response.Response{Detail: "nodelist is required when approve=true"})
+		if err := validateReservation(in.ApplicationContent); err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+			return
+		}
+		cl := middleware.GetCluster(c)
+		if cl.Slurmrestd == "" {
+			c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 			return
 		}
-		if err := DoReservation(in.Nodes); err != nil {
+		if strings.TrimSpace(in.ReservationName) == "" {
+			in.ReservationName = reservationName(id)
+		}
+		if strings.TrimSpace(in.User) == "" {
+			in.User = app.Applier
+		}
+
+		name, err := rt.slurmrestc(c).CreateReservation(c.Request.Context(), cl.Slurmrestd, reservationOf(in.ApplicationContent))
+		if err != nil {
+			switch apperror.CodeOf(err) {
+			case apperror.CODE_UPSTREAM_UNAVAILABLE, apperror.CODE_UPSTREAM_TIMEOUT, apperror.CODE_INTERNAL:
+				// 未能确认 Slurm 是否处理了请求, 申请保持原状态, 由审核人重试
+				middleware.Logger(c).Error("unable to create reservation", "application", id, "err", err)
+				c.JSON(apperror.Render(err, "failed to create reservation"))
+				return
+			}
+			middleware.Logger(c).Warn("reservation rejected by slurm", "application", id, "err", err)
 			state = dbpg.APPLICATION_STATE_PASSED_UNSUCCESS
+			if decision != "" {
+				decision += "; "
+			}
+			decision += "预约创建失败: " + err.Error()
 		} else {
 			state = dbpg.APPLICATION_STATE_PASSED
+			in.ReservationName = name
+			rt.backends.Invalidate(cl.Name, slurmrest.CACHE_NODES)
 		}
 	}
 
 	contentBytes, err := json.Marshal(in.ApplicationContent)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to marshal review content: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to marshal review content"))
 		return
 	}
-	if err := rt.db.DoReview(c.Request.Context(), id, state, in.Decision, string(contentBytes)); err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to update review: " + err.Error()})
+	if err := rt.db.DoReview(c.Request.Context(), id, state, middleware.Username(c), decision, string(contentBytes)); err != nil {
+		c.JSON(apperror.Render(err, "failed to update review"))
 		return
 	}
 	c.JSON(http.StatusOK, response.Response{Results: "ok"})
 }
 
-// TODO: 模拟
-func DoReservation(nodelist string) error {
-	return nil
-}
-
 type GetAllNodesQuery struct {
 	Partition string `form:"partition" binding:"required"`
+	Nodes     string `form:"nodes"` // 节点, 支持 hostlist 表达式, 为空时不过滤
 	paging.PagingParam
 }
 
 // HandlerGetAllNodes 获取某集群指定分区的节点列表
 // @Summary 获取某集群分区节点列表
-// @Description 按分区查询节点列表，支持分页。
+// @Description 按分区查询节点列表，支持分页及按节点(hostlist 表达式)过滤。
 // @Tags 资源管理, 资源总览
 // @Produce json
 // @Param cluster path string true "集群名称" example("test")
 // @Param partition query string true "分区名称"
+// @Param nodes query string false "节点, 支持 hostlist 表达式" example("cn[01-04,10]")
 // @Param paging query bool false "是否开启分页, 默认为 true" default(true)
 // @Param page query int false "页码，从1开始" default(1) minimum(1)
 // @Param page_size query int false "每页数量，最大100" default(10) minimum(1) maximum(100)
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/nodes [get]
 func (rt *Router) HandlerGetAllNodes(ctx *gin.Context) {
 	// get request parameters
 	cluster := ctx.Param("cluster")
 	if cluster == "" {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: "invalid parameter 'cluster'"})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid parameter 'cluster'"})
 		return
 	}
 
 	var query GetAllNodesQuery
 	if err := ctx.ShouldBindQuery(&query); err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: err.Error()})
+		ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
 		return
 	}
 
 	// get addr slurmrest server for 'cluster'
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, response.Response{Detail: fmt.Sprintf("unable to get slurmrest address of cluster(%s): %s", cluster, err)})
+	addr := middleware.GetCluster(ctx).Slurmrestd
+	if addr == "" {
+		ctx.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: fmt.Sprintf("empty slurmrestd address for cluster(%s)", cluster)})
 		return
 	}
 
-	nodes, total, err := rt.slurmrestc.GetNodes(ctx.Request.Context(), addr, []string{query.Partition}, query.Paging, query.Page, query.PageSize)
+	var names []string
+	if query.Nodes != "" {
+		expanded, err := hostlist.Expand(query.Nodes)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid nodes: " + err.Error()})
+			return
+		}
+		names = hostlist.Uniq(expanded)
+	}
+
+	// 按节点过滤时查询分区内全部节点, 过滤后在本地分页
+	nodes, total, err := rt.slurmrestc(ctx).GetNodes(ctx.Request.Context(), addr, []string{query.Partition}, query.Paging && names == nil, query.Page, query.PageSize)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, response.Response{Detail: "unable to get nodes from slurmrest"})
+		ctx.JSON(apperror.Render(err, "unable to get nodes from slurmrest"))
 		return
 	}
+	if names != nil {
+		nodes = slices.DeleteFunc(slices.Clone(nodes), func(n *model.Node) bool { return !slices.Contains(names, n.Name) })
+		total = len(nodes)
+		if query.Paging {
+			lo := min((query.Page-1)*query.PageSize, total)
+			nodes = nodes[lo:min(lo+query.PageSize, total)]
+		}
+	}
 
 	var prevURL, nextURL url.URL
 	if query.Paging {
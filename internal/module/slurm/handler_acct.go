@@ -1,8 +1,15 @@
 package slurm
 
 import (
+	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+	"csjk-bk/internal/pkg/middleware"
 	"csjk-bk/internal/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -19,30 +26,29 @@ type AccountNameList []string
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=AccountNameList}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/account/name/list [get]
 func (rt *Router) HandlerGetAccountsNameList(c *gin.Context) {
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 查询 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 调用 slurmrest 客户端，获取当前页数据
-	items, total, err := rt.slurmrestc.GetAccounts(c.Request.Context(), addr, false, 0, 0)
+	items, total, err := rt.slurmrestc(c).GetAccounts(c.Request.Context(), addr, false, 0, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch accounts list: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch accounts list"))
 		return
 	}
 
@@ -65,39 +71,166 @@ func (rt *Router) HandlerGetAccountsNameList(c *gin.Context) {
 // @Param account path string true "账户节点名称" example("root")
 // @Success 200 {object} response.Response{results=slurmrest.AccountNode}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/account/{account}/childnodes [get]
 func (rt *Router) HandlerGetAccountChildNodes(c *gin.Context) {
 	// 解析路径参数
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	account := c.Param("account")
 	if account == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing account in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing account in path"})
 		return
 	}
 
 	// 查询 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 调用 slurmrest 客户端
-	node, err := rt.slurmrestc.GetChildNodesOfAccount(c.Request.Context(), addr, account)
+	node, err := rt.slurmrestc(c).GetChildNodesOfAccount(c.Request.Context(), addr, account)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch account child nodes: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch account child nodes"))
 		return
 	}
 
 	// 返回结果
 	c.JSON(http.StatusOK, response.Response{Results: node})
 }
+
+// accountBusyStates 账户中存在这些状态的作业时不允许删除账户.
+var accountBusyStates = []string{"PENDING", "RUNNING", "SUSPENDED", "COMPLETING", "CONFIGURING", "REQUEUED"}
+
+// HandlerCreateAccount 创建账户.
+// @Summary 创建账户
+// @Description 对应 sacctmgr add account <name> parent=<parent> organization=<organization> description=<description>, parent 为空时为 root.
+// @Tags 资源管理, 用户管理
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param body body model.AccountWrite true "账户信息"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/account [post]
+func (rt *Router) HandlerCreateAccount(c *gin.Context) {
+	var in model.AccountWrite
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	in.Name, in.Parent = strings.ToLower(strings.TrimSpace(in.Name)), strings.TrimSpace(in.Parent)
+	if in.Name == "" || strings.ContainsAny(in.Name, ", \t=/") {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid account name: " + in.Name})
+		return
+	}
+	if in.Parent == "" {
+		in.Parent = "root"
+	}
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	accounts, _, err := rt.slurmrestc(c).GetAccounts(c.Request.Context(), cl.Slurmrestd, false, 0, 0)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch accounts list"))
+		return
+	}
+	if slices.ContainsFunc(accounts, func(a model.Account) bool { return a.Name == in.Name }) {
+		c.JSON(http.StatusConflict, response.Response{Code: string(apperror.CODE_CONFLICT), Detail: "account already exists: " + in.Name})
+		return
+	}
+	if !slices.ContainsFunc(accounts, func(a model.Account) bool { return a.Name == in.Parent }) {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "parent account not found: " + in.Parent})
+		return
+	}
+
+	if err := rt.slurmrestc(c).CreateAccount(c.Request.Context(), cl.Slurmrestd, in); err != nil {
+		middleware.Logger(c).Error("unable to create account", "account", in.Name, "parent", in.Parent, "err", err)
+		c.JSON(apperror.Render(err, "failed to create account"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_ACCOUNTS)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+// HandlerDeleteAccount 删除账户.
+// @Summary 删除账户
+// @Description 对应 sacctmgr delete account. 账户下仍有子账户, 用户关联或未结束的作业时拒绝删除(409).
+// @Tags 资源管理, 用户管理
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param account path string true "账户名称" example("physics")
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/account/{account} [delete]
+func (rt *Router) HandlerDeleteAccount(c *gin.Context) {
+	account := c.Param("account")
+	if account == "root" {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "root account can not be deleted"})
+		return
+	}
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	// 检查前清除缓存, 避免依据过期的关联判断
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_ACCOUNTS)
+	if _, err := rt.slurmrestc(c).GetAccountByName(c.Request.Context(), cl.Slurmrestd, account); err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch account"))
+		return
+	}
+	node, err := rt.slurmrestc(c).GetAssociationChildNodesOfAccount(c.Request.Context(), cl.Slurmrestd, account)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch association child nodes"))
+		return
+	}
+	if len(node.SubAccounts) > 0 || len(node.SubUsers) > 0 {
+		c.JSON(http.StatusConflict, response.Response{Code: string(apperror.CODE_CONFLICT), Detail: fmt.Sprintf("account %s still has %d child accounts and %d users", account, len(node.SubAccounts), len(node.SubUsers))})
+		return
+	}
+	filter := model.SchedulingJobFilter{Accounts: []string{account}, States: accountBusyStates}
+	_, busy, err := rt.slurmrestc(c).GetSchedulingJobs(c.Request.Context(), cl.Slurmrestd, filter, true, 1, 1)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch scheduling jobs"))
+		return
+	}
+	if busy > 0 {
+		c.JSON(http.StatusConflict, response.Response{Code: string(apperror.CODE_CONFLICT), Detail: fmt.Sprintf("account %s still has %d unfinished jobs", account, busy)})
+		return
+	}
+
+	if err := rt.slurmrestc(c).DeleteAccount(c.Request.Context(), cl.Slurmrestd, account); err != nil {
+		middleware.Logger(c).Error("unable to delete account", "account", account, "err", err)
+		c.JSON(apperror.Render(err, "failed to delete account"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_ACCOUNTS)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
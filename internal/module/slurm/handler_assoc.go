@@ -1,10 +1,18 @@
 package slurm
 
 import (
+	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+	"csjk-bk/internal/pkg/common/slurm/tres"
+	"csjk-bk/internal/pkg/middleware"
 	"csjk-bk/internal/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -18,36 +26,35 @@ import (
 // @Param account path string true "账户名称" example("root")
 // @Success 200 {object} response.Response{results=slurmrest.AssociationNode}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/association/{account}/childnodes [get]
 func (rt *Router) HandlerGetAssociationChildNodesOfAccount(c *gin.Context) {
 	// 校验路径参数
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	account := c.Param("account")
 	if account == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing account in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing account in path"})
 		return
 	}
 
 	// 获取 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 查询关联子节点
-	node, err := rt.slurmrestc.GetAssociationChildNodesOfAccount(c.Request.Context(), addr, account)
+	node, err := rt.slurmrestc(c).GetAssociationChildNodesOfAccount(c.Request.Context(), addr, account)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch association child nodes: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch association child nodes"))
 		return
 	}
 
@@ -79,6 +86,8 @@ type AssociationDetail struct {
 	MaxTresRunMins string `json:"max_tres_run_mins"` // 单作业运行中 TRES 时间上限
 	DefQosID       string `json:"def_qos_id"`        // 默认 QoS 策略
 	QOS            string `json:"qos"`               // 可用/关联的 QoS 列表
+
+	TresResources map[string]tres.Resources `json:"tres_resources"` // TRES 限制的结构化表示, 键为上述 TRES 字段名, 未设置的字段不包含; *_mins 字段的值为分钟数
 }
 
 // @Summary 获取某集群中某个关联详情
@@ -91,46 +100,45 @@ type AssociationDetail struct {
 // @Param partition query string false "分区名" default("") example("p1")
 // @Success 200 {object} response.Response{results=AssociationDetail}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/association/detail [get]
 func (rt *Router) HandlerGetAssociationDetail(c *gin.Context) {
 	// 校验 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 解析查询参数
 	acct := c.Query("account")
 	if acct == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing required query: account"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing required query: account"})
 		return
 	}
 	user := c.Query("user")
 	part := c.Query("partition")
 
 	// 解析 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 查询详情
-	item, err := rt.slurmrestc.GetAssociationDetail(c.Request.Context(), addr, acct, user, part)
+	item, err := rt.slurmrestc(c).GetAssociationDetail(c.Request.Context(), addr, acct, user, part)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch association detail: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch association detail"))
 		return
 	}
 
-	qosItems, _, err := rt.slurmrestc.GetQosAll(c.Request.Context(), addr, false, 0, 0)
+	qosItems, _, err := rt.slurmrestc(c).GetQosAll(c.Request.Context(), addr, false, 0, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch qos list: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch qos list"))
 		return
 	}
 
@@ -178,7 +186,226 @@ func (rt *Router) HandlerGetAssociationDetail(c *gin.Context) {
 		DefQosID:       idToName[item.DefQosID],
 		QOS:            strings.Join(qosList, ","),
 	}
+	out.TresResources = tresResources(rt.tresTable(c), map[string]string{
+		"grp_tres":          item.GrpTres,
+		"grp_tres_mins":     item.GrpTresMins,
+		"grp_tres_run_mins": item.GrpTresRunMins,
+		"max_tres_pj":       item.MaxTresPJ,
+		"max_tres_mins_pj":  item.MaxTresMinsPJ,
+		"max_tres_pn":       item.MaxTresPN,
+		"max_tres_run_mins": item.MaxTresRunMins,
+	})
 	// 如需要返回 cluster，可 out.ClusterName = cluster
 
 	c.JSON(http.StatusOK, response.Response{Results: out})
 }
+
+// HandlerAddUserAssociation 将用户加入账户.
+// @Summary 将用户加入账户
+// @Description 对应 sacctmgr add user <user> account=<account> [partition=<partition>], 用户不存在时一并创建. partition 为空时关联全部分区.
+// @Tags 资源管理, 用户管理
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param body body model.UserAssociation true "用户, 账户及分区"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/association [post]
+func (rt *Router) HandlerAddUserAssociation(c *gin.Context) {
+	var in model.UserAssociation
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	in.User, in.Account, in.Partition = strings.TrimSpace(in.User), strings.TrimSpace(in.Account), strings.TrimSpace(in.Partition)
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	if _, err := rt.slurmrestc(c).GetAccountByName(c.Request.Context(), cl.Slurmrestd, in.Account); err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch account"))
+		return
+	}
+	if in.Partition != "" {
+		if _, err := rt.slurmrestc(c).GetPartitionByName(c.Request.Context(), cl.Slurmrestd, in.Partition); err != nil {
+			c.JSON(apperror.Render(err, "failed to fetch partition"))
+			return
+		}
+	}
+	item, err := rt.slurmrestc(c).GetAssociationDetail(c.Request.Context(), cl.Slurmrestd, in.Account, in.User, in.Partition)
+	switch {
+	case err == nil && item.Partition == in.Partition:
+		c.JSON(http.StatusConflict, response.Response{Code: string(apperror.CODE_CONFLICT), Detail: fmt.Sprintf("association already exists: account=%s, user=%s, partition=%s", in.Account, in.User, in.Partition)})
+		return
+	case err != nil && apperror.CodeOf(err) != apperror.CODE_NOT_FOUND:
+		c.JSON(apperror.Render(err, "failed to fetch association detail"))
+		return
+	}
+
+	if err := rt.slurmrestc(c).AddUserAssociation(c.Request.Context(), cl.Slurmrestd, in); err != nil {
+		middleware.Logger(c).Error("unable to add user association", "user", in.User, "account", in.Account, "partition", in.Partition, "err", err)
+		c.JSON(apperror.Render(err, "failed to add user association"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_ACCOUNTS)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+// HandlerUpdateAssociation 修改关联限制.
+// @Summary 修改关联限制
+// @Description 由 account, user, partition 确定关联(user 为空时为账户本身的关联), 其余字段未设置时不修改, 数值限制为 -1 表示清除限制.
+// @Description grp_tres 格式为 type[/name]=count, 逗号分隔, 仅修改列出的 TRES; qos, def_qos 为 QoS 名称, def_qos 须在可用 QoS 中.
+// @Tags 资源管理, 用户管理
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param body body model.AssociationWrite true "关联及需修改的限制"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/association [put]
+func (rt *Router) HandlerUpdateAssociation(c *gin.Context) {
+	var in model.AssociationWrite
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	if reflect.DeepEqual(in, model.AssociationWrite{Account: in.Account, User: in.User, Partition: in.Partition}) {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "nothing to update"})
+		return
+	}
+	for field, v := range map[string]*int32{"max_jobs": in.MaxJobs, "max_wall_pj": in.MaxWallPJ, "shares": in.Shares} {
+		if v != nil && *v < -1 {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: field + " must be -1 or non-negative"})
+			return
+		}
+	}
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	if in.GrpTres != "" {
+		list, err := tres.Parse(in.GrpTres, rt.tresTable(c))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "grp_tres: " + err.Error()})
+			return
+		}
+		in.GrpTres = list.String()
+	}
+
+	item, err := rt.slurmrestc(c).GetAssociationDetail(c.Request.Context(), cl.Slurmrestd, in.Account, in.User, in.Partition)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch association detail"))
+		return
+	}
+
+	// 校验 QoS 名称, def_qos 须在修改后的可用 QoS 中
+	if in.QOS != nil || (in.DefQoS != nil && *in.DefQoS != "") {
+		qosItems, _, err := rt.slurmrestc(c).GetQosAll(c.Request.Context(), cl.Slurmrestd, false, 0, 0)
+		if err != nil {
+			c.JSON(apperror.Render(err, "failed to fetch qos list"))
+			return
+		}
+		idToName := make(map[int32]string, len(qosItems))
+		for _, q := range qosItems {
+			idToName[q.ID] = q.Name
+		}
+		available := strings.Split(preemptNames(item.QOS, idToName), ",")
+		if in.QOS != nil {
+			available = make([]string, 0)
+			for _, name := range strings.Split(*in.QOS, ",") {
+				if name = strings.TrimSpace(name); name == "" {
+					continue
+				}
+				if !slices.ContainsFunc(qosItems, func(q model.QoS) bool { return q.Name == name }) {
+					c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "qos not found: " + name})
+					return
+				}
+				available = append(available, name)
+			}
+			qos := strings.Join(available, ",")
+			in.QOS = &qos
+		}
+		if in.DefQoS != nil && *in.DefQoS != "" && !slices.Contains(available, *in.DefQoS) {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "def_qos must be one of the available qos: " + *in.DefQoS})
+			return
+		}
+	}
+
+	if err := rt.slurmrestc(c).UpdateAssociation(c.Request.Context(), cl.Slurmrestd, in); err != nil {
+		middleware.Logger(c).Error("unable to update association", "account", in.Account, "user", in.User, "partition", in.Partition, "err", err)
+		c.JSON(apperror.Render(err, "failed to update association"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_ACCOUNTS)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+type DefaultAccountParam struct {
+	Account string `json:"account" binding:"required" example:"physics"` // 默认账户, 用户须已加入该账户
+}
+
+// HandlerSetDefaultAccount 设置用户的默认账户.
+// @Summary 设置用户的默认账户
+// @Description 对应 sacctmgr modify user <user> set defaultaccount=<account>, 用户须已加入该账户.
+// @Tags 资源管理, 用户管理
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param user path string true "用户名" example("alice")
+// @Param body body DefaultAccountParam true "默认账户"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/user/{user}/default_account [put]
+func (rt *Router) HandlerSetDefaultAccount(c *gin.Context) {
+	var in DefaultAccountParam
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	user := c.Param("user")
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	if _, err := rt.slurmrestc(c).GetAssociationDetail(c.Request.Context(), cl.Slurmrestd, in.Account, user, ""); err != nil {
+		if apperror.CodeOf(err) == apperror.CODE_NOT_FOUND {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("user %s is not associated with account %s", user, in.Account)})
+			return
+		}
+		c.JSON(apperror.Render(err, "failed to fetch association detail"))
+		return
+	}
+
+	if err := rt.slurmrestc(c).SetDefaultAccount(c.Request.Context(), cl.Slurmrestd, user, in.Account); err != nil {
+		middleware.Logger(c).Error("unable to set default account", "user", user, "account", in.Account, "err", err)
+		c.JSON(apperror.Render(err, "failed to set default account"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_ACCOUNTS)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
@@ -0,0 +1,40 @@
+package slurm
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerInvalidateCache 清除集群的 slurmrestd 响应缓存.
+// @Summary 清除 Slurm 数据缓存
+// @Description 节点, 分区, QoS, 账户, TRES 类型及调度队列等数据在服务端缓存一段时间(见配置 cache.ttl), 通过本接口修改的数据会自动清除缓存,
+// @Description 直接在集群上修改(如 scontrol, sacctmgr)后可调用本接口立即生效. resource 可重复传递, 缺省清除全部.
+// @Tags 资源管理
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param resource query []string false "资源类型" collectionFormat(multi) Enums(nodes, partitions, qos, accounts, tres, jobs)
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/cache [delete]
+func (rt *Router) HandlerInvalidateCache(c *gin.Context) {
+	resources := c.QueryArray("resource")
+	for _, r := range resources {
+		if !slices.Contains(slurmrest.CacheResources, r) {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "resource must be one of [" + strings.Join(slurmrest.CacheResources, ", ") + "], got " + r})
+			return
+		}
+	}
+
+	rt.backends.Invalidate(middleware.GetCluster(c).Name, resources...)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
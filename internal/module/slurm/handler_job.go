@@ -0,0 +1,496 @@
+package slurm
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+	"csjk-bk/internal/pkg/common/slurm"
+	"csjk-bk/internal/pkg/common/slurm/hostlist"
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MAX_JOB_CONTROL_BATCH 单次批量作业控制的最大作业数.
+const MAX_JOB_CONTROL_BATCH = 100
+
+var (
+	// 作业号: 1234, 作业数组任务: 1234_5 / 1234_[1-3,5], 作业步: 1234.0 / 1234_5.batch
+	jobIDPattern = regexp.MustCompile(`^(\d+)(_(\d+|\[[0-9,\-%:]+\]))?(\.(\d+|batch|extern))?$`)
+	// 信号: 名称(USR1, SIGUSR1)或编号
+	signalPattern = regexp.MustCompile(`^((SIG)?[A-Z][A-Z0-9]*|\d{1,2})$`)
+
+	// 作业提交选项
+	nodesPattern      = regexp.MustCompile(`^\d+(-\d+)?$`)                                                             // 2, 1-4
+	gresPattern       = regexp.MustCompile(`^[A-Za-z0-9_]+(:[A-Za-z0-9_.\-]+)*(,[A-Za-z0-9_]+(:[A-Za-z0-9_.\-]+)*)*$`) // gpu:2, gpu:a100:1
+	dependencyPattern = regexp.MustCompile(`^[a-z]+(:[0-9_+]+)+([,?][a-z]+(:[0-9_+]+)+)*$|^singleton$`)                // afterok:1234:1235
+	arrayPattern      = regexp.MustCompile(`^[0-9,\-:]+(%\d+)?$`)                                                      // 1-10%2, 1,3,5
+	envKeyPattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	namePattern       = regexp.MustCompile(`^[A-Za-z0-9_.\-]*$`)
+)
+
+type JobControlParam struct {
+	Signal string `json:"signal" example:"USR1"` // 信号, action 为 signal 时必填, cancel 时可选
+}
+
+// JobControlResult 批量作业控制中单个作业的结果.
+type JobControlResult struct {
+	JobID   string `json:"jobid"`   // 作业号
+	Success bool   `json:"success"` // 是否成功
+	Code    string `json:"code"`    // 失败时的错误码
+	Detail  string `json:"detail"`  // 失败原因
+}
+
+// splitJobIDs 按逗号拆分作业号列表, 忽略作业数组下标范围([1,3-5])中的逗号.
+func splitJobIDs(s string) []string {
+	var (
+		ids   []string
+		depth int
+		start int
+	)
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				ids = append(ids, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(ids, strings.TrimSpace(s[start:]))
+}
+
+// HandlerControlJob 对作业执行取消, 挂起, 释放, 重新排队, 暂停, 恢复或发送信号.
+// @Summary 作业控制
+// @Description 对应 scancel 及 scontrol hold/release/requeue/suspend/resume. jobid 可为作业号(1234), 作业数组任务(1234_5, 1234_[1-3])
+// @Description 或作业步(1234.0, 仅 cancel/signal), 多个作业以逗号分隔, 最多 100 个.
+// @Description 普通用户仅能操作本人的作业, operator 可操作全部作业. 单个作业失败时按错误返回相应状态码;
+// @Description 多个作业时返回 200 及每个作业的结果.
+// @Description 官方 slurmrestd 仅支持 cancel, signal, hold, release.
+// @Tags 作业管理
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param jobid path string true "作业号, 多个以逗号分隔" example("1234,1235_[1-3]")
+// @Param action path string true "操作" Enums(cancel, hold, release, requeue, suspend, resume, signal)
+// @Param body body JobControlParam false "信号"
+// @Success 200 {object} response.Response{results=[]JobControlResult}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 501 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/job/{jobid}/{action} [post]
+func (rt *Router) HandlerControlJob(c *gin.Context) {
+	action := slurmrest.JobAction(c.Param("action"))
+	if !slices.Contains(slurmrest.JobActions, action) {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("unknown job action: %s", action)})
+		return
+	}
+
+	var in JobControlParam
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&in); err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+			return
+		}
+	}
+	in.Signal = strings.ToUpper(strings.TrimSpace(in.Signal))
+	if action == slurmrest.JOB_ACTION_SIGNAL && in.Signal == "" {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "signal is required for action signal"})
+		return
+	}
+	if in.Signal != "" {
+		if action != slurmrest.JOB_ACTION_SIGNAL && action != slurmrest.JOB_ACTION_CANCEL {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("signal is not allowed for action %s", action)})
+			return
+		}
+		if !signalPattern.MatchString(in.Signal) {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid signal: " + in.Signal})
+			return
+		}
+	}
+
+	ids := splitJobIDs(c.Param("jobid"))
+	if len(ids) > MAX_JOB_CONTROL_BATCH {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("at most %d jobs per request", MAX_JOB_CONTROL_BATCH)})
+		return
+	}
+	for _, id := range ids {
+		m := jobIDPattern.FindStringSubmatch(id)
+		if m == nil {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid job id: " + id})
+			return
+		}
+		if m[4] != "" && action != slurmrest.JOB_ACTION_CANCEL && action != slurmrest.JOB_ACTION_SIGNAL {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("job step is not allowed for action %s: %s", action, id)})
+			return
+		}
+	}
+
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	// 普通用户仅能操作本人的作业, 作业归属按作业号(不含数组下标及作业步)查询
+	owners := map[string]string{}
+	checkOwner := !middleware.HasRole(c, auth.ROLE_OPERATOR)
+	control := func(id string) error {
+		if checkOwner {
+			base := jobIDPattern.FindStringSubmatch(id)[1]
+			owner, ok := owners[base]
+			if !ok {
+				job, err := rt.slurmrestc(c).GetSchedulingJob(c.Request.Context(), addr, base)
+				if err != nil {
+					return err
+				}
+				owner = job.User
+				owners[base] = owner
+			}
+			if owner != middleware.Username(c) {
+				return apperror.New(apperror.CODE_PERMISSION_DENIED, "permission denied: not the owner of job "+base)
+			}
+		}
+		err := rt.slurmrestc(c).ControlJob(c.Request.Context(), addr, id, action, in.Signal)
+		rt.backends.Invalidate(middleware.GetCluster(c).Name, slurmrest.CACHE_JOBS) // 失败时也可能已部分生效
+		return err
+	}
+
+	if len(ids) == 1 {
+		if err := control(ids[0]); err != nil {
+			middleware.Logger(c).Error("unable to control job", "jobid", ids[0], "action", action, "err", err)
+			c.JSON(apperror.Render(err, fmt.Sprintf("failed to %s job %s", action, ids[0])))
+			return
+		}
+		c.JSON(http.StatusOK, response.Response{Count: 1, Results: []JobControlResult{{JobID: ids[0], Success: true}}})
+		return
+	}
+
+	results := make([]JobControlResult, 0, len(ids))
+	for _, id := range ids {
+		r := JobControlResult{JobID: id, Success: true}
+		if err := control(id); err != nil {
+			middleware.Logger(c).Error("unable to control job", "jobid", id, "action", action, "err", err)
+			r = JobControlResult{JobID: id, Code: string(apperror.CodeOf(err)), Detail: err.Error()}
+		}
+		results = append(results, r)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: len(results), Results: results})
+}
+
+// MAX_JOB_SCRIPT_SIZE 批处理脚本的最大长度(字节).
+const MAX_JOB_SCRIPT_SIZE = 1 << 20
+
+type JobSubmitParam struct {
+	Script      string            `json:"script" binding:"required" example:"#!/bin/bash\nsrun hostname"` // 批处理脚本, 以 #! 开头
+	User        string            `json:"user"`                                                           // 提交用户, 为空表示登录用户; 代其他用户提交需 operator 或 admin 角色
+	Name        string            `json:"name" example:"test"`                                            // 作业名
+	Partition   string            `json:"partition" example:"cpu"`                                        // 分区
+	QoS         string            `json:"qos" example:"normal"`                                           // QoS
+	Account     string            `json:"account" example:"root"`                                         // 账户
+	Nodes       string            `json:"nodes" example:"1-2"`                                            // 节点数, 如 2 或 1-4
+	Tasks       uint32            `json:"tasks" example:"4"`                                              // 任务数
+	CPUsPerTask uint32            `json:"cpus_per_task" example:"1"`                                      // 每个任务的 CPU 数
+	TimeLimit   uint32            `json:"time_limit" example:"60"`                                        // 运行时间上限, 单位分钟
+	Gres        string            `json:"gres" example:"gpu:1"`                                           // 通用资源
+	Dependency  string            `json:"dependency" example:"afterok:1234"`                              // 作业依赖
+	Array       string            `json:"array" example:"1-10%2"`                                         // 作业数组
+	WorkDir     string            `json:"work_dir" example:"/home/test"`                                  // 工作目录
+	Environment map[string]string `json:"environment"`                                                    // 环境变量
+}
+
+// validate 校验提交参数, 返回第一个错误描述, 全部合法时返回空字符串.
+func (p JobSubmitParam) validate() string {
+	switch {
+	case !strings.HasPrefix(p.Script, "#!"):
+		return "script must start with #!"
+	case len(p.Script) > MAX_JOB_SCRIPT_SIZE:
+		return fmt.Sprintf("script must not exceed %d bytes", MAX_JOB_SCRIPT_SIZE)
+	case !namePattern.MatchString(p.Name), !namePattern.MatchString(p.Partition),
+		!namePattern.MatchString(p.QoS), !namePattern.MatchString(p.Account):
+		return "name, partition, qos and account may only contain letters, digits, '_', '.' and '-'"
+	case p.Nodes != "" && !nodesPattern.MatchString(p.Nodes):
+		return "invalid nodes: " + p.Nodes
+	case p.Gres != "" && !gresPattern.MatchString(p.Gres):
+		return "invalid gres: " + p.Gres
+	case p.Dependency != "" && !dependencyPattern.MatchString(p.Dependency):
+		return "invalid dependency: " + p.Dependency
+	case p.Array != "" && !arrayPattern.MatchString(p.Array):
+		return "invalid array: " + p.Array
+	case p.WorkDir != "" && !strings.HasPrefix(p.WorkDir, "/"):
+		return "work_dir must be an absolute path"
+	}
+	for k := range p.Environment {
+		if !envKeyPattern.MatchString(k) {
+			return "invalid environment variable name: " + k
+		}
+	}
+	return ""
+}
+
+// submitUser 返回作业的提交用户. caller 为登录用户(未启用认证时为空), requested 为请求中的用户;
+// 集群令牌可代任意用户提交, 因此仅 privileged(operator 或 admin)可代其他用户提交, 未启用认证时 requested 必填.
+func submitUser(caller, requested string, privileged bool) (string, error) {
+	switch {
+	case caller == "" && requested == "":
+		return "", apperror.New(apperror.CODE_VALIDATION_FAILED, "user is required when authentication is disabled")
+	case caller == "":
+		return requested, nil
+	case requested == "" || requested == caller:
+		return caller, nil
+	case !privileged:
+		return "", apperror.New(apperror.CODE_PERMISSION_DENIED, "permission denied: submitting jobs as user "+requested+" requires role operator")
+	}
+	return requested, nil
+}
+
+// HandlerSubmitJob 提交批处理作业.
+// @Summary 提交作业
+// @Description 提交批处理脚本, 对应 sbatch. 作业默认以登录用户的身份运行, 指定 user 为其他用户时需 operator 或 admin 角色. validate_only 为 true 时仅校验作业能否被调度(sbatch --test-only), 不实际提交;
+// @Description 官方 slurmrestd 不支持仅校验; 其以 X-SLURM-USER-NAME 指定提交用户, 集群的 slurmrestd_token 须为 root 或 SlurmUser 的令牌.
+// @Tags 作业管理
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param validate_only query bool false "仅校验, 不提交" default(false)
+// @Param body body JobSubmitParam true "脚本及作业选项"
+// @Success 200 {object} response.Response{results=model.JobSubmitResult}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 501 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/job [post]
+func (rt *Router) HandlerSubmitJob(c *gin.Context) {
+	var in JobSubmitParam
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	if msg := in.validate(); msg != "" {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: msg})
+		return
+	}
+	validateOnly, err := strconv.ParseBool(c.DefaultQuery("validate_only", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "validate_only 参数格式错误"})
+		return
+	}
+
+	user, err := submitUser(middleware.Username(c), in.User, middleware.HasRole(c, auth.ROLE_OPERATOR))
+	if err != nil {
+		c.JSON(apperror.Render(err, ""))
+		return
+	}
+
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	job := model.JobSubmission{
+		Script:      in.Script,
+		User:        user,
+		Name:        in.Name,
+		Partition:   in.Partition,
+		QoS:         in.QoS,
+		Account:     in.Account,
+		Nodes:       in.Nodes,
+		Tasks:       in.Tasks,
+		CPUsPerTask: in.CPUsPerTask,
+		TimeLimit:   in.TimeLimit,
+		Gres:        in.Gres,
+		Dependency:  in.Dependency,
+		Array:       in.Array,
+		WorkDir:     in.WorkDir,
+		Environment: in.Environment,
+	}
+	result, err := rt.slurmrestc(c).SubmitJob(c.Request.Context(), addr, job, validateOnly)
+	if err != nil {
+		middleware.Logger(c).Error("unable to submit job", "user", user, "validate_only", validateOnly, "err", err)
+		c.JSON(apperror.Render(err, "failed to submit job"))
+		return
+	}
+	if !validateOnly {
+		rt.backends.Invalidate(middleware.GetCluster(c).Name, slurmrest.CACHE_JOBS)
+	}
+	if result.Warnings == nil {
+		result.Warnings = []string{}
+	}
+	c.JSON(http.StatusOK, response.Response{Results: result})
+}
+
+// jobSortKeys 账户作业列表支持的排序字段, 前缀 - 表示降序.
+var jobSortKeys = []string{"jobid", "submit", "start", "end"}
+
+// AccountingJobQuery 账户作业列表的查询条件, 列表条件以逗号分隔, 匹配其中任一值.
+type AccountingJobQuery struct {
+	User       string  `form:"user"`        // 用户名
+	Account    string  `form:"account"`     // 账户
+	Partition  string  `form:"partition"`   // 分区
+	QoS        string  `form:"qos"`         // QoS 名称
+	State      string  `form:"state"`       // 状态, 如 RUNNING, COMPLETED, 大小写不敏感
+	SubmitFrom string  `form:"submit_from"` // 提交时间下限
+	SubmitTo   string  `form:"submit_to"`   // 提交时间上限
+	StartFrom  string  `form:"start_from"`  // 开始时间下限
+	StartTo    string  `form:"start_to"`    // 开始时间上限
+	EndFrom    string  `form:"end_from"`    // 结束时间下限
+	EndTo      string  `form:"end_to"`      // 结束时间上限
+	Name       string  `form:"name"`        // 作业名称, 支持 * 及 ? 通配符
+	ExitCode   *uint32 `form:"exit_code"`   // 退出码
+	Node       string  `form:"node"`        // 节点, 支持 hostlist 表达式, 如 cn[01-04]
+	Sort       string  `form:"sort"`        // 排序字段
+}
+
+// splitQuery 按逗号拆分查询参数, 忽略空项.
+func splitQuery(s string) []string {
+	list := make([]string, 0)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// parseQueryTime 解析时间查询参数, 支持 Unix 时间戳及 slurm.ParseTime 的格式, 为空时返回 0.
+func parseQueryTime(s string, now time.Time) (int64, error) {
+	if s = strings.TrimSpace(s); s == "" {
+		return 0, nil
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ts, nil
+	}
+	t, err := slurm.ParseTime(s, now)
+	if err != nil {
+		return 0, err
+	}
+	return t.Unix(), nil
+}
+
+// filter 校验查询条件并转换为 model.JobFilter, 失败时返回错误信息. 相对时间以当前时间为基准.
+func (q AccountingJobQuery) filter() (model.JobFilter, string) {
+	now := time.Now()
+	f := model.JobFilter{
+		Users:      splitQuery(q.User),
+		Accounts:   splitQuery(q.Account),
+		Partitions: splitQuery(q.Partition),
+		QoS:        splitQuery(q.QoS),
+		Name:       strings.TrimSpace(q.Name),
+		ExitCode:   q.ExitCode,
+		Sort:       strings.TrimSpace(q.Sort),
+	}
+	for _, s := range splitQuery(q.State) {
+		s = strings.ToUpper(s)
+		if !slurm.IsJobStateName(s) {
+			return f, "unknown job state: " + s
+		}
+		f.States = append(f.States, s)
+	}
+	for _, t := range []struct {
+		name  string
+		value string
+		dst   *int64
+	}{
+		{"submit_from", q.SubmitFrom, &f.SubmitFrom},
+		{"submit_to", q.SubmitTo, &f.SubmitTo},
+		{"start_from", q.StartFrom, &f.StartFrom},
+		{"start_to", q.StartTo, &f.StartTo},
+		{"end_from", q.EndFrom, &f.EndFrom},
+		{"end_to", q.EndTo, &f.EndTo},
+	} {
+		v, err := parseQueryTime(t.value, now)
+		if err != nil {
+			return f, t.name + ": " + err.Error()
+		}
+		*t.dst = v
+	}
+	for _, r := range [][2]int64{{f.SubmitFrom, f.SubmitTo}, {f.StartFrom, f.StartTo}, {f.EndFrom, f.EndTo}} {
+		if r[0] > 0 && r[1] > 0 && r[0] > r[1] {
+			return f, "time range lower bound is later than upper bound"
+		}
+	}
+	if f.Name != "" {
+		if _, err := path.Match(f.Name, ""); err != nil {
+			return f, "invalid name pattern: " + f.Name
+		}
+	}
+	if q.Node != "" {
+		nodes, err := hostlist.Expand(q.Node)
+		if err != nil {
+			return f, "invalid node: " + err.Error()
+		}
+		f.Nodes = hostlist.Uniq(nodes)
+	}
+	if f.Sort != "" && !slices.Contains(jobSortKeys, strings.TrimPrefix(f.Sort, "-")) {
+		return f, fmt.Sprintf("invalid sort key %q, must be one of %s (prefix - for descending)", f.Sort, strings.Join(jobSortKeys, ", "))
+	}
+	return f, ""
+}
+
+// schedulingSortKeys 调度队列作业列表支持的排序字段, 前缀 - 表示降序.
+var schedulingSortKeys = []string{"priority", "submit", "jobid"}
+
+// SchedulingJobQuery 调度队列作业列表的查询条件, 列表条件以逗号分隔, 匹配其中任一值.
+type SchedulingJobQuery struct {
+	User      string `form:"user"`      // 用户名
+	Account   string `form:"account"`   // 账户
+	Partition string `form:"partition"` // 分区
+	QoS       string `form:"qos"`       // QoS 名称
+	State     string `form:"state"`     // 状态, 如 PENDING, RUNNING, 大小写不敏感
+	Reason    string `form:"reason"`    // 原因, 如 Resources, Priority, 大小写不敏感
+	Node      string `form:"node"`      // 节点, 支持 hostlist 表达式, 如 cn[01-04]
+	Sort      string `form:"sort"`      // 排序字段
+}
+
+// filter 校验查询条件并转换为 model.SchedulingJobFilter, 失败时返回错误信息.
+func (q SchedulingJobQuery) filter() (model.SchedulingJobFilter, string) {
+	f := model.SchedulingJobFilter{
+		Users:      splitQuery(q.User),
+		Accounts:   splitQuery(q.Account),
+		Partitions: splitQuery(q.Partition),
+		QoS:        splitQuery(q.QoS),
+		Reasons:    splitQuery(q.Reason),
+		Sort:       strings.TrimSpace(q.Sort),
+	}
+	for _, s := range splitQuery(q.State) {
+		s = strings.ToUpper(s)
+		if !slurm.IsJobStateName(s) {
+			return f, "unknown job state: " + s
+		}
+		f.States = append(f.States, s)
+	}
+	if q.Node != "" {
+		nodes, err := hostlist.Expand(q.Node)
+		if err != nil {
+			return f, "invalid node: " + err.Error()
+		}
+		f.Nodes = hostlist.Uniq(nodes)
+	}
+	if f.Sort != "" && !slices.Contains(schedulingSortKeys, strings.TrimPrefix(f.Sort, "-")) {
+		return f, fmt.Sprintf("invalid sort key %q, must be one of %s (prefix - for descending)", f.Sort, strings.Join(schedulingSortKeys, ", "))
+	}
+	return f, ""
+}
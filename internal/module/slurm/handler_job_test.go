@@ -0,0 +1,37 @@
+package slurm
+
+import (
+	"testing"
+
+	"csjk-bk/internal/pkg/apperror"
+)
+
+func TestSubmitUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		caller     string
+		requested  string
+		privileged bool
+		want       string
+		code       apperror.Code
+	}{
+		{"caller", "alice", "", false, "alice", ""},
+		{"self", "alice", "alice", false, "alice", ""},
+		{"other user", "alice", "bob", false, "", apperror.CODE_PERMISSION_DENIED},
+		{"operator for other user", "alice", "bob", true, "bob", ""},
+		{"auth disabled", "", "bob", false, "bob", ""},
+		{"auth disabled without user", "", "", true, "", apperror.CODE_VALIDATION_FAILED},
+	}
+	for _, tt := range tests {
+		got, err := submitUser(tt.caller, tt.requested, tt.privileged)
+		if tt.code != "" {
+			if code := apperror.CodeOf(err); code != tt.code {
+				t.Errorf("%s: submitUser error code = %q (%v), want %q", tt.name, code, err, tt.code)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: submitUser = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
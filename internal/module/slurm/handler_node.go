@@ -0,0 +1,142 @@
+package slurm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/postgres/model"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/common/paging"
+	"csjk-bk/internal/pkg/common/slurm/hostlist"
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MAX_NODE_STATE_BATCH 单次节点状态变更的最大节点数.
+const MAX_NODE_STATE_BATCH = 1024
+
+type NodeStateParam struct {
+	State  slurmrest.NodeState `json:"state" binding:"required" example:"DRAIN"`         // 目标状态: DRAIN, RESUME, DOWN, REBOOT
+	Reason string              `json:"reason" binding:"required" example:"disk failure"` // 原因
+}
+
+// NodeStateResult 节点状态变更结果.
+type NodeStateResult struct {
+	Nodes  []string            `json:"nodes"`  // 展开后的节点列表
+	State  slurmrest.NodeState `json:"state"`  // 目标状态
+	Reason string              `json:"reason"` // 原因
+}
+
+// HandlerUpdateNodeState 设置节点状态.
+// @Summary 设置节点状态
+// @Description 对应 scontrol update nodename=... state=... reason=... 及 scontrol reboot. name 支持主机列表表达式(如 cn[001-004,010]),
+// @Description 最多 1024 个节点. 变更记录(操作者, 时间, 原因)可通过节点变更历史接口查看. 官方 slurmrestd 不支持 REBOOT.
+// @Tags 资源管理
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param name path string true "节点名称或主机列表表达式" example("cn[001-004]")
+// @Param body body NodeStateParam true "目标状态及原因"
+// @Success 200 {object} response.Response{results=NodeStateResult}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 501 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/node/{name}/state [put]
+func (rt *Router) HandlerUpdateNodeState(c *gin.Context) {
+	var in NodeStateParam
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	in.State = slurmrest.NodeState(strings.ToUpper(string(in.State)))
+	if !slices.Contains(slurmrest.NodeStates, in.State) {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("unknown node state: %s", in.State)})
+		return
+	}
+	if in.Reason = strings.TrimSpace(in.Reason); in.Reason == "" {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "reason is required"})
+		return
+	}
+
+	nodes, err := hostlist.Expand(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	nodes = hostlist.Uniq(nodes)
+	if len(nodes) == 0 || len(nodes) > MAX_NODE_STATE_BATCH {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: fmt.Sprintf("name must expand to 1 to %d nodes", MAX_NODE_STATE_BATCH)})
+		return
+	}
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	if err := rt.slurmrestc(c).UpdateNodeState(c.Request.Context(), cl.Slurmrestd, nodes, in.State, in.Reason); err != nil {
+		middleware.Logger(c).Error("unable to update node state", "nodes", c.Param("name"), "state", in.State, "err", err)
+		c.JSON(apperror.Render(err, "failed to update node state"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_NODES, slurmrest.CACHE_PARTITIONS)
+
+	changes := make(model.NodeStateChanges, 0, len(nodes))
+	for _, n := range nodes {
+		changes = append(changes, model.NodeStateChange{Cluster: cl.Name, Node: n, State: string(in.State), Reason: in.Reason, Actor: middleware.Username(c)})
+	}
+	if err := rt.db.AddNodeStateChanges(c.Request.Context(), changes); err != nil {
+		// 状态已生效, 仅记录失败
+		middleware.Logger(c).Error("unable to record node state changes", "nodes", c.Param("name"), "state", in.State, "err", err)
+	}
+
+	c.JSON(http.StatusOK, response.Response{Count: len(nodes), Results: NodeStateResult{Nodes: nodes, State: in.State, Reason: in.Reason}})
+}
+
+// HandlerGetNodeHistory 获取节点状态变更历史, 按时间降序排列.
+// @Summary 获取节点状态变更历史
+// @Description 通过本服务执行的 drain/resume/down/reboot 记录, 包括操作者, 时间及原因.
+// @Tags 资源管理
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param name path string true "节点名称" example("cn001")
+// @Param paging query bool false "是否分页" default(true)
+// @Param page query int false "页码" default(1) minimum(1)
+// @Param page_size query int false "每页数量, 最大仅支持100" default(20) minimum(1) maximum(100)
+// @Success 200 {object} response.Response{results=model.NodeStateChanges}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/node/{name}/history [get]
+func (rt *Router) HandlerGetNodeHistory(c *gin.Context) {
+	pq := paging.PagingQuery{Paging: true}
+	if err := c.ShouldBindQuery(&pq); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	pq.SetDefaults(1, 20, 100)
+
+	name := c.Param("name")
+	list, total, err := rt.db.GetNodeStateChanges(c.Request.Context(), middleware.GetCluster(c).Name, name, pq.Paging, pq.Page, pq.PageSize)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch node history"))
+		return
+	}
+
+	var prev, next url.URL
+	if pq.Paging {
+		prev, next = response.BuildPageLinks(c.Request.URL, pq.Page, pq.PageSize, total)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: total, Previous: prev, Next: next, Results: list})
+}
@@ -1,11 +1,21 @@
 package slurm
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
+	"reflect"
+	"slices"
 	"strconv"
+	"strings"
 
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
 	"csjk-bk/internal/pkg/common/paging"
+	"csjk-bk/internal/pkg/common/slurm"
+	"csjk-bk/internal/pkg/common/slurm/tres"
+	"csjk-bk/internal/pkg/middleware"
 	"csjk-bk/internal/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -35,13 +45,16 @@ type QoSListElem struct {
 // @Param page_size query int false "每页数量，1-100（仅当 paging=true 生效）" minimum(1) maximum(100) default(20)
 // @Success 200 {object} response.Response{results=QoSList}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/qos/list [get]
 func (rt *Router) HandlerGetQoSList(c *gin.Context) {
 	// 解析路径参数 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
@@ -50,20 +63,16 @@ func (rt *Router) HandlerGetQoSList(c *gin.Context) {
 	pq.SetDefaults(1, 20, 100)
 
 	// 查询 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 调用 slurmrest 客户端，获取当前页数据
-	items, total, err := rt.slurmrestc.GetQosAll(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
+	items, total, err := rt.slurmrestc(c).GetQosAll(c.Request.Context(), addr, pq.Paging, pq.Page, pq.PageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch qos list: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch qos list"))
 		return
 	}
 
@@ -128,6 +137,8 @@ type QoSDetail struct {
 	UsageFactor           float64 `json:"usage_factor"`              // 资源使用因子
 	UsageThres            float64 `json:"usage_thres"`               // 资源使用阈值
 	LimitFactor           float64 `json:"limit_factor"`              // 资源限制因子
+
+	TresResources map[string]tres.Resources `json:"tres_resources"` // TRES 限制的结构化表示, 键为上述 TRES 字段名, 未设置的字段不包含; *_mins 字段的值为分钟数
 }
 
 // @Summary 获取某集群中某个 QoS 详情
@@ -137,37 +148,36 @@ type QoSDetail struct {
 // @Param id path int true "QoS ID"
 // @Success 200 {object} response.Response{results=QoSDetail}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/qos/{id}/detail [get]
 func (rt *Router) HandlerGetQoSDetail(c *gin.Context) {
 	// 解析路径参数 cluster 和 id
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil || id <= 0 {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "invalid path param: id"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid path param: id"})
 		return
 	}
 
 	// 查询 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 调用 slurmrest 客户端
-	q, err := rt.slurmrestc.GetQos(c.Request.Context(), addr, uint32(id))
+	q, err := rt.slurmrestc(c).GetQos(c.Request.Context(), addr, uint32(id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch qos detail: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch qos detail"))
 		return
 	}
 
@@ -207,6 +217,19 @@ func (rt *Router) HandlerGetQoSDetail(c *gin.Context) {
 		UsageThres:            q.UsageThres,
 		// LimitFactor 不存在于模型中，保持为零值
 	}
+	out.TresResources = tresResources(rt.tresTable(c), map[string]string{
+		"max_tres_run_mins_pa": q.MaxTresRunMinsPA,
+		"grp_tres_run_mins":    q.GrpTresRunMins,
+		"max_tres_mins_pj":     q.MaxTresMinsPJ,
+		"max_tres_run_mins_pu": q.MaxTresRunMinsPU,
+		"grp_tres_mins":        q.GrpTresMins,
+		"max_tres_pa":          q.MaxTresPA,
+		"max_tres_pn":          q.MaxTresPN,
+		"min_tres_pj":          q.MinTresPJ,
+		"max_tres_pj":          q.MaxTresPJ,
+		"max_tres_pu":          q.MaxTresPU,
+		"grp_tres":             q.GrpTres,
+	})
 
 	c.JSON(http.StatusOK, response.Response{Results: out})
 }
@@ -219,31 +242,30 @@ type QoSNameList []string
 // @Param cluster path string true "集群名称" example("test")
 // @Success 200 {object} response.Response{results=QoSNameList}
 // @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
 // @Router /api/v1/{cluster}/slurm/qos/name/list [get]
 func (rt *Router) HandlerGetQosNameList(c *gin.Context) {
 	// 解析路径参数 cluster
 	cluster := c.Param("cluster")
 	if cluster == "" {
-		c.JSON(http.StatusBadRequest, response.Response{Detail: "missing cluster in path"})
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster in path"})
 		return
 	}
 
 	// 查询 slurmrestd 地址
-	addr, err := rt.db.GetSlurmrestdAddr(cluster)
-	if err != nil || addr == "" {
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to resolve slurmrestd address: " + err.Error()})
-		} else {
-			c.JSON(http.StatusInternalServerError, response.Response{Detail: "empty slurmrestd address for cluster"})
-		}
+	addr := middleware.GetCluster(c).Slurmrestd
+	if addr == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
 		return
 	}
 
 	// 获取全部 QoS 列表（不分页）
-	items, total, err := rt.slurmrestc.GetQosAll(c.Request.Context(), addr, false, 0, 0)
+	items, total, err := rt.slurmrestc(c).GetQosAll(c.Request.Context(), addr, false, 0, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Response{Detail: "failed to fetch qos list: " + err.Error()})
+		c.JSON(apperror.Render(err, "failed to fetch qos list"))
 		return
 	}
 
@@ -257,3 +279,428 @@ func (rt *Router) HandlerGetQosNameList(c *gin.Context) {
 
 	c.JSON(http.StatusOK, response.Response{Count: total, Results: names})
 }
+
+// QoSChange QoS 属性的变化, 未设置或已清除的值为空字符串.
+type QoSChange struct {
+	Field string `json:"field"` // 字段, 与请求体中的名称一致
+	Old   string `json:"old"`   // 当前值
+	New   string `json:"new"`   // 修改后的值
+}
+
+// QoSWriteResult 创建或修改 QoS 的结果.
+type QoSWriteResult struct {
+	Name    string      `json:"name"`    // QoS 名称
+	DryRun  bool        `json:"dry_run"` // 为 true 时未实际修改
+	Changes []QoSChange `json:"changes"` // 与当前 QoS(创建时为 Slurm 默认值)相比的变化
+}
+
+// mergeTres 返回将 set 中的 TRES 应用到 cur 之后的结果(与 sacctmgr 一致, 仅修改列出的 TRES), count 为 -1 的条目被删除.
+// cur 中的 TRES ID 通过 table 转换为名称; cur 无法解析时原样返回.
+func mergeTres(cur, set string, table tres.Table) string {
+	list, err := tres.Parse(cur, table)
+	if err != nil {
+		return cur
+	}
+	updates, _ := tres.Parse(set, table)
+	return tres.Merge(list, updates).String()
+}
+
+// displayTres 将当前的 TRES(ID 格式)转换为与 mergeTres 一致的格式, 用于比较.
+func displayTres(cur string, table tres.Table) string {
+	return mergeTres(cur, "", table)
+}
+
+func limitString(v int64) string {
+	if v < 0 {
+		return ""
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+func factorString(v float64) string {
+	if v < 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// preemptNames 返回排序后的 QoS 名称列表, 自定义 slurmrest 服务返回的 QoS ID 通过 names 转换为名称.
+func preemptNames(s string, names map[int32]string) string {
+	list := make([]string, 0)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if id, err := strconv.Atoi(v); err == nil && names[int32(id)] != "" {
+			v = names[int32(id)]
+		}
+		list = append(list, v)
+	}
+	slices.Sort(list)
+	return strings.Join(slices.Compact(list), ",")
+}
+
+// validateQoSWrite 校验 QoS 参数, 并将 TRES 字段规范化为 type[/name]=count(容量单位为 MB). qoses 为集群中现有的 QoS, 用于校验 preempt;
+// table 为集群的 TRES 表, 用于转换 ID 格式的 TRES.
+func validateQoSWrite(in *model.QoSWrite, qoses model.QoSes, table tres.Table) error {
+	if in.Name == "" || strings.ContainsAny(in.Name, ", \t=") {
+		return fmt.Errorf("invalid qos name: %q", in.Name)
+	}
+	if in.Flags != nil {
+		if _, err := slurm.ParseQoSFlags(*in.Flags); err != nil {
+			return err
+		}
+	}
+	if in.PreemptMode != nil {
+		if _, err := slurm.ParsePreemptMode(*in.PreemptMode); err != nil {
+			return err
+		}
+	}
+	if in.Preempt != nil {
+		for _, name := range strings.Split(*in.Preempt, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if name == in.Name {
+				return fmt.Errorf("qos can not preempt itself: %s", name)
+			}
+			if !slices.ContainsFunc(qoses, func(q model.QoS) bool { return q.Name == name }) {
+				return fmt.Errorf("preempt qos not found: %s", name)
+			}
+		}
+	}
+	limits := map[string]*int32{
+		"preempt_exempt_time":       in.PreemptExemptTime,
+		"grace_time":                in.GraceTime,
+		"max_jobs_pa":               in.MaxJobsPA,
+		"max_jobs_per_user":         in.MaxJobsPerUser,
+		"max_submit_jobs_pa":        in.MaxSubmitJobsPA,
+		"max_submit_jobs_per_user":  in.MaxSubmitJobsPerUser,
+		"max_wall_duration_per_job": in.MaxWallDurationPerJob,
+		"grp_jobs":                  in.GrpJobs,
+		"grp_submit_jobs":           in.GrpSubmitJobs,
+		"grp_wall":                  in.GrpWall,
+	}
+	for field, v := range limits {
+		if v != nil && *v < -1 {
+			return fmt.Errorf("%s must be -1 (unlimited) or non-negative", field)
+		}
+	}
+	if in.UsageFactor != nil && *in.UsageFactor < 0 && *in.UsageFactor != -1 {
+		return fmt.Errorf("usage_factor must be -1 or non-negative")
+	}
+	if in.UsageThres != nil && *in.UsageThres != -1 && (*in.UsageThres < 0 || *in.UsageThres > 1) {
+		return fmt.Errorf("usage_thres must be -1 or between 0 and 1")
+	}
+	for field, p := range qosTresFields(in) {
+		if *p == "" {
+			continue
+		}
+		list, err := tres.Parse(*p, table)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		*p = list.String()
+	}
+	return nil
+}
+
+func qosTresFields(in *model.QoSWrite) map[string]*string {
+	return map[string]*string{
+		"max_tres_pa":          &in.MaxTresPA,
+		"max_tres_pj":          &in.MaxTresPJ,
+		"max_tres_pn":          &in.MaxTresPN,
+		"max_tres_pu":          &in.MaxTresPU,
+		"max_tres_mins_pj":     &in.MaxTresMinsPJ,
+		"max_tres_run_mins_pa": &in.MaxTresRunMinsPA,
+		"max_tres_run_mins_pu": &in.MaxTresRunMinsPU,
+		"min_tres_pj":          &in.MinTresPJ,
+		"grp_tres":             &in.GrpTres,
+		"grp_tres_mins":        &in.GrpTresMins,
+		"grp_tres_run_mins":    &in.GrpTresRunMins,
+	}
+}
+
+// qosChanges 比较当前 QoS(cur)与修改参数(in), 返回值发生变化的字段, 顺序与 model.QoSWrite 的字段一致.
+// names 为 QoS ID 到名称的映射, table 为集群的 TRES 表.
+func qosChanges(cur model.QoS, in model.QoSWrite, names map[int32]string, table tres.Table) []QoSChange {
+	changes := make([]QoSChange, 0)
+	add := func(field, old, new string) {
+		if old != new {
+			changes = append(changes, QoSChange{Field: field, Old: old, New: new})
+		}
+	}
+	if in.Description != nil {
+		add("description", cur.Description, *in.Description)
+	}
+	if in.Flags != nil {
+		flags, _ := slurm.ParseQoSFlags(*in.Flags)
+		add("flags", strings.Join(slurm.QoSFlagNames(cur.Flags, false), ","), strings.Join(slurm.QoSFlagNames(flags, false), ","))
+	}
+	if in.Priority != nil {
+		add("priority", strconv.FormatUint(uint64(cur.Priority), 10), strconv.FormatUint(uint64(*in.Priority), 10))
+	}
+	if in.Preempt != nil {
+		add("preempt", preemptNames(cur.Preempt, names), preemptNames(*in.Preempt, nil))
+	}
+	if in.PreemptMode != nil {
+		mode, _ := slurm.ParsePreemptMode(*in.PreemptMode)
+		add("preempt_mode", slurm.PreemptModeString(uint16(cur.PreemptMode)), slurm.PreemptModeString(mode))
+	}
+	if in.UsageFactor != nil {
+		add("usage_factor", factorString(cur.UsageFactor), factorString(*in.UsageFactor))
+	}
+	if in.UsageThres != nil {
+		add("usage_thres", factorString(cur.UsageThres), factorString(*in.UsageThres))
+	}
+
+	limits := []struct {
+		field string
+		cur   int64
+		new   *int32
+	}{
+		{"preempt_exempt_time", int64(cur.PreemptExemptTime), in.PreemptExemptTime},
+		{"grace_time", int64(cur.GraceTime), in.GraceTime},
+		{"max_jobs_pa", int64(cur.MaxJobsPA), in.MaxJobsPA},
+		{"max_jobs_per_user", int64(cur.MaxJobsPerUser), in.MaxJobsPerUser},
+		{"max_submit_jobs_pa", int64(cur.MaxSubmitJobsPA), in.MaxSubmitJobsPA},
+		{"max_submit_jobs_per_user", int64(cur.MaxSubmitJobsPerUser), in.MaxSubmitJobsPerUser},
+		{"max_wall_duration_per_job", int64(cur.MaxWallDurationPerJob), in.MaxWallDurationPerJob},
+		{"grp_jobs", int64(cur.GrpJobs), in.GrpJobs},
+		{"grp_submit_jobs", int64(cur.GrpSubmitJobs), in.GrpSubmitJobs},
+		{"grp_wall", int64(cur.GrpWall), in.GrpWall},
+	}
+	for _, l := range limits {
+		if l.new != nil {
+			add(l.field, limitString(l.cur), limitString(int64(*l.new)))
+		}
+	}
+
+	tres := []struct {
+		field    string
+		cur, new string
+	}{
+		{"max_tres_pa", cur.MaxTresPA, in.MaxTresPA},
+		{"max_tres_pj", cur.MaxTresPJ, in.MaxTresPJ},
+		{"max_tres_pn", cur.MaxTresPN, in.MaxTresPN},
+		{"max_tres_pu", cur.MaxTresPU, in.MaxTresPU},
+		{"max_tres_mins_pj", cur.MaxTresMinsPJ, in.MaxTresMinsPJ},
+		{"max_tres_run_mins_pa", cur.MaxTresRunMinsPA, in.MaxTresRunMinsPA},
+		{"max_tres_run_mins_pu", cur.MaxTresRunMinsPU, in.MaxTresRunMinsPU},
+		{"min_tres_pj", cur.MinTresPJ, in.MinTresPJ},
+		{"grp_tres", cur.GrpTres, in.GrpTres},
+		{"grp_tres_mins", cur.GrpTresMins, in.GrpTresMins},
+		{"grp_tres_run_mins", cur.GrpTresRunMins, in.GrpTresRunMins},
+	}
+	for _, t := range tres {
+		if t.new != "" {
+			add(t.field, displayTres(t.cur, table), mergeTres(t.cur, t.new, table))
+		}
+	}
+	return changes
+}
+
+// defaultQoS 新建 QoS 时 Slurm 使用的默认值, 用于创建时的对比.
+func defaultQoS(name string) model.QoS {
+	return model.QoS{
+		Name:                  name,
+		UsageFactor:           1,
+		UsageThres:            -1,
+		MaxJobsPA:             -1,
+		MaxJobsPerUser:        -1,
+		MaxSubmitJobsPA:       -1,
+		MaxSubmitJobsPerUser:  -1,
+		MaxWallDurationPerJob: -1,
+		GrpJobs:               -1,
+		GrpSubmitJobs:         -1,
+		GrpWall:               -1,
+	}
+}
+
+// HandlerCreateQoS 创建 QoS.
+// @Summary 创建 QoS
+// @Description 对应 sacctmgr add qos. 未设置的字段使用 Slurm 默认值. TRES 字段格式为 type[/name]=count, 逗号分隔, 如 cpu=64,mem=256G,gres/gpu=8,
+// @Description 服务端校验类型及数值, 容量转换为 MB. dry_run 为 true 时仅校验并返回与默认值相比的变化, 不实际创建.
+// @Tags 资源管理, QoS
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param dry_run query bool false "仅校验并返回变化, 不创建" default(false)
+// @Param body body model.QoSWrite true "QoS 属性"
+// @Success 200 {object} response.Response{results=QoSWriteResult}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/qos [post]
+func (rt *Router) HandlerCreateQoS(c *gin.Context) {
+	var in model.QoSWrite
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid query param: dry_run"})
+		return
+	}
+	in.Name = strings.TrimSpace(in.Name)
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	qoses, _, err := rt.slurmrestc(c).GetQosAll(c.Request.Context(), cl.Slurmrestd, false, 0, 0)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch qos list"))
+		return
+	}
+	table := rt.tresTable(c)
+	if err := validateQoSWrite(&in, qoses, table); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	names := make(map[int32]string, len(qoses))
+	for _, q := range qoses {
+		if q.Name == in.Name {
+			c.JSON(http.StatusConflict, response.Response{Code: string(apperror.CODE_CONFLICT), Detail: "qos already exists: " + in.Name})
+			return
+		}
+		names[q.ID] = q.Name
+	}
+
+	result := QoSWriteResult{Name: in.Name, DryRun: dryRun, Changes: qosChanges(defaultQoS(in.Name), in, names, table)}
+	if !dryRun {
+		if err := rt.slurmrestc(c).CreateQoS(c.Request.Context(), cl.Slurmrestd, in); err != nil {
+			middleware.Logger(c).Error("unable to create qos", "qos", in.Name, "err", err)
+			c.JSON(apperror.Render(err, "failed to create qos"))
+			return
+		}
+		rt.backends.Invalidate(cl.Name, slurmrest.CACHE_QOS)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: len(result.Changes), Results: result})
+}
+
+// HandlerUpdateQoS 修改 QoS.
+// @Summary 修改 QoS
+// @Description 对应 sacctmgr modify qos, 请求体中未设置的字段不修改, 请求体中的 name 忽略. 数值限制为 -1 表示清除限制;
+// @Description TRES 字段仅修改列出的 TRES, count 为 -1 表示清除该 TRES 的限制. dry_run 为 true 时仅校验并返回与当前 QoS 相比的变化, 不实际修改.
+// @Description 没有变化时不调用 slurmrestd.
+// @Tags 资源管理, QoS
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param name path string true "QoS 名称" example("normal")
+// @Param dry_run query bool false "仅校验并返回变化, 不修改" default(false)
+// @Param body body model.QoSWrite true "需修改的 QoS 属性"
+// @Success 200 {object} response.Response{results=QoSWriteResult}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/qos/{name} [put]
+func (rt *Router) HandlerUpdateQoS(c *gin.Context) {
+	var in model.QoSWrite
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid query param: dry_run"})
+		return
+	}
+	in.Name = c.Param("name")
+	if reflect.DeepEqual(in, model.QoSWrite{Name: in.Name}) {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "nothing to update"})
+		return
+	}
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	qoses, _, err := rt.slurmrestc(c).GetQosAll(c.Request.Context(), cl.Slurmrestd, false, 0, 0)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch qos list"))
+		return
+	}
+	table := rt.tresTable(c)
+	if err := validateQoSWrite(&in, qoses, table); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	names := make(map[int32]string, len(qoses))
+	idx := -1
+	for i, q := range qoses {
+		names[q.ID] = q.Name
+		if q.Name == in.Name {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_NOT_FOUND), Detail: "qos not found: " + in.Name})
+		return
+	}
+
+	result := QoSWriteResult{Name: in.Name, DryRun: dryRun, Changes: qosChanges(qoses[idx], in, names, table)}
+	if !dryRun && len(result.Changes) > 0 {
+		if err := rt.slurmrestc(c).UpdateQoS(c.Request.Context(), cl.Slurmrestd, in); err != nil {
+			middleware.Logger(c).Error("unable to update qos", "qos", in.Name, "err", err)
+			c.JSON(apperror.Render(err, "failed to update qos"))
+			return
+		}
+		rt.backends.Invalidate(cl.Name, slurmrest.CACHE_QOS)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: len(result.Changes), Results: result})
+}
+
+// HandlerDeleteQoS 删除 QoS.
+// @Summary 删除 QoS
+// @Description 对应 sacctmgr delete qos. 关联(association)中对该 QoS 的引用由 Slurm 一并移除.
+// @Tags 资源管理, QoS
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param name path string true "QoS 名称" example("normal")
+// @Success 200 {object} response.Response{results=string}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/qos/{name} [delete]
+func (rt *Router) HandlerDeleteQoS(c *gin.Context) {
+	name := c.Param("name")
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	qoses, _, err := rt.slurmrestc(c).GetQosAll(c.Request.Context(), cl.Slurmrestd, false, 0, 0)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch qos list"))
+		return
+	}
+	if !slices.ContainsFunc(qoses, func(q model.QoS) bool { return q.Name == name }) {
+		c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_NOT_FOUND), Detail: "qos not found: " + name})
+		return
+	}
+
+	if err := rt.slurmrestc(c).DeleteQoS(c.Request.Context(), cl.Slurmrestd, name); err != nil {
+		middleware.Logger(c).Error("unable to delete qos", "qos", name, "err", err)
+		c.JSON(apperror.Render(err, "failed to delete qos"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_QOS, slurmrest.CACHE_ACCOUNTS)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
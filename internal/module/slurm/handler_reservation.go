@@ -0,0 +1,440 @@
+package slurm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	dbpg "csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+	"csjk-bk/internal/pkg/common/paging"
+	"csjk-bk/internal/pkg/common/slurm"
+	"csjk-bk/internal/pkg/common/slurm/hostlist"
+	"csjk-bk/internal/pkg/middleware"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reservationName 申请未指定预约名称时使用的名称, 以申请 ID 区分.
+func reservationName(id int) string {
+	return fmt.Sprintf("resv_app%d", id)
+}
+
+// validateReservation 校验申请内容能否用于创建预约: 需指定开始时间, 持续时间, 以及节点列表或节点数.
+func validateReservation(ac ApplicationContent) error {
+	if strings.TrimSpace(ac.StartTime) == "" || strings.TrimSpace(ac.Duration) == "" {
+		return fmt.Errorf("start and duration are required when approve=true")
+	}
+	if _, err := slurm.ParseTime(ac.StartTime, time.Now()); err != nil {
+		return err
+	}
+	if _, err := slurm.ParseDuration(ac.Duration); err != nil {
+		return err
+	}
+	if strings.TrimSpace(ac.Nodes) == "" && ac.NodeCnt <= 0 {
+		return fmt.Errorf("nodes or node_cnt is required when approve=true")
+	}
+	if nodes := strings.TrimSpace(ac.Nodes); nodes != "" {
+		if _, err := hostlist.Expand(nodes); err != nil {
+			return fmt.Errorf("invalid nodes: %w", err)
+		}
+	}
+	if ac.NodeCnt < 0 {
+		return fmt.Errorf("node_cnt must not be negative")
+	}
+	return nil
+}
+
+// reservationOf 根据申请内容构造预约参数, 同时指定 Nodes 及 NodeCnt 时以 Nodes 为准.
+func reservationOf(ac ApplicationContent) model.Reservation {
+	resv := model.Reservation{
+		Name:      strings.TrimSpace(ac.ReservationName),
+		Partition: strings.TrimSpace(ac.PartitionName),
+		StartTime: strings.TrimSpace(ac.StartTime),
+		Duration:  strings.TrimSpace(ac.Duration),
+		Nodes:     strings.TrimSpace(ac.Nodes),
+		Flags:     strings.TrimSpace(ac.Flags),
+		Users:     strings.TrimSpace(ac.User),
+	}
+	if resv.Nodes == "" {
+		resv.NodeCnt = ac.NodeCnt
+	}
+	return resv
+}
+
+// HandlerGetReservations 获取集群中的资源预约
+// @Summary 获取资源预约列表
+// @Description 对应 scontrol show reservation, 包括通过申请审核创建的预约及管理员直接创建的预约.
+// @Tags 资源管理, 资源预约
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param paging query bool false "是否分页" default(true)
+// @Param page query int false "页码" default(1) minimum(1)
+// @Param page_size query int false "每页数量, 最大仅支持100" default(20) minimum(1) maximum(100)
+// @Success 200 {object} response.Response{results=model.Reservations}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/reservation/list [get]
+func (rt *Router) HandlerGetReservations(c *gin.Context) {
+	pq := paging.PagingQuery{Paging: true}
+	if err := c.ShouldBindQuery(&pq); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	pq.SetDefaults(1, 20, 100)
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	list, total, err := rt.slurmrestc(c).GetReservations(c.Request.Context(), cl.Slurmrestd, pq.Paging, pq.Page, pq.PageSize)
+	if err != nil {
+		middleware.Logger(c).Error("unable to get reservations", "err", err)
+		c.JSON(apperror.Render(err, "failed to fetch reservations"))
+		return
+	}
+
+	var prev, next url.URL
+	if pq.Paging {
+		prev, next = response.BuildPageLinks(c.Request.URL, pq.Page, pq.PageSize, total)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: total, Previous: prev, Next: next, Results: list})
+}
+
+// HandlerUpdateReservation 更新资源预约
+// @Summary 更新资源预约
+// @Description 对应 scontrol update reservation, 请求体中为空的字段不修改, 请求体中的 name 忽略.
+// @Description 官方 slurmrestd v0.0.43 之前的接口不支持.
+// @Tags 资源管理, 资源预约
+// @Accept json
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param name path string true "预约名称" example("resv_app1")
+// @Param body body model.Reservation true "预约参数"
+// @Success 200 {object} response.Response{results=string}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 501 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/reservation/{name} [put]
+func (rt *Router) HandlerUpdateReservation(c *gin.Context) {
+	var in model.Reservation
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid request body: " + err.Error()})
+		return
+	}
+	in.Name = c.Param("name")
+	if in == (model.Reservation{Name: in.Name}) {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "nothing to update"})
+		return
+	}
+	if in.NodeCnt < 0 {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "node_cnt must not be negative"})
+		return
+	}
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	if err := rt.slurmrestc(c).UpdateReservation(c.Request.Context(), cl.Slurmrestd, in); err != nil {
+		middleware.Logger(c).Error("unable to update reservation", "reservation", in.Name, "err", err)
+		c.JSON(apperror.Render(err, "failed to update reservation"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_NODES)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+// HandlerDeleteReservation 删除资源预约
+// @Summary 删除资源预约
+// @Description 对应 scontrol delete reservation. 官方 slurmrestd v0.0.43 之前的接口不支持.
+// @Tags 资源管理, 资源预约
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param name path string true "预约名称" example("resv_app1")
+// @Success 200 {object} response.Response{results=string}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 501 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/reservation/{name} [delete]
+func (rt *Router) HandlerDeleteReservation(c *gin.Context) {
+	name := c.Param("name")
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+
+	if err := rt.slurmrestc(c).DeleteReservation(c.Request.Context(), cl.Slurmrestd, name); err != nil {
+		middleware.Logger(c).Error("unable to delete reservation", "reservation", name, "err", err)
+		c.JSON(apperror.Render(err, "failed to delete reservation"))
+		return
+	}
+	rt.backends.Invalidate(cl.Name, slurmrest.CACHE_NODES)
+	c.JSON(http.StatusOK, response.Response{Results: "ok"})
+}
+
+// 预约冲突类型.
+const (
+	CONFLICT_NODE_NOT_FOUND = "node_not_found" // 节点不存在
+	CONFLICT_NODE_STATE     = "node_state"     // 节点不可用(down, drain 等)
+	CONFLICT_RESERVATION    = "reservation"    // 与已有预约的时间及节点重叠
+	CONFLICT_JOB            = "job"            // 节点上运行中的作业在预约开始前不会结束
+)
+
+// unavailableNodeStates 节点状态中包含这些标志时不能用于预约.
+var unavailableNodeStates = []string{"DOWN", "DRAIN", "FAIL", "MAINT", "FUTURE", "NOT_RESPONDING", "UNKNOWN", "POWER"}
+
+// ReservationConflict 预约冲突.
+type ReservationConflict struct {
+	Node   string `json:"node"`   // 节点名称
+	Kind   string `json:"kind"`   // 冲突类型: node_not_found, node_state, reservation, job
+	Detail string `json:"detail"` // 节点状态, 预约名称或作业号等
+}
+
+// ReservationCheck 预约申请检查结果.
+type ReservationCheck struct {
+	StartTime         time.Time             `json:"start_time"`         // 申请的开始时间
+	EndTime           time.Time             `json:"end_time"`           // 申请的结束时间, 不限时长时为零值
+	Requested         []string              `json:"requested"`          // 申请的节点(展开后), 仅指定节点数时为空
+	NodeCnt           int                   `json:"node_cnt"`           // 需要的节点数
+	OK                bool                  `json:"ok"`                 // 申请的节点无冲突且属于申请的分区, 可直接审核通过
+	Conflicts         []ReservationConflict `json:"conflicts"`          // 申请节点的冲突
+	PartitionMismatch []string              `json:"partition_mismatch"` // 不属于申请分区的节点
+	Suggested         []string              `json:"suggested"`          // 建议的无冲突节点, 优先保留申请中无冲突的节点
+	SuggestedNodes    string                `json:"suggested_nodes"`    // 建议节点的主机列表表达式, 如 cn[01-04], 可直接用作预约的 nodes
+	Satisfiable       bool                  `json:"satisfiable"`        // 建议的节点是否满足需要的节点数
+}
+
+// window 时间段 [start, end), end 为零值表示不限时长.
+type window struct {
+	start time.Time
+	end   time.Time
+}
+
+func (w window) overlaps(o window) bool {
+	return (w.end.IsZero() || o.start.Before(w.end)) && (o.end.IsZero() || w.start.Before(o.end))
+}
+
+// windowOf 根据开始时间及结束时间或时长计算时间段.
+func windowOf(start, end, duration string, now time.Time) (window, error) {
+	var w window
+	var err error
+	if w.start, err = slurm.ParseTime(start, now); err != nil {
+		return w, err
+	}
+	if end != "" {
+		w.end, err = slurm.ParseTime(end, now)
+		return w, err
+	}
+	d, err := slurm.ParseDuration(duration)
+	if err != nil {
+		return w, err
+	}
+	if d != slurm.UNLIMITED {
+		w.end = w.start.Add(d)
+	}
+	return w, nil
+}
+
+// nodeUnavailable 判断节点状态(如 IDLE+DRAIN, down*)是否不能用于预约.
+func nodeUnavailable(state string) bool {
+	state = strings.ToUpper(state)
+	for _, s := range unavailableNodeStates {
+		if strings.Contains(state, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// HandlerCheckReservationApp 检查资源预约申请能否满足
+// @Summary 检查资源预约申请
+// @Description 审核前检查申请的节点(nodes 或 node_cnt)在申请的时间段内是否可用: 节点是否存在及其状态, 是否与已有预约重叠,
+// @Description 节点上运行中的作业是否在开始时间前结束, 以及节点是否属于申请的分区. 同时给出满足节点数的无冲突节点建议.
+// @Description 普通用户仅能检查本人的申请.
+// @Tags 资源管理, 资源预约
+// @Produce json
+// @Param cluster path string true "集群名称" example("test")
+// @Param id path int true "申请ID"
+// @Success 200 {object} response.Response{results=ReservationCheck}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Failure 502 {object} response.Response
+// @Failure 504 {object} response.Response
+// @Router /api/v1/{cluster}/slurm/reservation/application/{id}/check [get]
+func (rt *Router) HandlerCheckReservationApp(c *gin.Context) {
+	id, err := strconv.Atoi(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "invalid application id: must be integer"})
+		return
+	}
+	if !rt.checkApplicationOwner(c, id) {
+		return
+	}
+	app, err := rt.db.GetApplication(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch application"))
+		return
+	}
+	if app.Class != dbpg.APPLICATION_CLASS_RESOURCE {
+		c.JSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_APPLICATION_NOT_FOUND), Detail: fmt.Sprintf("application not found: id=%d", id)})
+		return
+	}
+	var ac ApplicationContent
+	if err := json.Unmarshal([]byte(app.Content), &ac); err != nil {
+		c.JSON(apperror.Render(err, "failed to parse application content"))
+		return
+	}
+	if err := validateReservation(ac); err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	want, err := windowOf(ac.StartTime, "", ac.Duration, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+		return
+	}
+	check := ReservationCheck{StartTime: want.start, EndTime: want.end, NodeCnt: ac.NodeCnt}
+	if nodes := strings.TrimSpace(ac.Nodes); nodes != "" {
+		requested, err := hostlist.Expand(nodes)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: err.Error()})
+			return
+		}
+		check.Requested = hostlist.Uniq(requested)
+		check.NodeCnt = len(check.Requested)
+	}
+
+	cl := middleware.GetCluster(c)
+	if cl.Slurmrestd == "" {
+		c.JSON(http.StatusInternalServerError, response.Response{Code: string(apperror.CODE_CLUSTER_MISCONFIGURED), Detail: "empty slurmrestd address for cluster"})
+		return
+	}
+	ctx, backend := c.Request.Context(), rt.slurmrestc(c)
+	nodes, _, err := backend.GetNodes(ctx, cl.Slurmrestd, nil, false, 0, 0)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch nodes"))
+		return
+	}
+	reservations, _, err := backend.GetReservations(ctx, cl.Slurmrestd, false, 0, 0)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch reservations"))
+		return
+	}
+	jobs, _, err := backend.GetSchedulingJobs(ctx, cl.Slurmrestd, model.SchedulingJobFilter{}, false, 0, 0)
+	if err != nil {
+		c.JSON(apperror.Render(err, "failed to fetch jobs"))
+		return
+	}
+
+	// 各节点在申请时间段内的冲突
+	conflicts := make(map[string][]ReservationConflict)
+	byName := make(map[string]*model.Node, len(nodes))
+	for _, n := range nodes {
+		byName[n.Name] = n
+		if nodeUnavailable(n.State) {
+			conflicts[n.Name] = append(conflicts[n.Name], ReservationConflict{Node: n.Name, Kind: CONFLICT_NODE_STATE, Detail: n.State})
+		}
+	}
+	for _, r := range reservations {
+		// 已为该申请创建的预约不算冲突
+		if r.Name == strings.TrimSpace(ac.ReservationName) && (app.State == dbpg.APPLICATION_STATE_PASSED || app.State == dbpg.APPLICATION_STATE_ACTIVE) {
+			continue
+		}
+		w, err := windowOf(r.StartTime, r.EndTime, r.Duration, time.Now())
+		if err == nil && !w.overlaps(want) {
+			continue
+		}
+		hosts, err := hostlist.Expand(r.Nodes)
+		if err != nil {
+			middleware.Logger(c).Warn("unable to expand reservation nodes", "reservation", r.Name, "nodes", r.Nodes, "err", err)
+			continue
+		}
+		for _, h := range hosts {
+			conflicts[h] = append(conflicts[h], ReservationConflict{Node: h, Kind: CONFLICT_RESERVATION, Detail: r.Name})
+		}
+	}
+	for _, j := range jobs {
+		state := strings.ToUpper(j.State)
+		if !strings.Contains(state, "RUNNING") && !strings.Contains(state, "SUSPENDED") && !strings.Contains(state, "COMPLETING") {
+			continue
+		}
+		// 结束时间未知或不限时的作业视为与申请时间段重叠
+		if j.TimeEnd > 0 && !time.Unix(j.TimeEnd, 0).After(want.start) {
+			continue
+		}
+		hosts, err := hostlist.Expand(j.Nodelist)
+		if err != nil {
+			middleware.Logger(c).Warn("unable to expand job nodes", "jobid", j.Jobid, "nodes", j.Nodelist, "err", err)
+			continue
+		}
+		for _, h := range hosts {
+			conflicts[h] = append(conflicts[h], ReservationConflict{Node: h, Kind: CONFLICT_JOB, Detail: j.Jobid})
+		}
+	}
+
+	partition := strings.TrimSpace(ac.PartitionName)
+	inPartition := func(n *model.Node) bool {
+		return partition == "" || slices.Contains(n.Partition, partition)
+	}
+
+	check.Conflicts = []ReservationConflict{}
+	check.PartitionMismatch = []string{}
+	check.Suggested = []string{}
+	for _, h := range check.Requested {
+		n, ok := byName[h]
+		if !ok {
+			check.Conflicts = append(check.Conflicts, ReservationConflict{Node: h, Kind: CONFLICT_NODE_NOT_FOUND})
+			continue
+		}
+		check.Conflicts = append(check.Conflicts, conflicts[h]...)
+		if !inPartition(n) {
+			check.PartitionMismatch = append(check.PartitionMismatch, h)
+		}
+		if len(conflicts[h]) == 0 && inPartition(n) {
+			check.Suggested = append(check.Suggested, h)
+		}
+	}
+	check.OK = len(check.Requested) > 0 && len(check.Conflicts) == 0 && len(check.PartitionMismatch) == 0
+
+	// 申请的节点不足时从分区内其余无冲突节点中补充
+	available := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if len(conflicts[n.Name]) == 0 && inPartition(n) {
+			available = append(available, n.Name)
+		}
+	}
+	candidates := hostlist.Difference(available, check.Requested)
+	if short := check.NodeCnt - len(check.Suggested); short > 0 {
+		check.Suggested = append(check.Suggested, candidates[:min(short, len(candidates))]...)
+	}
+	check.Satisfiable = len(check.Suggested) >= check.NodeCnt
+	check.SuggestedNodes = hostlist.Compress(check.Suggested)
+
+	c.JSON(http.StatusOK, response.Response{Results: check})
+}
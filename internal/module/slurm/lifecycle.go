@@ -0,0 +1,218 @@
+package slurm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/alertmanager"
+	dbpg "csjk-bk/internal/pkg/client/postgres"
+	pgmodel "csjk-bk/internal/pkg/client/postgres/model"
+	"csjk-bk/internal/pkg/client/slurmrest"
+)
+
+// ALERT_RESERVATION_EXPIRING 到期提醒的报警名称(alertname 标签).
+const ALERT_RESERVATION_EXPIRING = "ReservationExpiring"
+
+// Lifecycle 定期扫描已通过(APPLICATION_STATE_PASSED)及已生效(APPLICATION_STATE_ACTIVE)的资源预约申请,
+// 并与 Slurm 中的预约对账:
+//   - 开始时间已到且 Slurm 预约存在: 通过 -> 生效;
+//   - 开始时间加持续时间已过: -> 已到期, 释放残留的 Slurm 预约;
+//   - 到期前 Slurm 预约已不存在(被管理员删除等): -> 已取消;
+//   - 到期前 notifyBefore 通过 Alertmanager 提醒申请人, 每个申请仅提醒一次.
+//
+// 每次扫描持有 advisory lock(dbpg.LOCK_RESERVATION_LIFECYCLE), 多个实例同时运行时仅一个实例扫描, 提醒不会重复发送;
+// 状态更新以申请的当前状态为条件, 审核人同时修改时不会重复变更.
+type Lifecycle struct {
+	db           *dbpg.Client
+	backends     *slurmrest.Backends
+	am           *alertmanager.Client
+	interval     time.Duration
+	notifyBefore time.Duration
+	logger       *slog.Logger
+}
+
+func NewLifecycle(db *dbpg.Client, backends *slurmrest.Backends, am *alertmanager.Client, interval, notifyBefore time.Duration, logger *slog.Logger) *Lifecycle {
+	return &Lifecycle{
+		db:           db,
+		backends:     backends,
+		am:           am,
+		interval:     interval,
+		notifyBefore: notifyBefore,
+		logger:       logger.With("component", "reservation_lifecycle"),
+	}
+}
+
+// Run 立即扫描一次, 之后每隔 interval 扫描一次, 直到 ctx 取消.
+func (l *Lifecycle) Run(ctx context.Context) {
+	l.logger.Info("reservation lifecycle started", "interval", l.interval, "notify_before", l.notifyBefore)
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		l.scan(ctx)
+		select {
+		case <-ctx.Done():
+			l.logger.Info("reservation lifecycle stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// liveCluster 集群及其当前的 Slurm 预约, 每次扫描获取一次.
+type liveCluster struct {
+	cluster      pgmodel.Cluster
+	backend      slurmrest.Backend
+	reservations map[string]bool
+	err          error
+}
+
+func (l *Lifecycle) scan(ctx context.Context) {
+	unlock, ok, err := l.db.TryAdvisoryLock(ctx, dbpg.LOCK_RESERVATION_LIFECYCLE)
+	if err != nil {
+		l.logger.Error("unable to acquire reservation lifecycle lock", "err", err)
+		return
+	}
+	if !ok {
+		l.logger.Debug("reservation lifecycle is running on another instance, skip")
+		return
+	}
+	defer unlock()
+
+	apps, err := l.db.GetApplicationsByState(ctx, dbpg.APPLICATION_CLASS_RESOURCE, dbpg.APPLICATION_STATE_PASSED, dbpg.APPLICATION_STATE_ACTIVE)
+	if err != nil {
+		l.logger.Error("unable to fetch approved reservation applications", "err", err)
+		return
+	}
+
+	clusters := make(map[string]*liveCluster)
+	live := func(name string) *liveCluster {
+		if lc, ok := clusters[name]; ok {
+			return lc
+		}
+		lc := &liveCluster{}
+		clusters[name] = lc
+		if lc.cluster, lc.err = l.db.GetCluster(ctx, name); lc.err != nil {
+			l.logger.Error("unable to fetch cluster", "cluster", name, "err", lc.err)
+			return lc
+		}
+		lc.backend = l.backends.For(lc.cluster)
+		list, _, err := lc.backend.GetReservations(ctx, lc.cluster.Slurmrestd, false, 0, 0)
+		if lc.err = err; err != nil {
+			l.logger.Error("unable to fetch reservations", "cluster", name, "err", err)
+			return lc
+		}
+		lc.reservations = make(map[string]bool, len(list))
+		for _, r := range list {
+			lc.reservations[r.Name] = true
+		}
+		return lc
+	}
+
+	now := time.Now()
+	for _, app := range apps {
+		if ctx.Err() != nil {
+			return
+		}
+		l.reconcile(ctx, app, now, live)
+	}
+}
+
+// reconcile 根据时间及 Slurm 中的预约更新申请(app)的状态, 并在到期前发送提醒.
+func (l *Lifecycle) reconcile(ctx context.Context, app dbpg.Application, now time.Time, live func(string) *liveCluster) {
+	var ac ApplicationContent
+	if err := json.Unmarshal([]byte(app.Content), &ac); err != nil {
+		l.logger.Warn("unable to parse application content", "application", app.ID, "err", err)
+		return
+	}
+	// 相对时间(如 now+1hour)以审核时间为基准
+	ref := app.ReviewAt
+	if ref.IsZero() {
+		ref = app.ApplyAt
+	}
+	w, err := windowOf(ac.StartTime, "", ac.Duration, ref)
+	if err != nil {
+		l.logger.Warn("invalid reservation window", "application", app.ID, "err", err)
+		return
+	}
+	expired := !w.end.IsZero() && !now.Before(w.end)
+
+	// 迁移前的历史申请未记录集群, 仅按时间变更状态
+	var lc *liveCluster
+	if app.Cluster != "" {
+		if lc = live(app.Cluster); lc.err != nil {
+			return
+		}
+	}
+
+	switch {
+	case expired:
+		if lc != nil && lc.reservations[ac.ReservationName] {
+			err := lc.backend.DeleteReservation(ctx, lc.cluster.Slurmrestd, ac.ReservationName)
+			if err != nil && apperror.CodeOf(err) != apperror.CODE_NOT_FOUND {
+				l.logger.Error("unable to release expired reservation", "application", app.ID, "cluster", app.Cluster, "reservation", ac.ReservationName, "err", err)
+				return
+			}
+			l.backends.Invalidate(app.Cluster, slurmrest.CACHE_NODES)
+		}
+		l.transit(ctx, app, dbpg.APPLICATION_STATE_EXPIRED)
+		return
+	case lc != nil && !lc.reservations[ac.ReservationName]:
+		l.transit(ctx, app, dbpg.APPLICATION_STATE_CANCELLED)
+		return
+	case app.State == dbpg.APPLICATION_STATE_PASSED && !now.Before(w.start):
+		l.transit(ctx, app, dbpg.APPLICATION_STATE_ACTIVE)
+	}
+
+	if l.notifyBefore > 0 && !w.end.IsZero() && app.NotifiedAt.IsZero() && !now.Before(w.end.Add(-l.notifyBefore)) {
+		l.notify(ctx, app, ac, lc, w.end)
+	}
+}
+
+func (l *Lifecycle) transit(ctx context.Context, app dbpg.Application, to int) {
+	ok, err := l.db.TransitApplication(ctx, app.ID, app.State, to)
+	if err != nil {
+		l.logger.Error("unable to update application state", "application", app.ID, "from", app.State, "to", to, "err", err)
+		return
+	}
+	if ok {
+		l.logger.Info("reservation application state changed", "application", app.ID, "cluster", app.Cluster, "from", ApplicationStateMaps[app.State], "to", ApplicationStateMaps[to])
+	}
+}
+
+// notify 通过 Alertmanager 提醒申请人预约即将到期, 报警在到期时自动恢复.
+func (l *Lifecycle) notify(ctx context.Context, app dbpg.Application, ac ApplicationContent, lc *liveCluster, end time.Time) {
+	var server string
+	if lc != nil {
+		server = lc.cluster.AlertmanagerURL
+	}
+	alert := alertmanager.PostableAlert{
+		StartsAt: time.Now(),
+		EndsAt:   end,
+		Labels: map[string]string{
+			"alertname":   ALERT_RESERVATION_EXPIRING,
+			"severity":    "info",
+			"cluster":     app.Cluster,
+			"user":        app.Applier,
+			"reservation": ac.ReservationName,
+		},
+		Annotations: map[string]string{
+			"summary":     "资源预约即将到期",
+			"description": fmt.Sprintf("资源预约 %s(申请 %d, 节点 %s)将于 %s 到期, 到期后预约将被释放.", ac.ReservationName, app.ID, ac.Nodes, end.Format(time.DateTime)),
+		},
+	}
+	if err := l.am.PostAlerts(ctx, server, []alertmanager.PostableAlert{alert}); err != nil {
+		if errors.Is(err, alertmanager.ErrNoServer) {
+			return
+		}
+		l.logger.Error("unable to notify reservation expiry", "application", app.ID, "err", err)
+		return
+	}
+	if err := l.db.MarkApplicationNotified(ctx, app.ID); err != nil {
+		l.logger.Error("unable to mark application notified", "application", app.ID, "err", err)
+	}
+}
@@ -1,24 +1,27 @@
 package slurm
 
 import (
+	"csjk-bk/internal/pkg/auth"
 	"csjk-bk/internal/pkg/client/postgres"
 	"csjk-bk/internal/pkg/client/slurmrest"
+	"csjk-bk/internal/pkg/common/slurm/tres"
+	"csjk-bk/internal/pkg/middleware"
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
-	db         *postgres.Client
-	slurmrestc *slurmrest.Client
-	logger     *slog.Logger
+	db       *postgres.Client
+	backends *slurmrest.Backends
+	logger   *slog.Logger
 }
 
-func NewRouter(db *postgres.Client, slurmrestc *slurmrest.Client, logger *slog.Logger) *Router {
+func NewRouter(db *postgres.Client, backends *slurmrest.Backends, logger *slog.Logger) *Router {
 	return &Router{
-		db:         db,
-		slurmrestc: slurmrestc,
-		logger:     logger,
+		db:       db,
+		backends: backends,
+		logger:   logger,
 	}
 }
 
@@ -26,27 +29,89 @@ func (rt *Router) Register(r *gin.Engine) {
 	rt.logger.Debug("register slrum router")
 	v1 := r.Group("/api/v1/")
 	{
-		g := v1.Group("/:cluster/slurm")
-		g.GET("/overview", rt.HandlerGetOverview)                                              // GET /api/v1/:cluster/slurm/overview
-		g.GET("/qos/list", rt.HandlerGetQoSList)                                               // GET /api/v1/:cluster/slurm/qos/list?paging=xxx&page=xxx&page_size=xxx
-		g.GET("qos/name/list", rt.HandlerGetQosNameList)                                       // GET /api/v1/:cluster/slurm/qos/name/list
-		g.GET("/qos/:id/detail", rt.HandlerGetQoSDetail)                                       // GET /api/v1/:cluster/slurm/qos/:id/detail
-		g.GET("/account/name/list", rt.HandlerGetAccountsNameList)                             // GET /api/v1/:cluster/slurm/account/name/list
-		g.GET("/account/:account/childnodes", rt.HandlerGetAccountChildNodes)                  // GET /api/v1/:cluster/slurm//account/:account/childnodes
-		g.GET("/association/:account/childnodes", rt.HandlerGetAssociationChildNodesOfAccount) // GET /api/v1/:cluster/slurm/association/:account/childnodes
-		g.GET("/association/detail", rt.HandlerGetAssociationDetail)                           // GET /api/v1/:cluster/slurm/association/detail?account=xxx&user=xxx&partition=xxx
-		g.GET("/accounting/job/list", rt.HandlerGetJobListFromAccounting)                      // GET /api/v1/:cluster/slurm/accounting//job/list?paging=xxx&page=xxx&page_size=xxx
-		g.GET("/accounting/job/:jobid/detail", rt.HandlerGetAccountingJobDetail)               // GET /api/v1/:cluster/slurm/accounting/job/:jobid/detail
-		g.GET("/partition/list", rt.HandlerGetPartitionList)                                   // GET /api/v1/:cluster/slurm/partition/list?paging=xxx&page=xxx&page_size=xxx
-		g.GET("partition/:name/detail", rt.HandlerGetPartitionDetail)                          // GET /api/v1/:cluster/slurm/partition/:name/detail
-		g.GET("/scheduling/job/list", rt.HandlerGetSchedulingJobList)                          // GET /api/v1/:cluster/slurm/scheduling/job/list
-		g.GET("/scheduling/job/:jobid/detail", rt.HandlerGetSchedulingJobsDetail)              // GET /api/v1/:cluster/slurm/scheduling/job/:jobid/detail
-		g.GET("/reservation/applications", rt.HandlerGetReservationApps)                       // GET /api/v1/:cluster/slurm/reservation/applications?applier=xxx&paging=xxx&page=xxx&page_size=xxx
-		g.GET("/reservation/application/:id/decision", rt.HandlerGetApplicationDecision)       // GET /api/v1/:cluster/slurm/reservation/application/:id/decision
-		g.POST("/reservation/application", rt.HandlerCreateApplication)                        // POST /api/v1/:cluster/slurm/reservation/application
-		g.PUT("/reservation/application/:id", rt.HandlerUpdateApplication)                     // PUT /api/v1/:cluster/slurm/reservation/application/:id
-		g.DELETE("/reservation/application/:id", rt.HandlerDelApplication)                     // DELETE /api/v1/:cluster/slurm/reservation/application/:id
-		g.PUT("/reservation/application/:id/review", rt.HandlerRevireApplication)              // PUT /api/v1/:cluster/slurm/reservation/application/:id/review
-		g.GET("/nodes", rt.HandlerGetAllNodes)                                                 // GET /api/v1/:cluster/slurm/nodes?partition=xxx&paging=xxx&page_size=xxx
+		// 权限: 查询类接口所有登录用户可用, 申请列表/修改/删除及作业控制在 handler 中限制普通用户仅能操作本人的申请/作业.
+		user, reviewer := middleware.RequireRole(auth.ROLE_USER), middleware.RequireRole(auth.ROLE_REVIEWER)
+		operator := middleware.RequireRole(auth.ROLE_OPERATOR)
+		g := v1.Group("/:cluster/slurm", middleware.ResolveCluster(rt.db))
+		g.GET("/overview", user, rt.HandlerGetOverview)                                              // GET /api/v1/:cluster/slurm/overview
+		g.GET("/qos/list", user, rt.HandlerGetQoSList)                                               // GET /api/v1/:cluster/slurm/qos/list?paging=xxx&page=xxx&page_size=xxx
+		g.GET("qos/name/list", user, rt.HandlerGetQosNameList)                                       // GET /api/v1/:cluster/slurm/qos/name/list
+		g.GET("/qos/:id/detail", user, rt.HandlerGetQoSDetail)                                       // GET /api/v1/:cluster/slurm/qos/:id/detail
+		g.POST("/qos", operator, rt.HandlerCreateQoS)                                                // POST /api/v1/:cluster/slurm/qos?dry_run=xxx
+		g.PUT("/qos/:name", operator, rt.HandlerUpdateQoS)                                           // PUT /api/v1/:cluster/slurm/qos/:name?dry_run=xxx
+		g.DELETE("/qos/:name", operator, rt.HandlerDeleteQoS)                                        // DELETE /api/v1/:cluster/slurm/qos/:name
+		g.GET("/account/name/list", user, rt.HandlerGetAccountsNameList)                             // GET /api/v1/:cluster/slurm/account/name/list
+		g.GET("/account/:account/childnodes", user, rt.HandlerGetAccountChildNodes)                  // GET /api/v1/:cluster/slurm//account/:account/childnodes
+		g.POST("/account", operator, rt.HandlerCreateAccount)                                        // POST /api/v1/:cluster/slurm/account
+		g.DELETE("/account/:account", operator, rt.HandlerDeleteAccount)                             // DELETE /api/v1/:cluster/slurm/account/:account
+		g.GET("/association/:account/childnodes", user, rt.HandlerGetAssociationChildNodesOfAccount) // GET /api/v1/:cluster/slurm/association/:account/childnodes
+		g.GET("/association/detail", user, rt.HandlerGetAssociationDetail)                           // GET /api/v1/:cluster/slurm/association/detail?account=xxx&user=xxx&partition=xxx
+		g.POST("/association", operator, rt.HandlerAddUserAssociation)                               // POST /api/v1/:cluster/slurm/association
+		g.PUT("/association", operator, rt.HandlerUpdateAssociation)                                 // PUT /api/v1/:cluster/slurm/association
+		g.PUT("/user/:user/default_account", operator, rt.HandlerSetDefaultAccount)                  // PUT /api/v1/:cluster/slurm/user/:user/default_account
+		g.GET("/accounting/job/list", user, rt.HandlerGetJobListFromAccounting)                      // GET /api/v1/:cluster/slurm/accounting/job/list?paging=xxx&page=xxx&page_size=xxx&user=xxx&state=xxx&sort=xxx...
+		g.GET("/accounting/job/:jobid/detail", user, rt.HandlerGetAccountingJobDetail)               // GET /api/v1/:cluster/slurm/accounting/job/:jobid/detail
+		g.GET("/partition/list", user, rt.HandlerGetPartitionList)                                   // GET /api/v1/:cluster/slurm/partition/list?paging=xxx&page=xxx&page_size=xxx
+		g.GET("partition/:name/detail", user, rt.HandlerGetPartitionDetail)                          // GET /api/v1/:cluster/slurm/partition/:name/detail
+		g.GET("/scheduling/job/list", user, rt.HandlerGetSchedulingJobList)                          // GET /api/v1/:cluster/slurm/scheduling/job/list?paging=xxx&page=xxx&page_size=xxx&user=xxx&state=xxx&reason=xxx&node=xxx&sort=xxx...
+		g.GET("/scheduling/job/:jobid/detail", user, rt.HandlerGetSchedulingJobsDetail)              // GET /api/v1/:cluster/slurm/scheduling/job/:jobid/detail
+		g.GET("/reservation/applications", user, rt.HandlerGetReservationApps)                       // GET /api/v1/:cluster/slurm/reservation/applications?applier=xxx&paging=xxx&page=xxx&page_size=xxx
+		g.GET("/reservation/application/:id/decision", user, rt.HandlerGetApplicationDecision)       // GET /api/v1/:cluster/slurm/reservation/application/:id/decision
+		g.POST("/reservation/application", user, rt.HandlerCreateApplication)                        // POST /api/v1/:cluster/slurm/reservation/application
+		g.PUT("/reservation/application/:id", user, rt.HandlerUpdateApplication)                     // PUT /api/v1/:cluster/slurm/reservation/application/:id
+		g.DELETE("/reservation/application/:id", user, rt.HandlerDelApplication)                     // DELETE /api/v1/:cluster/slurm/reservation/application/:id
+		g.GET("/reservation/application/:id/check", user, rt.HandlerCheckReservationApp)             // GET /api/v1/:cluster/slurm/reservation/application/:id/check
+		g.PUT("/reservation/application/:id/review", reviewer, rt.HandlerRevireApplication)          // PUT /api/v1/:cluster/slurm/reservation/application/:id/review
+		g.GET("/reservation/list", user, rt.HandlerGetReservations)                                  // GET /api/v1/:cluster/slurm/reservation/list?paging=xxx&page=xxx&page_size=xxx
+		g.PUT("/reservation/:name", operator, rt.HandlerUpdateReservation)                           // PUT /api/v1/:cluster/slurm/reservation/:name
+		g.DELETE("/reservation/:name", operator, rt.HandlerDeleteReservation)                        // DELETE /api/v1/:cluster/slurm/reservation/:name
+		g.GET("/nodes", user, rt.HandlerGetAllNodes)                                                 // GET /api/v1/:cluster/slurm/nodes?partition=xxx&nodes=xxx&paging=xxx&page_size=xxx
+		g.DELETE("/cache", operator, rt.HandlerInvalidateCache)                                      // DELETE /api/v1/:cluster/slurm/cache?resource=xxx
+		g.PUT("/node/:name/state", operator, rt.HandlerUpdateNodeState)                              // PUT /api/v1/:cluster/slurm/node/:name/state
+		g.GET("/node/:name/history", user, rt.HandlerGetNodeHistory)                                 // GET /api/v1/:cluster/slurm/node/:name/history?paging=xxx&page=xxx&page_size=xxx
+		g.POST("/job", user, rt.HandlerSubmitJob)                                                    // POST /api/v1/:cluster/slurm/job?validate_only=xxx
+		g.POST("/job/:jobid/:action", user, rt.HandlerControlJob)                                    // POST /api/v1/:cluster/slurm/job/:jobid/:action
 	}
 }
+
+// slurmrestc 返回当前集群(由 ResolveCluster 解析)接口类型对应的 slurmrestd 客户端.
+func (rt *Router) slurmrestc(c *gin.Context) slurmrest.Backend {
+	return rt.backends.For(middleware.GetCluster(c))
+}
+
+// tresTable 返回集群的 TRES 表, 获取失败时返回 nil(仅识别固定 ID 的类型).
+func (rt *Router) tresTable(c *gin.Context) tres.Table {
+	list, err := rt.slurmrestc(c).GetTres(c.Request.Context(), middleware.GetCluster(c).Slurmrestd)
+	if err != nil {
+		middleware.Logger(c).Warn("unable to fetch tres table", "err", err)
+		return nil
+	}
+	defs := make([]tres.Def, 0, len(list))
+	for _, t := range list {
+		defs = append(defs, tres.Def{ID: int64(t.ID), Type: t.Type, Name: t.Name})
+	}
+	return tres.NewTable(defs)
+}
+
+// tresResources 将 TRES 字段(字段名到原始字符串)转换为结构化表示, 空字段及无法解析的字段忽略.
+func tresResources(table tres.Table, fields map[string]string) map[string]tres.Resources {
+	return parseTresFields(tres.Parse, table, fields)
+}
+
+// tresUsage 同 tresResources, 用于作业步的 TRES 用量字段, 见 tres.ParseUsage.
+func tresUsage(table tres.Table, fields map[string]string) map[string]tres.Resources {
+	return parseTresFields(tres.ParseUsage, table, fields)
+}
+
+func parseTresFields(parse func(string, tres.Table) (tres.List, error), table tres.Table, fields map[string]string) map[string]tres.Resources {
+	out := make(map[string]tres.Resources, len(fields))
+	for field, s := range fields {
+		if s == "" {
+			continue
+		}
+		if list, err := parse(s, table); err == nil {
+			out[field] = list.Resources()
+		}
+	}
+	return out
+}
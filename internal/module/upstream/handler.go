@@ -0,0 +1,27 @@
+package upstream
+
+import (
+	"net/http"
+
+	"csjk-bk/internal/pkg/client/transport"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandlerGetUpstreams 获取各上游服务地址的熔断器状态.
+// @Summary 获取上游服务熔断状态
+// @Description 获取 slurmrestd, KD Lustre, Alertmanager 各地址的熔断器状态. 仅包含服务启动后访问过的地址;
+// @Description state 为 open 时发往该地址的请求直接返回 UPSTREAM_UNAVAILABLE, 冷却结束后转为 half_open 并放行一个探测请求.
+// @Tags 上游服务
+// @Produce json
+// @Success 200 {object} response.Response{results=[]transport.BreakerStatus}
+// @Failure 403 {object} response.Response
+// @Router /api/v1/upstreams [get]
+func (rt *Router) HandlerGetUpstreams(c *gin.Context) {
+	list := []transport.BreakerStatus{}
+	for _, t := range rt.transports {
+		list = append(list, t.Status()...)
+	}
+	c.JSON(http.StatusOK, response.Response{Count: len(list), Results: list})
+}
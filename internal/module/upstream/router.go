@@ -0,0 +1,29 @@
+package upstream
+
+import (
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/client/transport"
+	"csjk-bk/internal/pkg/middleware"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Router struct {
+	transports []*transport.Transport
+	logger     *slog.Logger
+}
+
+func NewRouter(logger *slog.Logger, transports ...*transport.Transport) *Router {
+	return &Router{transports: transports, logger: logger}
+}
+
+func (rt *Router) Register(r *gin.Engine) {
+	rt.logger.Debug("register upstream router")
+	// 权限: 上游熔断状态仅全局管理员可查看.
+	admin := middleware.RequireRole(auth.ROLE_ADMIN)
+	v1 := r.Group("/api/v1/upstreams")
+	{
+		v1.GET("", admin, rt.HandlerGetUpstreams) // GET /api/v1/upstreams
+	}
+}
@@ -0,0 +1,142 @@
+// Package apperror 定义带稳定错误码的错误类型, 以及错误码与 HTTP 状态码的映射.
+// 客户端包返回 *Error(或以 %w 包装的 *Error), handler 通过 Render 构造带 code 字段的响应.
+package apperror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"csjk-bk/internal/pkg/response"
+)
+
+// Code 稳定的错误码, 作为 response.Response.Code 返回给调用方, 不随错误描述变化.
+type Code string
+
+const (
+	CODE_VALIDATION_FAILED     Code = "VALIDATION_FAILED"     // 请求参数错误
+	CODE_UNAUTHENTICATED       Code = "UNAUTHENTICATED"       // 未登录, 令牌无效或用户名密码错误
+	CODE_PERMISSION_DENIED     Code = "PERMISSION_DENIED"     // 无权限
+	CODE_NOT_FOUND             Code = "NOT_FOUND"             // 资源不存在(如上游服务返回 404)
+	CODE_CLUSTER_NOT_FOUND     Code = "CLUSTER_NOT_FOUND"     // 集群未注册
+	CODE_APPLICATION_NOT_FOUND Code = "APPLICATION_NOT_FOUND" // 申请不存在
+	CODE_CONFLICT              Code = "CONFLICT"              // 资源已存在或状态冲突
+	CODE_CLUSTER_MISCONFIGURED Code = "CLUSTER_MISCONFIGURED" // 集群缺少所需的依赖服务地址等配置
+	CODE_UPSTREAM_ERROR        Code = "UPSTREAM_ERROR"        // 上游服务(slurmrestd, KD Lustre, Alertmanager, ipmitool)返回错误
+	CODE_UPSTREAM_UNAVAILABLE  Code = "UPSTREAM_UNAVAILABLE"  // 无法连接上游服务
+	CODE_UPSTREAM_TIMEOUT      Code = "UPSTREAM_TIMEOUT"      // 上游服务超时
+	CODE_UNSUPPORTED           Code = "UNSUPPORTED"           // 集群所用的上游服务不支持该操作(如官方 slurmrestd 不提供 Ldap 管理)
+	CODE_INTERNAL              Code = "INTERNAL"              // 其他内部错误
+)
+
+var statusOf = map[Code]int{
+	CODE_VALIDATION_FAILED:     http.StatusBadRequest,
+	CODE_UNAUTHENTICATED:       http.StatusUnauthorized,
+	CODE_PERMISSION_DENIED:     http.StatusForbidden,
+	CODE_NOT_FOUND:             http.StatusNotFound,
+	CODE_CLUSTER_NOT_FOUND:     http.StatusNotFound,
+	CODE_APPLICATION_NOT_FOUND: http.StatusNotFound,
+	CODE_CONFLICT:              http.StatusConflict,
+	CODE_CLUSTER_MISCONFIGURED: http.StatusInternalServerError,
+	CODE_UPSTREAM_ERROR:        http.StatusBadGateway,
+	CODE_UPSTREAM_UNAVAILABLE:  http.StatusBadGateway,
+	CODE_UPSTREAM_TIMEOUT:      http.StatusGatewayTimeout,
+	CODE_UNSUPPORTED:           http.StatusNotImplemented,
+	CODE_INTERNAL:              http.StatusInternalServerError,
+}
+
+// Status 返回错误码对应的 HTTP 状态码, 未知错误码返回 500.
+func Status(code Code) int {
+	if s, ok := statusOf[code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// Error 带错误码的错误. 可直接作为哨兵错误使用(如 postgres.ErrApplicationNotFound), 以 %w 包装后
+// errors.Is 仍然成立, CodeOf 也可取得其错误码.
+type Error struct {
+	Code    Code
+	Message string
+	Err     error // 底层错误, 可为空
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// New 创建错误码为 code 的错误.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap 使用错误码 code 包装 err.
+func Wrap(code Code, err error, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Upstream 根据上游服务(upstream)返回的非 2xx 状态码构造错误, detail 为上游返回的错误描述, 可为空.
+// 上游的 404 映射为 NOT_FOUND, 400/422 映射为 VALIDATION_FAILED, 408/504 映射为 UPSTREAM_TIMEOUT,
+// 其余映射为 UPSTREAM_ERROR.
+func Upstream(upstream string, statusCode int, detail string) *Error {
+	code := CODE_UPSTREAM_ERROR
+	switch statusCode {
+	case http.StatusNotFound:
+		code = CODE_NOT_FOUND
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		code = CODE_VALIDATION_FAILED
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		code = CODE_UPSTREAM_TIMEOUT
+	}
+	msg := fmt.Sprintf("%s returned unexpected status code: %d", upstream, statusCode)
+	if detail = strings.TrimSpace(detail); detail != "" {
+		msg += " (" + detail + ")"
+	}
+	return New(code, msg)
+}
+
+// CodeOf 返回 err 的错误码: 链中存在 *Error 时取其错误码; 超时(context.DeadlineExceeded 或网络超时)
+// 为 UPSTREAM_TIMEOUT; 其他网络错误为 UPSTREAM_UNAVAILABLE; 否则为 INTERNAL.
+func CodeOf(err error) Code {
+	var ae *Error
+	if errors.As(err, &ae) {
+		return ae.Code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CODE_UPSTREAM_TIMEOUT
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return CODE_UPSTREAM_TIMEOUT
+	}
+	var ue *url.Error
+	var oe *net.OpError
+	if errors.As(err, &ue) || errors.As(err, &oe) {
+		return CODE_UPSTREAM_UNAVAILABLE
+	}
+	return CODE_INTERNAL
+}
+
+// Render 根据 err 构造 HTTP 状态码及响应, Detail 为 "message: err", message 为空时为 err. 用法:
+//
+//	c.JSON(apperror.Render(err, "failed to fetch partitions"))
+func Render(err error, message string) (int, response.Response) {
+	code := CodeOf(err)
+	detail := message
+	if err != nil {
+		if detail != "" {
+			detail += ": "
+		}
+		detail += err.Error()
+	}
+	return Status(code), response.Response{Code: string(code), Detail: detail}
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxy 从受信任的反向代理(如统一认证网关)设置的请求头中读取调用者身份.
+// 仅当请求的直连地址(RemoteAddr)位于受信任网段时才读取请求头, 避免客户端伪造.
+type TrustedProxy struct {
+	userHeader   string
+	groupsHeader string
+	prefixes     []netip.Prefix
+}
+
+// NewTrustedProxy 创建 TrustedProxy. cidrs 为受信任代理的网段, 如 10.0.0.0/8 或单个地址 10.0.0.1;
+// groupsHeader 中的多个组以逗号分隔.
+func NewTrustedProxy(userHeader, groupsHeader string, cidrs []string) (*TrustedProxy, error) {
+	tp := &TrustedProxy{userHeader: userHeader, groupsHeader: groupsHeader}
+	for _, s := range cidrs {
+		s = strings.TrimSpace(s)
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy address %q: %w", s, err)
+			}
+			tp.prefixes = append(tp.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy cidr %q: %w", s, err)
+		}
+		tp.prefixes = append(tp.prefixes, p.Masked())
+	}
+	return tp, nil
+}
+
+// Identity 返回代理请求头中的身份. 请求不是来自受信任代理或未设置用户名请求头时, 第二个返回值为 false.
+func (tp *TrustedProxy) Identity(r *http.Request) (Identity, bool) {
+	if tp == nil || !tp.trusted(r.RemoteAddr) {
+		return Identity{}, false
+	}
+	user := strings.TrimSpace(r.Header.Get(tp.userHeader))
+	if user == "" {
+		return Identity{}, false
+	}
+	id := Identity{Username: user}
+	if tp.groupsHeader != "" {
+		for _, g := range strings.Split(r.Header.Get(tp.groupsHeader), ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				id.Groups = append(id.Groups, g)
+			}
+		}
+	}
+	return id, true
+}
+
+func (tp *TrustedProxy) trusted(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range tp.prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
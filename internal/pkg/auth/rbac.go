@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"csjk-bk/internal/pkg/client/postgres/model"
+)
+
+// Role 调用者在某集群下的角色.
+type Role string
+
+// 角色. admin 拥有全部权限; reviewer 审核资源预约及配额申请; operator 执行运维操作(如带外传感器设置);
+// user 为所有已认证用户, 仅可管理本人的申请.
+const (
+	ROLE_ADMIN    Role = "admin"
+	ROLE_REVIEWER Role = "reviewer"
+	ROLE_OPERATOR Role = "operator"
+	ROLE_USER     Role = "user"
+)
+
+// Roles 调用者拥有的角色集合.
+type Roles []Role
+
+// Has 判断是否拥有 want 中任一角色, admin 视为拥有全部角色.
+func (rs Roles) Has(want ...Role) bool {
+	if slices.Contains(rs, ROLE_ADMIN) {
+		return true
+	}
+	for _, w := range want {
+		if slices.Contains(rs, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleStore 提供某集群下 Ldap 组与角色的映射.
+type RoleStore interface {
+	GetClusterRoles(ctx context.Context, cluster string) (model.ClusterRoles, error)
+}
+
+// Authorizer 根据调用者所属 Ldap 组解析其在某集群下的角色.
+type Authorizer struct {
+	store       RoleStore
+	adminGroups []string
+}
+
+// NewAuthorizer 创建 Authorizer. adminGroups 中任一组的成员在所有集群下均为 admin.
+func NewAuthorizer(store RoleStore, adminGroups []string) *Authorizer {
+	return &Authorizer{store: store, adminGroups: adminGroups}
+}
+
+// Roles 返回 id 在集群(cluster)下的角色, 已认证用户总是拥有 user 角色. cluster 为空时仅判断全局管理员组.
+func (a *Authorizer) Roles(ctx context.Context, id Identity, cluster string) (Roles, error) {
+	roles := Roles{ROLE_USER}
+	for _, g := range id.Groups {
+		if slices.Contains(a.adminGroups, g) {
+			return append(roles, ROLE_ADMIN), nil
+		}
+	}
+	if cluster == "" {
+		return roles, nil
+	}
+	mappings, err := a.store.GetClusterRoles(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range mappings {
+		r := Role(m.Role)
+		if slices.Contains(id.Groups, m.Group) && !slices.Contains(roles, r) {
+			roles = append(roles, r)
+		}
+	}
+	return roles, nil
+}
@@ -0,0 +1,109 @@
+// Package auth 提供登录身份校验及会话令牌(JWT)的签发与解析.
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 令牌类型
+const (
+	TOKEN_TYPE_ACCESS  = "access"
+	TOKEN_TYPE_REFRESH = "refresh"
+)
+
+// ErrInvalidToken 令牌无效, 过期或类型不符.
+var ErrInvalidToken = apperror.New(apperror.CODE_UNAUTHENTICATED, "invalid token")
+
+// Identity 已认证的调用者身份.
+type Identity struct {
+	Username string   `json:"username"` // Ldap 用户名
+	Cluster  string   `json:"cluster"`  // 登录时校验身份的集群
+	Groups   []string `json:"groups"`   // Ldap 附加组
+}
+
+// TokenPair 登录/刷新返回的令牌.
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`       // 访问令牌, 通过 Authorization: Bearer <token> 传递
+	AccessExpiresAt  time.Time `json:"access_expires_at"`  // 访问令牌过期时间
+	RefreshToken     string    `json:"refresh_token"`      // 刷新令牌, 仅用于 /api/v1/auth/refresh
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // 刷新令牌过期时间
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Type    string   `json:"typ"`
+	Cluster string   `json:"cluster"`
+	Groups  []string `json:"groups,omitempty"`
+}
+
+// Issuer 使用 HS256 签发及校验令牌.
+type Issuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+func NewIssuer(secret []byte, accessTTL, refreshTTL time.Duration) *Issuer {
+	return &Issuer{secret: secret, accessTTL: accessTTL, refreshTTL: refreshTTL, now: time.Now}
+}
+
+// Issue 为 id 签发访问令牌及刷新令牌.
+func (is *Issuer) Issue(id Identity) (TokenPair, error) {
+	now := is.now()
+	var tp TokenPair
+	var err error
+	tp.AccessExpiresAt = now.Add(is.accessTTL)
+	tp.AccessToken, err = is.sign(id, TOKEN_TYPE_ACCESS, now, tp.AccessExpiresAt)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	tp.RefreshExpiresAt = now.Add(is.refreshTTL)
+	tp.RefreshToken, err = is.sign(id, TOKEN_TYPE_REFRESH, now, tp.RefreshExpiresAt)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return tp, nil
+}
+
+func (is *Issuer) sign(id Identity, typ string, now, exp time.Time) (string, error) {
+	c := claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   id.Username,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+		Type:    typ,
+		Cluster: id.Cluster,
+		Groups:  id.Groups,
+	}
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(is.secret)
+	if err != nil {
+		return "", fmt.Errorf("unable to sign token: %w", err)
+	}
+	return s, nil
+}
+
+// Parse 校验令牌签名, 有效期及类型(typ), 返回令牌中的身份.
+func (is *Issuer) Parse(token, typ string) (Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		return is.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithTimeFunc(is.now), jwt.WithExpirationRequired())
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if c.Type != typ {
+		return Identity{}, fmt.Errorf("%w: unexpected token type %q", ErrInvalidToken, c.Type)
+	}
+	if c.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	return Identity{Username: c.Subject, Cluster: c.Cluster, Groups: c.Groups}, nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/postgres/model"
+	"csjk-bk/internal/pkg/client/slurmrest"
+)
+
+// ErrInvalidCredentials 用户名或密码错误.
+var ErrInvalidCredentials = apperror.New(apperror.CODE_UNAUTHENTICATED, "invalid username or password")
+
+// ErrUserNotFound Ldap 用户不存在, 如刷新令牌时用户已被删除.
+var ErrUserNotFound = apperror.New(apperror.CODE_UNAUTHENTICATED, "user not found")
+
+// Verifier 校验某集群下的用户名及密码, 成功时返回用户身份.
+type Verifier interface {
+	Verify(ctx context.Context, cluster model.Cluster, username, password string) (Identity, error)
+	// Lookup 重新获取用户在某集群下的身份(附加组), 用于刷新令牌; 用户不存在时返回 ErrUserNotFound.
+	Lookup(ctx context.Context, cluster model.Cluster, username string) (Identity, error)
+}
+
+// SlurmrestVerifier 通过自定义 slurmrest 服务的 Ldap 代理接口校验密码并获取用户附加组.
+// 使用官方 slurmrestd 的集群不提供 Ldap 代理接口, 校验时返回 slurmrest.ErrUnsupported, 需通过受信任代理认证.
+type SlurmrestVerifier struct {
+	backends *slurmrest.Backends
+}
+
+func NewSlurmrestVerifier(backends *slurmrest.Backends) *SlurmrestVerifier {
+	return &SlurmrestVerifier{backends: backends}
+}
+
+func (v *SlurmrestVerifier) Verify(ctx context.Context, cluster model.Cluster, username, password string) (Identity, error) {
+	if cluster.Slurmrestd == "" {
+		return Identity{}, fmt.Errorf("empty slurmrestd address for cluster(%s)", cluster.Name)
+	}
+	client := v.backends.For(cluster)
+	if err := client.AuthLdapUser(ctx, cluster.Slurmrestd, username, password); err != nil {
+		if errors.Is(err, slurmrest.ErrInvalidCredentials) {
+			return Identity{}, ErrInvalidCredentials
+		}
+		return Identity{}, err
+	}
+	groups, err := client.GetAdditionalGroupsOfUser(ctx, cluster.Slurmrestd, username)
+	if err != nil {
+		return Identity{}, fmt.Errorf("unable to get groups of user(%s): %w", username, err)
+	}
+	return Identity{Username: username, Cluster: cluster.Name, Groups: groups}, nil
+}
+
+func (v *SlurmrestVerifier) Lookup(ctx context.Context, cluster model.Cluster, username string) (Identity, error) {
+	if cluster.Slurmrestd == "" {
+		return Identity{}, fmt.Errorf("empty slurmrestd address for cluster(%s)", cluster.Name)
+	}
+	groups, err := v.backends.For(cluster).GetAdditionalGroupsOfUser(ctx, cluster.Slurmrestd, username)
+	if err != nil {
+		if apperror.CodeOf(err) == apperror.CODE_NOT_FOUND {
+			return Identity{}, ErrUserNotFound
+		}
+		return Identity{}, fmt.Errorf("unable to get groups of user(%s): %w", username, err)
+	}
+	return Identity{Username: username, Cluster: cluster.Name, Groups: groups}, nil
+}
@@ -0,0 +1,181 @@
+// Package cache 为变化较慢的上游数据(节点, 分区, QoS, 账户等)提供进程内 TTL 缓存.
+// 缓存按 (集群, 资源类型) 分组, 各组的 TTL 由 TTLFunc 决定, 可按集群覆盖; 同一键的并发未命中
+// 只访问一次上游(singleflight); 写操作完成后调用 Invalidate 使对应分组失效.
+package cache
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// sweepInterval 清理过期缓存项的最小间隔.
+const sweepInterval = time.Minute
+
+// 查询结果, 作为 ObserveFunc 的 result.
+const (
+	RESULT_HIT    = "hit"    // 命中
+	RESULT_MISS   = "miss"   // 未命中, 由本次查询访问上游服务
+	RESULT_SHARED = "shared" // 未命中, 与进行中的相同查询共享结果
+)
+
+// ObserveFunc 记录一次缓存查询, 用于统计命中率(如 metrics.ObserveCache).
+type ObserveFunc func(resource, cluster, result string)
+
+// TTLFunc 返回集群(cluster)中资源(resource)的缓存时间, 小于等于 0 表示不缓存.
+type TTLFunc func(cluster, resource string) time.Duration
+
+type group struct {
+	cluster  string
+	resource string
+}
+
+type entry struct {
+	value   any
+	expires time.Time
+}
+
+// Cache 进程内 TTL 缓存, 可被多个 goroutine 并发使用. 缓存的值由多个调用方共享, 调用方不得修改.
+type Cache struct {
+	ttl     TTLFunc
+	observe ObserveFunc
+	flight  singleflight.Group
+
+	mu        sync.Mutex
+	entries   map[group]map[string]entry
+	gens      map[group]uint64 // 每次 Invalidate 递增, 用于丢弃失效前发起的加载结果
+	nextSweep time.Time
+}
+
+// New 创建 Cache. observe 可为空.
+func New(ttl TTLFunc, observe ObserveFunc) *Cache {
+	if observe == nil {
+		observe = func(string, string, string) {}
+	}
+	return &Cache{ttl: ttl, observe: observe, entries: make(map[group]map[string]entry), gens: make(map[group]uint64)}
+}
+
+// Load 返回集群(cluster)中资源(resource)键为 key 的缓存值, 未命中时调用 load 加载并缓存.
+// 同一键的并发未命中共享一次 load 调用; load 的 context 不随调用方取消(保留其中的请求 ID 等值),
+// 其超时由 load 自身控制, 调用方取消时立即返回 ctx.Err(). load 返回错误时不缓存.
+func Load[T any](ctx context.Context, c *Cache, cluster, resource, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if c == nil || c.ttl(cluster, resource) <= 0 {
+		return load(ctx)
+	}
+
+	g := group{cluster: cluster, resource: resource}
+	if v, ok := c.get(g, key); ok {
+		c.observe(resource, cluster, RESULT_HIT)
+		return v.(T), nil
+	}
+
+	c.mu.Lock()
+	gen := c.gens[g]
+	c.gens[g] = gen // 登记分组, 以便 Invalidate 使进行中的加载失效
+	c.mu.Unlock()
+
+	// singleflight 的键包含分组的版本号, 失效后的请求不会复用失效前发起的加载
+	flightKey := cluster + "\x00" + resource + "\x00" + key + "\x00" + strconv.FormatUint(gen, 10)
+	leader := false // 仅发起加载的调用方的闭包会被执行
+	ch := c.flight.DoChan(flightKey, func() (any, error) {
+		leader = true
+		v, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		c.set(g, gen, key, v)
+		return v, nil
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case r := <-ch:
+		result := RESULT_SHARED
+		if leader {
+			result = RESULT_MISS
+		}
+		c.observe(resource, cluster, result)
+		if r.Err != nil {
+			return zero, r.Err
+		}
+		return r.Val.(T), nil
+	}
+}
+
+// Invalidate 使集群(cluster)中资源 resources 的缓存失效, resources 为空时使该集群的全部缓存失效.
+// 失效前发起且尚未完成的加载结果不会被缓存.
+func (c *Cache) Invalidate(cluster string, resources ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for g := range c.gens {
+		if g.cluster == cluster && (len(resources) == 0 || slices.Contains(resources, g.resource)) {
+			c.gens[g]++
+			delete(c.entries, g)
+		}
+	}
+}
+
+func (c *Cache) get(g group, key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[g][key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expires) {
+		delete(c.entries[g], key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// set 缓存加载结果. gen 与分组当前版本不一致说明加载期间发生了失效, 丢弃该结果.
+func (c *Cache) set(g group, gen uint64, key string, v any) {
+	ttl := c.ttl(g.cluster, g.resource)
+	if ttl <= 0 {
+		return
+	}
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gens[g] != gen {
+		return
+	}
+	if now.After(c.nextSweep) {
+		c.sweep(now)
+	}
+	m, ok := c.entries[g]
+	if !ok {
+		m = make(map[string]entry)
+		c.entries[g] = m
+	}
+	m[key] = entry{value: v, expires: now.Add(ttl)}
+}
+
+// sweep 删除过期缓存项, 调用方需持有 c.mu.
+func (c *Cache) sweep(now time.Time) {
+	for g, m := range c.entries {
+		for k, e := range m {
+			if now.After(e.expires) {
+				delete(m, k)
+			}
+		}
+		if len(m) == 0 {
+			delete(c.entries, g)
+		}
+	}
+	c.nextSweep = now.Add(sweepInterval)
+}
@@ -0,0 +1,256 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// observed 记录 ObserveFunc 的调用结果.
+type observed struct {
+	mu      sync.Mutex
+	results []string
+}
+
+func (o *observed) observe(resource, cluster, result string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.results = append(o.results, result)
+}
+
+func constTTL(ttl time.Duration) TTLFunc {
+	return func(string, string) time.Duration { return ttl }
+}
+
+// counter 返回每次调用递增的加载函数及调用次数.
+func counter() (func(ctx context.Context) (int, error), *atomic.Int64) {
+	var n atomic.Int64
+	return func(ctx context.Context) (int, error) { return int(n.Add(1)), nil }, &n
+}
+
+func TestLoadHit(t *testing.T) {
+	o := &observed{}
+	c := New(constTTL(time.Minute), o.observe)
+	load, calls := counter()
+
+	for i := 0; i < 3; i++ {
+		v, err := Load(context.Background(), c, "c1", "nodes", "k", load)
+		if err != nil || v != 1 {
+			t.Fatalf("Load = %d, %v, want 1", v, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("load calls = %d, want 1", got)
+	}
+	if want := []string{RESULT_MISS, RESULT_HIT, RESULT_HIT}; len(o.results) != 3 || o.results[0] != want[0] || o.results[2] != want[2] {
+		t.Errorf("observed = %v, want %v", o.results, want)
+	}
+
+	// 不同键, 资源, 集群互不影响
+	for _, k := range [][3]string{{"c1", "nodes", "k2"}, {"c1", "qos", "k"}, {"c2", "nodes", "k"}} {
+		if v, _ := Load(context.Background(), c, k[0], k[1], k[2], load); v == 1 {
+			t.Errorf("Load(%v) returned value cached for another key", k)
+		}
+	}
+}
+
+func TestLoadDisabled(t *testing.T) {
+	load, calls := counter()
+	for _, c := range []*Cache{nil, New(constTTL(0), nil)} {
+		calls.Store(0)
+		Load(context.Background(), c, "c1", "nodes", "k", load)
+		Load(context.Background(), c, "c1", "nodes", "k", load)
+		if got := calls.Load(); got != 2 {
+			t.Errorf("load calls = %d, want 2 when caching is disabled", got)
+		}
+	}
+}
+
+func TestLoadExpires(t *testing.T) {
+	c := New(constTTL(20*time.Millisecond), nil)
+	load, calls := counter()
+
+	Load(context.Background(), c, "c1", "nodes", "k", load)
+	time.Sleep(30 * time.Millisecond)
+	if v, _ := Load(context.Background(), c, "c1", "nodes", "k", load); v != 2 {
+		t.Errorf("Load after TTL = %d, want 2", v)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("load calls = %d, want 2", got)
+	}
+}
+
+func TestLoadErrorNotCached(t *testing.T) {
+	c := New(constTTL(time.Minute), nil)
+	errUpstream := errors.New("upstream")
+	var calls atomic.Int64
+	load := func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 1 {
+			return 0, errUpstream
+		}
+		return 42, nil
+	}
+
+	if _, err := Load(context.Background(), c, "c1", "nodes", "k", load); !errors.Is(err, errUpstream) {
+		t.Fatalf("Load error = %v, want %v", err, errUpstream)
+	}
+	if v, err := Load(context.Background(), c, "c1", "nodes", "k", load); err != nil || v != 42 {
+		t.Errorf("Load after error = %d, %v, want 42", v, err)
+	}
+}
+
+func TestLoadSingleflight(t *testing.T) {
+	o := &observed{}
+	c := New(constTTL(time.Minute), o.observe)
+	release := make(chan struct{})
+	var calls atomic.Int64
+	load := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 7, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := Load(context.Background(), c, "c1", "nodes", "k", load); err != nil || v != 7 {
+				t.Errorf("Load = %d, %v, want 7", v, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // 等待全部调用方进入 singleflight
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("load calls = %d, want 1", got)
+	}
+	misses := 0
+	for _, r := range o.results {
+		if r == RESULT_MISS {
+			misses++
+		}
+	}
+	if misses != 1 || len(o.results) != n {
+		t.Errorf("observed = %v, want 1 miss of %d", o.results, n)
+	}
+}
+
+func TestLoadCallerCancel(t *testing.T) {
+	c := New(constTTL(time.Minute), nil)
+	release := make(chan struct{})
+	done := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		defer close(done)
+		<-release
+		return 1, ctx.Err() // 调用方取消不影响加载
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := Load(ctx, c, "c1", "nodes", "k", load); !errors.Is(err, context.Canceled) {
+		t.Errorf("Load error = %v, want context.Canceled", err)
+	}
+	close(release)
+	<-done
+
+	// 加载在调用方取消后完成, 结果仍被缓存
+	v, err := Load(context.Background(), c, "c1", "nodes", "k", func(ctx context.Context) (int, error) { return 2, nil })
+	if err != nil || v != 1 {
+		t.Errorf("Load = %d, %v, want cached 1", v, err)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	c := New(constTTL(time.Minute), nil)
+	load, calls := counter()
+	keys := [][2]string{{"c1", "nodes"}, {"c1", "qos"}, {"c2", "nodes"}}
+	for _, k := range keys {
+		Load(context.Background(), c, k[0], k[1], "k", load)
+	}
+
+	c.Invalidate("c1", "nodes")
+	for i, want := range []int64{4, 2, 3} {
+		k := keys[i]
+		v, _ := Load(context.Background(), c, k[0], k[1], "k", load)
+		if int64(v) != want {
+			t.Errorf("Load(%v) after Invalidate(c1, nodes) = %d, want %d", k, v, want)
+		}
+	}
+
+	c.Invalidate("c1")
+	if v, _ := Load(context.Background(), c, "c1", "qos", "k", load); v != 5 {
+		t.Errorf("Load(c1, qos) after Invalidate(c1) = %d, want 5", v)
+	}
+	if v, _ := Load(context.Background(), c, "c2", "nodes", "k", load); v != 3 {
+		t.Errorf("Load(c2, nodes) after Invalidate(c1) = %d, want 3", v)
+	}
+	if got := calls.Load(); got != 5 {
+		t.Errorf("load calls = %d, want 5", got)
+	}
+
+	var nilCache *Cache
+	nilCache.Invalidate("c1") // 不应 panic
+}
+
+// TestInvalidateDuringLoad 失效前发起的加载在失效后完成时, 其结果(旧数据)不得被缓存,
+// 失效后的查询也不得复用该加载.
+func TestInvalidateDuringLoad(t *testing.T) {
+	c := New(constTTL(time.Minute), nil)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	stale := make(chan int, 1)
+	go func() {
+		v, _ := Load(context.Background(), c, "c1", "nodes", "k", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil // 失效前读取的旧数据
+		})
+		stale <- v
+	}()
+	<-started
+
+	c.Invalidate("c1", "nodes")
+
+	// 失效后发起的查询不与进行中的旧加载共享结果
+	fresh, err := Load(context.Background(), c, "c1", "nodes", "k", func(ctx context.Context) (int, error) { return 2, nil })
+	if err != nil || fresh != 2 {
+		t.Errorf("Load after Invalidate = %d, %v, want 2", fresh, err)
+	}
+
+	close(release)
+	if v := <-stale; v != 1 {
+		t.Errorf("in-flight Load = %d, want 1", v)
+	}
+	if v, _ := Load(context.Background(), c, "c1", "nodes", "k", func(ctx context.Context) (int, error) { return 3, nil }); v != 2 {
+		t.Errorf("Load after in-flight load finished = %d, want 2 (stale value must not be cached)", v)
+	}
+
+	// 失效时没有其他查询, 旧加载完成后同样不缓存
+	started, release = make(chan struct{}), make(chan struct{})
+	c.Invalidate("c1")
+	go func() {
+		v, _ := Load(context.Background(), c, "c1", "qos", "k", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		stale <- v
+	}()
+	<-started
+	c.Invalidate("c1")
+	close(release)
+	<-stale
+	if v, _ := Load(context.Background(), c, "c1", "qos", "k", func(ctx context.Context) (int, error) { return 2, nil }); v != 2 {
+		t.Errorf("Load after invalidated in-flight load = %d, want 2", v)
+	}
+}
@@ -1,6 +1,7 @@
 package alertmanager
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,31 +9,40 @@ import (
 	"net/http"
 	"net/url"
 	"time"
+
+	"csjk-bk/internal/pkg/apperror"
 )
 
 type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// ErrNoServer 未指定 Alertmanager 地址且未配置默认地址.
+var ErrNoServer = apperror.New(apperror.CODE_CLUSTER_MISCONFIGURED, "no alertmanager server configured")
+
 type Client struct {
 	doer   Doer
-	server *url.URL
+	server string // 默认 Alertmanager 地址
 	logger *slog.Logger
 }
 
-// SetCilent 仅用于测试阶段使用
-func (c *Client) SetClient(Doer Doer, server *url.URL, logger *slog.Logger) {
+// SetCilent 设置 HTTP 客户端及日志, Alertmanager 地址由各集群配置决定, 在调用时传入.
+func (c *Client) SetClient(Doer Doer, logger *slog.Logger) {
 	c.doer = Doer
 	if Doer == nil {
 		c.doer = http.DefaultClient
 	}
-	c.server = server
 	c.logger = logger
 	if logger == nil {
 		c.logger = slog.Default()
 	}
 }
 
+// SetServer 设置默认 Alertmanager 地址, 调用时未指定地址则使用该地址.
+func (c *Client) SetServer(server string) {
+	c.server = server
+}
+
 type Alerts []Alert
 type Alert struct {
 	Fingerprint  string            `json:"fingerprint"`
@@ -43,23 +53,123 @@ type Alert struct {
 	Annotations  map[string]string `json:"annotations"`
 }
 
-func (c *Client) GetActiveAlerts(ctx context.Context) (Alerts, error) {
+// GetActiveAlerts 从 Alertmanager(server, 如 http://host:9093) 获取未被抑制/静默的活跃报警.
+// server 为空时使用默认地址.
+func (c *Client) GetActiveAlerts(ctx context.Context, server string) (Alerts, error) {
 	alerts := make(Alerts, 0)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.server.String(), nil)
+	if server == "" {
+		server = c.server
+	}
+	if server == "" {
+		return alerts, ErrNoServer
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		c.logger.Error("invalid alertmanager url", "err", err, "url", server)
+		return alerts, fmt.Errorf("invalid alertmanager url: %s", server)
+	}
+	u = u.JoinPath("/api/v2/alerts")
+	u.RawQuery = url.Values{
+		"active":      []string{"true"},
+		"inhibited":   []string{"false"},
+		"silenced":    []string{"false"},
+		"unprocessed": []string{"false"},
+	}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		c.logger.Error("unable to create request to get active alerts from alertmanager", "err", err, "url", c.server.String())
-		return alerts, fmt.Errorf("unable to create request to get active alerts from alertmanager")
+		c.logger.Error("unable to create request to get active alerts from alertmanager", "err", err, "url", u.String())
+		return alerts, fmt.Errorf("unable to create request to get active alerts from alertmanager: %w", err)
 	}
 	resp, err := c.doer.Do(req)
 	if err != nil {
-		c.logger.Error("unable to send request to alertmanager", "err", err, "url", c.server.String())
-		return alerts, fmt.Errorf("unbale to send request to alertmanager")
+		c.logger.Error("unable to send request to alertmanager", "err", err, "url", u.String())
+		return alerts, fmt.Errorf("unable to send request to alertmanager: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		c.logger.Error("unexpected status code", "code", resp.StatusCode, "url", u.String())
+		return alerts, apperror.Upstream("alertmanager", resp.StatusCode, "")
+	}
 	if err := json.NewDecoder(resp.Body).Decode(&alerts); err != nil {
 		c.logger.Error("unable to decode response body of alertmanager", "err", err)
-		return alerts, fmt.Errorf("unable to decode response body of alertmanager")
+		return alerts, fmt.Errorf("unable to decode response body of alertmanager: %w", err)
 	}
 
 	return alerts, nil
 }
+
+// PostableAlert 推送到 Alertmanager 的报警. StartsAt 为零值时为接收时间, EndsAt 为零值时由 Alertmanager 的 resolve_timeout 决定何时恢复.
+type PostableAlert struct {
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// PostAlerts 向 Alertmanager(server) 推送报警, 标签相同的报警由 Alertmanager 合并. server 为空时使用默认地址.
+func (c *Client) PostAlerts(ctx context.Context, server string, alerts []PostableAlert) error {
+	if server == "" {
+		server = c.server
+	}
+	if server == "" {
+		return ErrNoServer
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		c.logger.Error("invalid alertmanager url", "err", err, "url", server)
+		return fmt.Errorf("invalid alertmanager url: %s", server)
+	}
+	u = u.JoinPath("/api/v2/alerts")
+
+	payload, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("unable to marshal alerts: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		c.logger.Error("unable to create request to post alerts to alertmanager", "err", err, "url", u.String())
+		return fmt.Errorf("unable to create request to post alerts to alertmanager: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		c.logger.Error("unable to send request to alertmanager", "err", err, "url", u.String())
+		return fmt.Errorf("unable to send request to alertmanager: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		c.logger.Error("unexpected status code", "code", resp.StatusCode, "url", u.String())
+		return apperror.Upstream("alertmanager", resp.StatusCode, "")
+	}
+	return nil
+}
+
+// Ping 通过 Alertmanager 的 /-/healthy 接口探测服务是否可用. server 为空时使用默认地址.
+func (c *Client) Ping(ctx context.Context, server string) error {
+	if server == "" {
+		server = c.server
+	}
+	if server == "" {
+		return ErrNoServer
+	}
+	u, err := url.Parse(server)
+	if err != nil {
+		return fmt.Errorf("invalid alertmanager url: %s", server)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.JoinPath("/-/healthy").String(), nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request to alertmanager: %w", err)
+	}
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send request to alertmanager: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return apperror.Upstream("alertmanager", resp.StatusCode, "")
+	}
+	return nil
+}
@@ -4,16 +4,28 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os/exec"
 	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/metrics"
 )
 
 type ExecCommandFunc func(ctx context.Context, name string, args ...string) *exec.Cmd
 
+// Credential BMC(ipmitool -U/-P) 账号.
+type Credential struct {
+	User     string
+	Password string
+}
+
 type Client struct {
 	execCommand ExecCommandFunc
+	credential  Credential // 默认 BMC 账号, 调用方未指定账号时使用
 	logger      *slog.Logger
 }
 
@@ -23,71 +35,128 @@ func (c *Client) Set(exec ExecCommandFunc, logger *slog.Logger) *Client {
 	return c
 }
 
-func (c *Client) SetUpperThreshsOfOutbandSensor(ctx context.Context, rmu, bmu, id, unr, ucr, unc string) error {
-	// TODO: 账号和密码会经常改吗？要不写到配置文件中.
-	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", "admin", "-P", "admin", "sensor", "thresh", id, "upper", unc, ucr, unr)
-	output, err := cmd.CombinedOutput()
-	c.logger.Debug(cmd.String())
+// SetCredential 设置默认 BMC 账号.
+func (c *Client) SetCredential(cred Credential) *Client {
+	c.credential = cred
+	return c
+}
+
+// commandError 将命令执行失败转换为带错误码的错误, ctx 超时视为上游超时.
+func commandError(ctx context.Context, err error, cmd string) error {
+	code := apperror.CODE_UPSTREAM_ERROR
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		code = apperror.CODE_UPSTREAM_TIMEOUT
+	}
+	return apperror.Wrap(code, err, "command failed: "+cmd)
+}
+
+// resolve 返回实际使用的 BMC 账号, cred 未指定用户名时使用默认账号.
+func (c *Client) resolve(cred Credential) (Credential, error) {
+	if cred.User == "" {
+		cred = c.credential
+	}
+	if cred.User == "" {
+		return cred, apperror.New(apperror.CODE_CLUSTER_MISCONFIGURED, "no bmc credential configured")
+	}
+	return cred, nil
+}
+
+func (c *Client) SetUpperThreshsOfOutbandSensor(ctx context.Context, cred Credential, rmu, bmu, id, unr, ucr, unc string) error {
+	cred, err := c.resolve(cred)
 	if err != nil {
-		c.logger.Error("unable to execute command", "cmd", cmd.String(), "output", output, "err", err)
-		return fmt.Errorf(cmd.String())
+		return err
+	}
+	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", cred.User, "-P", cred.Password, "sensor", "thresh", id, "upper", unc, ucr, unr)
+	output, err := combinedOutput("sensor_thresh_upper", cmd)
+	c.logger.Debug("execute command", "rmu", rmu, "bmu", bmu)
+	if err != nil {
+		c.logger.Error("unable to execute command", "cmd", cmdString(cmd, cred), "output", output, "err", err)
+		return commandError(ctx, err, cmdString(cmd, cred))
 	}
 	return nil
 }
 
-func (c *Client) SetLowerThreshsOfOutbandSensor(ctx context.Context, rmu, bmu, id, lnr, lcr, lnc string) error {
-	// TODO: 账号和密码会经常改吗？要不写到配置文件中.
-	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", "admin", "-P", "admin", "sensor", "thresh", id, "lower", lnr, lcr, lnc)
-	output, err := cmd.CombinedOutput()
-	c.logger.Debug(cmd.String())
+func (c *Client) SetLowerThreshsOfOutbandSensor(ctx context.Context, cred Credential, rmu, bmu, id, lnr, lcr, lnc string) error {
+	cred, err := c.resolve(cred)
 	if err != nil {
-		c.logger.Error("unable to execute command", "cmd", cmd.String(), "output", output, "err", err)
-		return fmt.Errorf(cmd.String())
+		return err
+	}
+	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", cred.User, "-P", cred.Password, "sensor", "thresh", id, "lower", lnr, lcr, lnc)
+	output, err := combinedOutput("sensor_thresh_lower", cmd)
+	c.logger.Debug("execute command", "rmu", rmu, "bmu", bmu)
+	if err != nil {
+		c.logger.Error("unable to execute command", "cmd", cmdString(cmd, cred), "output", output, "err", err)
+		return commandError(ctx, err, cmdString(cmd, cred))
 	}
 	return nil
 }
 
-func (c *Client) SetThreshsOfOutbandSensor(ctx context.Context, rmu, bmu, id, which, value string) error {
-	// TODO: 账号和密码会经常改吗？要不写到配置文件中.
-	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", "admin", "-P", "admin", "sensor", "thresh", id, which, value)
-	output, err := cmd.CombinedOutput()
-	c.logger.Debug(cmd.String())
+func (c *Client) SetThreshsOfOutbandSensor(ctx context.Context, cred Credential, rmu, bmu, id, which, value string) error {
+	cred, err := c.resolve(cred)
 	if err != nil {
-		c.logger.Error("unable to execute command", "cmd", cmd.String(), "output", output, "err", err)
-		return fmt.Errorf(cmd.String())
+		return err
+	}
+	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", cred.User, "-P", cred.Password, "sensor", "thresh", id, which, value)
+	output, err := combinedOutput("sensor_thresh", cmd)
+	c.logger.Debug("execute command", "rmu", rmu, "bmu", bmu)
+	if err != nil {
+		c.logger.Error("unable to execute command", "cmd", cmdString(cmd, cred), "output", output, "err", err)
+		return commandError(ctx, err, cmdString(cmd, cred))
 	}
 	return nil
 }
 
 func (c *Client) SetInhibitOfOutbandSensor(ctx context.Context, rmu, board, sensor, rule string) error {
 	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "set_sensor_filter.sh", board, sensor, rule)
-	output, err := cmd.CombinedOutput()
+	output, err := combinedOutput("sensor_filter", cmd)
 	if err != nil {
 		c.logger.Error("unable to execute command", "cmd", cmd.String(), "output", output, "err", err)
-		return fmt.Errorf(cmd.String())
+		return commandError(ctx, err, cmd.String())
 	}
 
 	if strings.Contains(string(output), "set success") {
 		return nil
 	}
 
-	return fmt.Errorf(string(output))
+	return apperror.New(apperror.CODE_UPSTREAM_ERROR, strings.TrimSpace(string(output)))
 }
 
-func (c *Client) GetThresholdOfOutbandSensor(ctx context.Context, rmu, bmu string) (Thresholds, error) {
-	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", "admin", "-P", "admin", "sensor")
-	output, err := cmd.CombinedOutput()
+func (c *Client) GetThresholdOfOutbandSensor(ctx context.Context, cred Credential, rmu, bmu string) (Thresholds, error) {
+	cred, err := c.resolve(cred)
 	if err != nil {
-		c.logger.Error("unable to execute command", "cmd", cmd.String(), "output", output, "err", err)
-		return nil, fmt.Errorf(cmd.String())
+		return nil, err
+	}
+	cmd := c.execCommand(ctx, "smu_transfer_cmd", rmu, "ipmitool", "-I", "lanp", "-H", bmu, "-U", cred.User, "-P", cred.Password, "sensor")
+	output, err := combinedOutput("sensor_list", cmd)
+	if err != nil {
+		c.logger.Error("unable to execute command", "cmd", cmdString(cmd, cred), "output", output, "err", err)
+		return nil, commandError(ctx, err, cmdString(cmd, cred))
 	}
 
 	thd, err := parseThresholdOfOutbandSensor(output)
 	if err != nil {
-		c.logger.Error(fmt.Sprintf("unable to parse output for %s", cmd.String()), "output", output)
+		c.logger.Error(fmt.Sprintf("unable to parse output for %s", cmdString(cmd, cred)), "output", output)
+		return nil, err
 	}
 
-	return thd, fmt.Errorf(string(output))
+	return thd, nil
+}
+
+// combinedOutput 执行命令并记录耗时及退出码.
+func combinedOutput(operation string, cmd *exec.Cmd) ([]byte, error) {
+	start := time.Now()
+	output, err := cmd.CombinedOutput()
+	metrics.ObserveCommand(operation, time.Since(start), err)
+	return output, err
+}
+
+// cmdString 返回用于日志及错误信息的命令行, 隐藏 BMC 密码.
+func cmdString(cmd *exec.Cmd, cred Credential) string {
+	s := cmd.String()
+	if cred.Password != "" {
+		s = strings.ReplaceAll(s, " -P "+cred.Password, " -P ******")
+	}
+	return s
 }
 
 type Thresholds []Threshold
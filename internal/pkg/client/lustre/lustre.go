@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+
+	"csjk-bk/internal/pkg/apperror"
 )
 
 type Doer interface {
@@ -73,11 +75,11 @@ func (c *Client) Control(ctx context.Context, addr, cmd string) error {
 	}
 
 	if resp.StatusCode/100 != 2 {
-		return fmt.Errorf("unexpected http status: %d, detail: %s", resp.StatusCode, data.Message)
+		return apperror.Upstream("lustre", resp.StatusCode, data.Message)
 	}
 
 	if data.Code != 200 {
-		return fmt.Errorf("lustre control failed: code=%d, msg=%s", data.Code, data.Message)
+		return apperror.New(apperror.CODE_UPSTREAM_ERROR, fmt.Sprintf("lustre control failed: code=%d, msg=%s", data.Code, data.Message))
 	}
 
 	return nil
@@ -119,11 +121,11 @@ func (c *Client) Query(ctx context.Context, addr, cmd string) (map[string]string
 	}
 
 	if resp.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("unexpected http status: %d, detail: %s", resp.StatusCode, data.Message)
+		return nil, apperror.Upstream("lustre", resp.StatusCode, data.Message)
 	}
 
 	if data.Code != 200 {
-		return nil, fmt.Errorf("lustre query failed: code=%d, msg=%s", data.Code, data.Message)
+		return nil, apperror.New(apperror.CODE_UPSTREAM_ERROR, fmt.Sprintf("lustre query failed: code=%d, msg=%s", data.Code, data.Message))
 	}
 
 	return data.Result, nil
@@ -181,10 +183,10 @@ func (c *Client) GetMounts(ctx context.Context, addr string) ([]map[string]strin
 	}
 
 	if resp.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("unexpected http status: %d, detail: %s", resp.StatusCode, data.Message)
+		return nil, apperror.Upstream("lustre", resp.StatusCode, data.Message)
 	}
 	if data.Code != 200 {
-		return nil, fmt.Errorf("lustre get mounts failed: code=%d, msg=%s", data.Code, data.Message)
+		return nil, apperror.New(apperror.CODE_UPSTREAM_ERROR, fmt.Sprintf("lustre get mounts failed: code=%d, msg=%s", data.Code, data.Message))
 	}
 
 	return data.Result, nil
@@ -233,10 +235,10 @@ func (c *Client) GetDefaultQuota(ctx context.Context, addr, mount string) (map[s
 	}
 
 	if resp.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("unexpected http status: %d, detail: %s", resp.StatusCode, data.Message)
+		return nil, apperror.Upstream("lustre", resp.StatusCode, data.Message)
 	}
 	if data.Code != 200 {
-		return nil, fmt.Errorf("lustre get default quota failed: code=%d, msg=%s", data.Code, data.Message)
+		return nil, apperror.New(apperror.CODE_UPSTREAM_ERROR, fmt.Sprintf("lustre get default quota failed: code=%d, msg=%s", data.Code, data.Message))
 	}
 
 	return data.Result, nil
@@ -286,11 +288,29 @@ func (c *Client) GetUserQuota(ctx context.Context, addr, user, mount string) (ma
 	}
 
 	if resp.StatusCode/100 != 2 {
-		return nil, fmt.Errorf("unexpected http status: %d, detail: %s", resp.StatusCode, data.Message)
+		return nil, apperror.Upstream("lustre", resp.StatusCode, data.Message)
 	}
 	if data.Code != 200 {
-		return nil, fmt.Errorf("lustre get user quota failed: code=%d, msg=%s", data.Code, data.Message)
+		return nil, apperror.New(apperror.CODE_UPSTREAM_ERROR, fmt.Sprintf("lustre get user quota failed: code=%d, msg=%s", data.Code, data.Message))
 	}
 
 	return data.Result, nil
 }
+
+// Ping 探测 KD lustre 服务是否可达. KD 服务未提供健康检查接口, 能收到任意 HTTP 响应即视为可达.
+func (c *Client) Ping(ctx context.Context, addr string) error {
+	if c == nil || c.doer == nil {
+		return fmt.Errorf("nil client or http doer")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/", addr), nil)
+	if err != nil {
+		return fmt.Errorf("create request failed: %w", err)
+	}
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request failed: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
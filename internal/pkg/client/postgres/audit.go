@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/client/postgres/model"
+)
+
+// AddAuditLog 写入一条审计日志.
+func (c *Client) AddAuditLog(ctx context.Context, l model.AuditLog) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	const q = `
+        INSERT INTO audit_log (created_at, actor, cluster, method, route, path, target, body, status, outcome, duration_ms, client_ip)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    `
+	if _, err := conn.Exec(ctx, q, l.CreatedAt, l.Actor, l.Cluster, l.Method, l.Route, l.Path, l.Target, l.Body,
+		l.Status, l.Outcome, l.DurationMS, l.ClientIP); err != nil {
+		return fmt.Errorf("插入审计日志失败: %w", err)
+	}
+	return nil
+}
+
+// GetAuditLogs 按时间降序获取审计日志, 支持分页.
+// 过滤条件: from/to 为零值时不限制; actor 精确匹配; route 为路由模板前缀, 如 /api/v1/:cluster/ldap.
+func (c *Client) GetAuditLogs(ctx context.Context, from, to time.Time, actor, route string, paging bool, page, pageSize int) (model.AuditLogs, int, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	// 1) 构建 where 子句（供 count 和 list 复用）
+	conds := make([]string, 0, 4)
+	args := make([]any, 0, 6)
+	if !from.IsZero() {
+		args = append(args, from)
+		conds = append(conds, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		conds = append(conds, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+	if actor != "" {
+		args = append(args, actor)
+		conds = append(conds, fmt.Sprintf("actor = $%d", len(args)))
+	}
+	if route != "" {
+		// 转义 LIKE 通配符, 仅做前缀匹配
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(route)
+		args = append(args, escaped+"%")
+		conds = append(conds, fmt.Sprintf("route LIKE $%d", len(args)))
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	// 2) 统计总数（不分页）
+	var total int64
+	if err := conn.QueryRow(ctx, "SELECT COUNT(*) FROM audit_log"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("统计总数失败: %w", err)
+	}
+
+	// 3) 查询列表（可分页）
+	q := "SELECT id, created_at, actor, cluster, method, route, path, target, body, status, outcome, duration_ms, client_ip FROM audit_log" +
+		where + " ORDER BY created_at DESC, id DESC"
+	if paging && pageSize > 0 {
+		off := (page - 1) * pageSize
+		if off < 0 {
+			off = 0
+		}
+		args = append(args, pageSize, off)
+		q += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	rows, err := conn.Query(ctx, q, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询数据库失败: %w", err)
+	}
+	defer rows.Close()
+
+	list := make(model.AuditLogs, 0)
+	for rows.Next() {
+		var l model.AuditLog
+		if err := rows.Scan(&l.ID, &l.CreatedAt, &l.Actor, &l.Cluster, &l.Method, &l.Route, &l.Path, &l.Target, &l.Body,
+			&l.Status, &l.Outcome, &l.DurationMS, &l.ClientIP); err != nil {
+			return nil, 0, fmt.Errorf("读取数据失败: %w", err)
+		}
+		list = append(list, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("读取数据失败: %w", err)
+	}
+
+	return list, int(total), nil
+}
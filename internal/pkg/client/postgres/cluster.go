@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/postgres/model"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrClusterNotFound 集群未注册.
+var ErrClusterNotFound = apperror.New(apperror.CODE_CLUSTER_NOT_FOUND, "cluster not found")
+
+// ErrClusterExists 集群名称已存在.
+var ErrClusterExists = apperror.New(apperror.CODE_CONFLICT, "cluster already exists")
+
+const clusterColumns = "id, name, description, slurmrestd, slurmrestd_flavor, slurmrestd_version, slurmrestd_user, slurmrestd_token, lustre_server, alertmanager_url, rmu_addr, bmc_user, bmc_password, created_at, updated_at"
+
+func scanCluster(row pgx.Row) (model.Cluster, error) {
+	var cl model.Cluster
+	err := row.Scan(&cl.ID, &cl.Name, &cl.Description, &cl.Slurmrestd, &cl.SlurmrestdFlavor, &cl.SlurmrestdVersion,
+		&cl.SlurmrestdUser, &cl.SlurmrestdToken, &cl.LustreServer, &cl.AlertmanagerURL, &cl.RMUAddr, &cl.BMCUser, &cl.BMCPassword, &cl.CreatedAt, &cl.UpdatedAt)
+	return cl, err
+}
+
+// GetClusters 获取已注册集群列表, 按名称排序, 支持分页.
+func (c *Client) GetClusters(ctx context.Context, paging bool, page, pageSize int) (model.Clusters, int, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	var total int64
+	if err := conn.QueryRow(ctx, "SELECT COUNT(*) FROM cluster").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("统计总数失败: %w", err)
+	}
+
+	q := "SELECT " + clusterColumns + " FROM cluster ORDER BY name"
+	args := make([]any, 0, 2)
+	if paging && pageSize > 0 {
+		off := (page - 1) * pageSize
+		if off < 0 {
+			off = 0
+		}
+		q += " LIMIT $1 OFFSET $2"
+		args = append(args, pageSize, off)
+	}
+
+	rows, err := conn.Query(ctx, q, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询数据库失败: %w", err)
+	}
+	defer rows.Close()
+
+	list := make(model.Clusters, 0)
+	for rows.Next() {
+		cl, err := scanCluster(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("读取数据失败: %w", err)
+		}
+		list = append(list, cl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("读取数据失败: %w", err)
+	}
+
+	return list, int(total), nil
+}
+
+// GetCluster 根据集群名称获取集群信息. 集群不存在时返回 ErrClusterNotFound.
+func (c *Client) GetCluster(ctx context.Context, name string) (model.Cluster, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return model.Cluster{}, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	cl, err := scanCluster(conn.QueryRow(ctx, "SELECT "+clusterColumns+" FROM cluster WHERE name = $1", name))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Cluster{}, fmt.Errorf("%w: name=%s", ErrClusterNotFound, name)
+		}
+		return model.Cluster{}, fmt.Errorf("查询数据库失败: %w", err)
+	}
+	return cl, nil
+}
+
+// AddCluster 注册新集群. 名称重复时返回 ErrClusterExists.
+func (c *Client) AddCluster(ctx context.Context, cl model.Cluster) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	if strings.TrimSpace(cl.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	const q = `
+        INSERT INTO cluster (name, description, slurmrestd, slurmrestd_flavor, slurmrestd_version, slurmrestd_user, slurmrestd_token,
+                             lustre_server, alertmanager_url, rmu_addr, bmc_user, bmc_password)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    `
+	if _, err := conn.Exec(ctx, q, cl.Name, cl.Description, cl.Slurmrestd, cl.SlurmrestdFlavor, cl.SlurmrestdVersion,
+		cl.SlurmrestdUser, cl.SlurmrestdToken, cl.LustreServer, cl.AlertmanagerURL, cl.RMUAddr, cl.BMCUser, cl.BMCPassword); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: name=%s", ErrClusterExists, cl.Name)
+		}
+		return fmt.Errorf("插入集群失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateCluster 更新集群(name)的配置, 名称本身不可修改. 当 cl.BMCPassword 或 cl.SlurmrestdToken 为空时保留原值.
+func (c *Client) UpdateCluster(ctx context.Context, name string, cl model.Cluster) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	const q = `
+        UPDATE cluster
+        SET description        = $1,
+            slurmrestd         = $2,
+            slurmrestd_flavor  = $3,
+            slurmrestd_version = $4,
+            slurmrestd_user    = $5,
+            slurmrestd_token   = COALESCE(NULLIF($6, ''), slurmrestd_token),
+            lustre_server      = $7,
+            alertmanager_url   = $8,
+            rmu_addr           = $9,
+            bmc_user           = $10,
+            bmc_password       = COALESCE(NULLIF($11, ''), bmc_password),
+            updated_at         = now()
+        WHERE name = $12
+    `
+	tag, err := conn.Exec(ctx, q, cl.Description, cl.Slurmrestd, cl.SlurmrestdFlavor, cl.SlurmrestdVersion,
+		cl.SlurmrestdUser, cl.SlurmrestdToken, cl.LustreServer, cl.AlertmanagerURL, cl.RMUAddr, cl.BMCUser, cl.BMCPassword, name)
+	if err != nil {
+		return fmt.Errorf("更新集群失败: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: name=%s", ErrClusterNotFound, name)
+	}
+	return nil
+}
+
+// DelCluster 删除集群(name).
+func (c *Client) DelCluster(ctx context.Context, name string) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(ctx, "DELETE FROM cluster WHERE name = $1", name)
+	if err != nil {
+		return fmt.Errorf("删除集群失败: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: name=%s", ErrClusterNotFound, name)
+	}
+	return nil
+}
+
+// GetClusterRoles 获取集群(name)的 Ldap 组与角色映射. 集群不存在时返回空列表.
+func (c *Client) GetClusterRoles(ctx context.Context, name string) (model.ClusterRoles, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	const q = `
+        SELECT r.ldap_group, r.role
+        FROM cluster_role r JOIN cluster c ON c.id = r.cluster_id
+        WHERE c.name = $1
+        ORDER BY r.ldap_group, r.role
+    `
+	rows, err := conn.Query(ctx, q, name)
+	if err != nil {
+		return nil, fmt.Errorf("查询数据库失败: %w", err)
+	}
+	defer rows.Close()
+
+	list := make(model.ClusterRoles, 0)
+	for rows.Next() {
+		var r model.ClusterRole
+		if err := rows.Scan(&r.Group, &r.Role); err != nil {
+			return nil, fmt.Errorf("读取数据失败: %w", err)
+		}
+		list = append(list, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取数据失败: %w", err)
+	}
+	return list, nil
+}
+
+// SetClusterRoles 使用 roles 替换集群(name)的全部 Ldap 组与角色映射. 集群不存在时返回 ErrClusterNotFound.
+func (c *Client) SetClusterRoles(ctx context.Context, name string, roles model.ClusterRoles) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+	if err := tx.QueryRow(ctx, "SELECT id FROM cluster WHERE name = $1 FOR UPDATE", name).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: name=%s", ErrClusterNotFound, name)
+		}
+		return fmt.Errorf("查询数据库失败: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM cluster_role WHERE cluster_id = $1", id); err != nil {
+		return fmt.Errorf("删除角色映射失败: %w", err)
+	}
+	for _, r := range roles {
+		const q = `
+            INSERT INTO cluster_role (cluster_id, ldap_group, role)
+            VALUES ($1, $2, $3)
+            ON CONFLICT DO NOTHING
+        `
+		if _, err := tx.Exec(ctx, q, id, r.Group, r.Role); err != nil {
+			return fmt.Errorf("插入角色映射失败: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// 数据库表结构迁移文件, 命名格式为 NNNN_<name>.up.sql / NNNN_<name>.down.sql, 版本号从 1 开始连续递增.
+// 当前版本记录在 Model.version 中, 旧版 postgres.sql 写入的 "1.0.0" 视为版本 1.
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// migrationLockID 迁移时使用的 advisory lock ID, 防止多个实例同时迁移.
+const migrationLockID int64 = 0x63736a6b
+
+// legacyVersion 旧版 postgres.sql 写入的版本号, 对应迁移版本 1.
+const legacyVersion = "1.0.0"
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的迁移脚本.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Migrations 返回所有内置迁移, 按版本升序排列.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", e.Name())
+		}
+		v, _ := strconv.Atoi(m[1])
+		b, err := migrationFS.ReadFile(path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read migration %s: %w", e.Name(), err)
+		}
+		mg, ok := byVersion[v]
+		if !ok {
+			mg = &Migration{Version: v, Name: m[2]}
+			byVersion[v] = mg
+		} else if mg.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s, %s", v, mg.Name, m[2])
+		}
+		if m[3] == "up" {
+			mg.Up = string(b)
+		} else {
+			mg.Down = string(b)
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		list = append(list, *mg)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	for i, mg := range list {
+		if mg.Version != i+1 {
+			return nil, fmt.Errorf("migration versions must be contiguous from 1, missing version %d", i+1)
+		}
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("migration %d(%s) must have both up and down scripts", mg.Version, mg.Name)
+		}
+	}
+	return list, nil
+}
+
+// LatestSchemaVersion 返回内置迁移的最新版本.
+func LatestSchemaVersion() (int, error) {
+	list, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+	return len(list), nil
+}
+
+// SchemaVersion 返回数据库当前的表结构版本, 未初始化时为 0.
+func (c *Client) SchemaVersion(ctx context.Context) (int, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+	return readSchemaVersion(ctx, conn.Conn())
+}
+
+func readSchemaVersion(ctx context.Context, conn *pgx.Conn) (int, error) {
+	var v string
+	err := conn.QueryRow(ctx, "SELECT version FROM model WHERE id = 1").Scan(&v)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, pgx.ErrNoRows) || (errors.As(err, &pgErr) && pgErr.Code == "42P01") {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("unable to read schema version: %w", err)
+	}
+	if v == legacyVersion {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version in model table: %q", v)
+	}
+	return n, nil
+}
+
+// Migrate 将数据库迁移到最新版本, 返回迁移前的版本.
+func (c *Client) Migrate(ctx context.Context) (int, error) {
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		return 0, err
+	}
+	return c.MigrateTo(ctx, latest)
+}
+
+// MigrateTo 将数据库迁移到指定版本(可升级或回退), 返回迁移前的版本.
+// 每个版本在单独的事务中执行, 迁移期间持有 advisory lock, 其他实例会等待.
+func (c *Client) MigrateTo(ctx context.Context, target int) (int, error) {
+	list, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+	if target < 0 || target > len(list) {
+		return 0, fmt.Errorf("invalid target schema version %d, latest is %d", target, len(list))
+	}
+
+	pc, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer pc.Release()
+	conn := pc.Conn()
+
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return 0, fmt.Errorf("unable to acquire migration lock: %w", err)
+	}
+	defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	// 加锁后读取版本, 避免与其他实例重复迁移
+	current, err := readSchemaVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if current > len(list) {
+		return current, fmt.Errorf("database schema version %d is newer than this binary supports(%d)", current, len(list))
+	}
+
+	for v := current; v < target; v++ {
+		if err := applyMigration(ctx, conn, list[v].Up, v+1); err != nil {
+			return current, fmt.Errorf("migration %d(%s) up failed: %w", list[v].Version, list[v].Name, err)
+		}
+	}
+	for v := current; v > target; v-- {
+		if err := applyMigration(ctx, conn, list[v-1].Down, v-1); err != nil {
+			return current, fmt.Errorf("migration %d(%s) down failed: %w", list[v-1].Version, list[v-1].Name, err)
+		}
+	}
+	return current, nil
+}
+
+// applyMigration 在事务中执行迁移脚本并记录新版本. 版本为 0 时 Model 表已被删除, 不再记录.
+func applyMigration(ctx context.Context, conn *pgx.Conn, script string, version int) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if version > 0 {
+		const q = `
+            INSERT INTO model (id, version) VALUES (1, $1)
+            ON CONFLICT (id) DO UPDATE SET version = EXCLUDED.version
+        `
+		if _, err := tx.Exec(ctx, q, strconv.Itoa(version)); err != nil {
+			return fmt.Errorf("unable to record schema version: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS Applications;
+DROP TABLE IF EXISTS AlertAnnotation;
+DROP TABLE IF EXISTS AlertLabel;
+DROP TABLE IF EXISTS Alert;
+DROP TABLE IF EXISTS Model;
@@ -1,11 +1,10 @@
+-- 初始表结构, 对应旧版 postgres.sql(Model.version = 1.0.0).
 CREATE TABLE Model (
     ID INTEGER UNIQUE DEFAULT(1),
     version VARCHAR(20) NOT NULL,
     CONSTRAINT single_row CHECK (ID = 1)
 );
 
-INSERT INTO Model (version) VALUES ('1.0.0');
-
 CREATE TABLE Alert (
 	ID SERIAL NOT NULL PRIMARY KEY,
     fingerprint TEXT NOT NULL,
@@ -36,8 +35,8 @@ CREATE TABLE AlertAnnotation (
 CREATE INDEX idx_alert_startsat ON alert (startsat);
 CREATE INDEX idx_alert_endsat ON alert (endsat);
 CREATE INDEX idx_alertlabel_label_value_alertid ON alertlabel (label, value, alertid);
-CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_alertlabel_label ON alertlabel (label);
-CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_alertlabel_label_value ON public.alertlabel (label, value);
+CREATE INDEX IF NOT EXISTS idx_alertlabel_label ON alertlabel (label);
+CREATE INDEX IF NOT EXISTS idx_alertlabel_label_value ON public.alertlabel (label, value);
 
 CREATE TABLE Applications (
     ID SERIAL NOT NULL PRIMARY KEY,
@@ -50,4 +49,4 @@ CREATE TABLE Applications (
     Reviewer VARCHAR(100) NULL, -- 审核用户 ID
     Decision text NULL, -- 审核结果
     Content JSON NOT NULL -- 申请内容
-)
\ No newline at end of file
+);
@@ -0,0 +1,16 @@
+-- 集群注册表
+CREATE TABLE IF NOT EXISTS Cluster (
+    ID SERIAL NOT NULL PRIMARY KEY,
+    Name VARCHAR(100) NOT NULL UNIQUE, -- 集群名称, 对应路由中的 :cluster
+    Description TEXT NOT NULL DEFAULT(''),
+    Slurmrestd VARCHAR(255) NOT NULL DEFAULT(''), -- slurmrestd 地址 host:port
+    Slurmrestd_Flavor VARCHAR(20) NOT NULL DEFAULT('custom'), -- custom / official
+    Slurmrestd_Version VARCHAR(20) NOT NULL DEFAULT(''), -- 如 v0.0.40
+    Lustre_Server VARCHAR(255) NOT NULL DEFAULT(''), -- KD Lustre 服务地址 host:port
+    Alertmanager_URL VARCHAR(255) NOT NULL DEFAULT(''), -- 如 http://host:9093
+    RMU_Addr VARCHAR(255) NOT NULL DEFAULT(''), -- 默认 RMU 地址
+    BMC_User VARCHAR(100) NOT NULL DEFAULT(''), -- 默认 BMC 用户名
+    BMC_Password VARCHAR(255) NOT NULL DEFAULT(''), -- 默认 BMC 密码
+    Created_At TIMESTAMPTZ NOT NULL DEFAULT now(),
+    Updated_At TIMESTAMPTZ NOT NULL DEFAULT now()
+);
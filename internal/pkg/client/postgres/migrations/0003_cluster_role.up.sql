@@ -0,0 +1,8 @@
+-- 集群角色映射: 将 Ldap 组映射为某集群下的角色(admin/reviewer/operator/user)
+CREATE TABLE IF NOT EXISTS Cluster_Role (
+    ID SERIAL NOT NULL PRIMARY KEY,
+    Cluster_ID INT NOT NULL REFERENCES Cluster (ID) ON DELETE CASCADE,
+    Ldap_Group VARCHAR(100) NOT NULL, -- Ldap 组名
+    Role VARCHAR(20) NOT NULL, -- 角色
+    CONSTRAINT Cluster_Role_Unique UNIQUE (Cluster_ID, Ldap_Group, Role)
+);
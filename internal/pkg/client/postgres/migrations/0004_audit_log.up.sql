@@ -0,0 +1,20 @@
+-- 审计日志: 记录每个修改类(POST/PUT/PATCH/DELETE)接口调用
+CREATE TABLE IF NOT EXISTS Audit_Log (
+    ID BIGSERIAL NOT NULL PRIMARY KEY,
+    Created_At TIMESTAMPTZ NOT NULL DEFAULT now(), -- 请求开始时间
+    Actor VARCHAR(100) NOT NULL DEFAULT(''), -- 调用者, 未认证时为空
+    Cluster VARCHAR(100) NOT NULL DEFAULT(''), -- 集群名称
+    Method VARCHAR(10) NOT NULL, -- HTTP 方法
+    Route VARCHAR(255) NOT NULL, -- 路由模板, 如 /api/v1/:cluster/ldap/user/:name
+    Path TEXT NOT NULL, -- 实际请求路径
+    Target VARCHAR(255) NOT NULL DEFAULT(''), -- 操作对象, 即路径参数(除 cluster 外), 如 name=foo
+    Body TEXT NOT NULL DEFAULT(''), -- 脱敏后的请求体
+    Status INT NOT NULL, -- HTTP 响应码
+    Outcome VARCHAR(20) NOT NULL, -- success / failure
+    Duration_MS BIGINT NOT NULL DEFAULT(0), -- 耗时(毫秒)
+    Client_IP VARCHAR(64) NOT NULL DEFAULT('')
+);
+
+CREATE INDEX IF NOT EXISTS Audit_Log_Created_At_Idx ON Audit_Log (Created_At DESC);
+CREATE INDEX IF NOT EXISTS Audit_Log_Actor_Idx ON Audit_Log (Actor, Created_At DESC);
+CREATE INDEX IF NOT EXISTS Audit_Log_Route_Idx ON Audit_Log (Route, Created_At DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE Cluster DROP COLUMN IF EXISTS Slurmrestd_Token;
+ALTER TABLE Cluster DROP COLUMN IF EXISTS Slurmrestd_User;
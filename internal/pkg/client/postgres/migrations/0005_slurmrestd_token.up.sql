@@ -0,0 +1,3 @@
+-- 官方 slurmrestd 认证信息(X-SLURM-USER-NAME / X-SLURM-USER-TOKEN), 仅 Slurmrestd_Flavor = 'official' 时使用
+ALTER TABLE Cluster ADD COLUMN IF NOT EXISTS Slurmrestd_User VARCHAR(100) NOT NULL DEFAULT('');
+ALTER TABLE Cluster ADD COLUMN IF NOT EXISTS Slurmrestd_Token TEXT NOT NULL DEFAULT('');
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS Node_State_Change;
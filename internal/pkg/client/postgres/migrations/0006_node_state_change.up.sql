@@ -0,0 +1,12 @@
+-- 节点状态变更记录: 通过 PUT /api/v1/:cluster/slurm/node/:name/state 执行的 drain/resume/down/reboot
+CREATE TABLE IF NOT EXISTS Node_State_Change (
+    ID BIGSERIAL NOT NULL PRIMARY KEY,
+    Created_At TIMESTAMPTZ NOT NULL DEFAULT now(), -- 变更时间
+    Cluster VARCHAR(100) NOT NULL, -- 集群名称
+    Node VARCHAR(255) NOT NULL, -- 节点名称
+    State VARCHAR(20) NOT NULL, -- 目标状态: DRAIN / RESUME / DOWN / REBOOT
+    Reason TEXT NOT NULL, -- 原因
+    Actor VARCHAR(100) NOT NULL DEFAULT('') -- 操作者, 未认证时为空
+);
+
+CREATE INDEX IF NOT EXISTS Node_State_Change_Node_Idx ON Node_State_Change (Cluster, Node, Created_At DESC);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS Applications_Class_State_Idx;
+ALTER TABLE Applications DROP COLUMN IF EXISTS Notified_At;
+ALTER TABLE Applications DROP COLUMN IF EXISTS State_Changed_At;
+ALTER TABLE Applications DROP COLUMN IF EXISTS Cluster;
@@ -0,0 +1,11 @@
+-- 申请所属集群及状态变更时间, 用于资源预约申请的生命周期管理(生效, 到期, 取消)
+ALTER TABLE Applications ADD COLUMN IF NOT EXISTS Cluster VARCHAR(100) NOT NULL DEFAULT(''); -- 集群名称, 历史数据为空
+ALTER TABLE Applications ADD COLUMN IF NOT EXISTS State_Changed_At TIMESTAMPTZ NULL; -- 审核后最近一次状态变更时间
+ALTER TABLE Applications ADD COLUMN IF NOT EXISTS Notified_At TIMESTAMPTZ NULL; -- 到期提醒发送时间
+
+-- 回填历史申请的集群: 仅注册了一个集群时, 历史申请均属于该集群.
+-- 注册了多个集群时无法确定, 保持为空, 这些申请在任意集群下可见(见 Application.InCluster), 可由管理员手动更新.
+UPDATE Applications SET Cluster = (SELECT Name FROM Cluster)
+WHERE Cluster = '' AND (SELECT COUNT(*) FROM Cluster) = 1;
+
+CREATE INDEX IF NOT EXISTS Applications_Class_State_Idx ON Applications (Class, State);
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// 审计结果
+const (
+	AUDIT_OUTCOME_SUCCESS = "success" // 响应码 < 400
+	AUDIT_OUTCOME_FAILURE = "failure"
+)
+
+type AuditLogs []AuditLog
+
+// AuditLog 对应数据库中 Audit_Log 表, 记录一次修改类接口调用.
+type AuditLog struct {
+	ID         int64     `gorm:"column:id" json:"id"`                   // ID
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`   // 请求开始时间
+	Actor      string    `gorm:"column:actor" json:"actor"`             // 调用者, 未认证时为空
+	Cluster    string    `gorm:"column:cluster" json:"cluster"`         // 集群名称
+	Method     string    `gorm:"column:method" json:"method"`           // HTTP 方法
+	Route      string    `gorm:"column:route" json:"route"`             // 路由模板, 如 /api/v1/:cluster/ldap/user/:name
+	Path       string    `gorm:"column:path" json:"path"`               // 实际请求路径
+	Target     string    `gorm:"column:target" json:"target"`           // 操作对象(路径参数), 如 name=foo
+	Body       string    `gorm:"column:body" json:"body"`               // 脱敏后的请求体
+	Status     int       `gorm:"column:status" json:"status"`           // HTTP 响应码
+	Outcome    string    `gorm:"column:outcome" json:"outcome"`         // success / failure
+	DurationMS int64     `gorm:"column:duration_ms" json:"duration_ms"` // 耗时(毫秒)
+	ClientIP   string    `gorm:"column:client_ip" json:"client_ip"`     // 客户端地址
+}
@@ -1,6 +1,38 @@
 package model
 
+import "time"
+
+// slurmrestd 接口类型
+const (
+	SLURMRESTD_FLAVOR_CUSTOM   = "custom"   // 自定义 slurmrest 服务(/api/v1/slurm/...)
+	SLURMRESTD_FLAVOR_OFFICIAL = "official" // 官方 slurmrestd(/slurm/..., /slurmdb/...)
+)
+
+type Clusters []Cluster
+
+// Cluster 对应数据库中 Cluster 表, 记录某集群各依赖服务的地址及默认配置.
 type Cluster struct {
-	Slurmrestd        string `gorm:"slurmrestd"`
-	SlurmrestdVersion string `gorm:"slurmrestd_version"`
+	ID                int       `gorm:"column:id" json:"id"`                                 // ID
+	Name              string    `gorm:"column:name" json:"name"`                             // 集群名称, 对应路由中的 :cluster
+	Description       string    `gorm:"column:description" json:"description"`               // 描述
+	Slurmrestd        string    `gorm:"column:slurmrestd" json:"slurmrestd"`                 // slurmrestd 服务地址(host:port)
+	SlurmrestdFlavor  string    `gorm:"column:slurmrestd_flavor" json:"slurmrestd_flavor"`   // slurmrestd 接口类型, custom 或 official
+	SlurmrestdVersion string    `gorm:"column:slurmrestd_version" json:"slurmrestd_version"` // slurmrestd 接口版本, 如 v0.0.40
+	SlurmrestdUser    string    `gorm:"column:slurmrestd_user" json:"slurmrestd_user"`       // 官方 slurmrestd 用户名(X-SLURM-USER-NAME), 可为空
+	SlurmrestdToken   string    `gorm:"column:slurmrestd_token" json:"-"`                    // 官方 slurmrestd 令牌(X-SLURM-USER-TOKEN), 须为 root 或 SlurmUser 的令牌以代用户提交作业, 不对外输出
+	LustreServer      string    `gorm:"column:lustre_server" json:"lustre_server"`           // KD Lustre 服务地址(host:port)
+	AlertmanagerURL   string    `gorm:"column:alertmanager_url" json:"alertmanager_url"`     // Alertmanager 地址, 如 http://host:9093
+	RMUAddr           string    `gorm:"column:rmu_addr" json:"rmu_addr"`                     // 默认 RMU 地址
+	BMCUser           string    `gorm:"column:bmc_user" json:"bmc_user"`                     // 默认 BMC(ipmitool) 用户名
+	BMCPassword       string    `gorm:"column:bmc_password" json:"-"`                        // 默认 BMC(ipmitool) 密码, 不对外输出
+	CreatedAt         time.Time `gorm:"column:created_at" json:"created_at"`                 // 创建时间
+	UpdatedAt         time.Time `gorm:"column:updated_at" json:"updated_at"`                 // 更新时间
+}
+
+type ClusterRoles []ClusterRole
+
+// ClusterRole 对应数据库中 Cluster_Role 表, 表示某集群下 Ldap 组(Group)的成员拥有角色(Role).
+type ClusterRole struct {
+	Group string `gorm:"column:ldap_group" json:"group"` // Ldap 组名
+	Role  string `gorm:"column:role" json:"role"`        // 角色, admin/reviewer/operator/user
 }
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type NodeStateChanges []NodeStateChange
+
+// NodeStateChange 对应数据库中 Node_State_Change 表, 记录一次节点状态变更.
+type NodeStateChange struct {
+	ID        int64     `gorm:"column:id" json:"id"`                 // ID
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"` // 变更时间
+	Cluster   string    `gorm:"column:cluster" json:"cluster"`       // 集群名称
+	Node      string    `gorm:"column:node" json:"node"`             // 节点名称
+	State     string    `gorm:"column:state" json:"state"`           // 目标状态: DRAIN / RESUME / DOWN / REBOOT
+	Reason    string    `gorm:"column:reason" json:"reason"`         // 原因
+	Actor     string    `gorm:"column:actor" json:"actor"`           // 操作者, 未认证时为空
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"csjk-bk/internal/pkg/client/postgres/model"
+)
+
+// AddNodeStateChanges 在同一事务中写入多条节点状态变更记录.
+func (c *Client) AddNodeStateChanges(ctx context.Context, changes model.NodeStateChanges) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, ch := range changes {
+		const q = `
+            INSERT INTO node_state_change (cluster, node, state, reason, actor)
+            VALUES ($1, $2, $3, $4, $5)
+        `
+		if _, err := tx.Exec(ctx, q, ch.Cluster, ch.Node, ch.State, ch.Reason, ch.Actor); err != nil {
+			return fmt.Errorf("插入节点状态变更记录失败: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交事务失败: %w", err)
+	}
+	return nil
+}
+
+// GetNodeStateChanges 按时间降序获取集群(cluster)中节点(node)的状态变更记录, 支持分页.
+func (c *Client) GetNodeStateChanges(ctx context.Context, cluster, node string, paging bool, page, pageSize int) (model.NodeStateChanges, int, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	var total int64
+	if err := conn.QueryRow(ctx, "SELECT COUNT(*) FROM node_state_change WHERE cluster = $1 AND node = $2", cluster, node).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("统计总数失败: %w", err)
+	}
+
+	q := "SELECT id, created_at, cluster, node, state, reason, actor FROM node_state_change WHERE cluster = $1 AND node = $2 ORDER BY created_at DESC, id DESC"
+	args := []any{cluster, node}
+	if paging && pageSize > 0 {
+		off := (page - 1) * pageSize
+		if off < 0 {
+			off = 0
+		}
+		args = append(args, pageSize, off)
+		q += " LIMIT $3 OFFSET $4"
+	}
+
+	rows, err := conn.Query(ctx, q, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询数据库失败: %w", err)
+	}
+	defer rows.Close()
+
+	list := make(model.NodeStateChanges, 0)
+	for rows.Next() {
+		var ch model.NodeStateChange
+		if err := rows.Scan(&ch.ID, &ch.CreatedAt, &ch.Cluster, &ch.Node, &ch.State, &ch.Reason, &ch.Actor); err != nil {
+			return nil, 0, fmt.Errorf("读取数据失败: %w", err)
+		}
+		list = append(list, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("读取数据失败: %w", err)
+	}
+
+	return list, int(total), nil
+}
@@ -3,10 +3,13 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"csjk-bk/internal/pkg/apperror"
+
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -56,22 +59,27 @@ func NewWithPool(p Pool) *Client { return &Client{pool: p} }
 // Pool 返回底层连接池接口，便于执行查询或扩展能力。
 func (c *Client) Pool() Pool { return c.pool }
 
-// Close 关闭底层连接池。
-func (c *Client) Close() {
-	if c != nil && c.pool != nil {
-		c.pool.Close()
+// Ping 检查数据库连通性。
+func (c *Client) Ping(ctx context.Context) error {
+	if c == nil || c.pool == nil {
+		return fmt.Errorf("nil postgres client")
 	}
+	return c.pool.Ping(ctx)
 }
 
-// GetSlurmrestAddr 获取 slurmrestd 服务地址
-func (c *Client) GetSlurmrestdAddr(cluster string) (string, error) {
-	return "192.168.2.35:39999", nil
-	// return "localhost:8080", nil
+// Stat 返回连接池统计信息，底层 Pool 不是 pgxpool.Pool 时返回 nil。
+func (c *Client) Stat() *pgxpool.Stat {
+	if p, ok := c.pool.(interface{ Stat() *pgxpool.Stat }); ok {
+		return p.Stat()
+	}
+	return nil
 }
 
-// GetLustreServer 获取某集群 Lustre Server 的地址
-func (c *Client) GetLustreServer(cluster string) (string, error) {
-	return "lustre.com", nil
+// Close 关闭底层连接池。
+func (c *Client) Close() {
+	if c != nil && c.pool != nil {
+		c.pool.Close()
+	}
 }
 
 type Alerts []*Alert
@@ -244,6 +252,9 @@ const (
 	APPLICATION_STATE_PASSED
 	APPLICATION_STATE_REVIEWING
 	APPLICATION_STATE_PASSED_UNSUCCESS
+	APPLICATION_STATE_ACTIVE    // 资源预约已生效(开始时间已到)
+	APPLICATION_STATE_EXPIRED   // 资源预约已到期, 残留的 Slurm 预约已释放
+	APPLICATION_STATE_CANCELLED // 资源预约到期前 Slurm 预约已被删除
 )
 
 const (
@@ -251,21 +262,33 @@ const (
 	APPLICATION_CLASS_RESOURCE = "slurm"
 )
 
+// ErrApplicationNotFound 申请不存在.
+var ErrApplicationNotFound = apperror.New(apperror.CODE_APPLICATION_NOT_FOUND, "application not found")
+
 type Applications []Application
 type Application struct {
-	ID       int
-	Class    string
-	State    int
-	ApplyAt  time.Time
-	ReviewAt time.Time
-	Applier  string
-	Reviewer string
-	Decision string
-	Content  string
+	ID             int
+	Class          string
+	Cluster        string // 集群名称, 迁移前且未能回填的历史申请为空(见 InCluster)
+	State          int
+	ApplyAt        time.Time
+	ReviewAt       time.Time
+	StateChangedAt time.Time // 审核后最近一次状态变更时间(生效, 到期等)
+	NotifiedAt     time.Time // 到期提醒发送时间
+	Applier        string
+	Reviewer       string
+	Decision       string
+	Content        string
+}
+
+// InCluster 判断申请是否属于集群(name). 集群为空的历史申请(迁移时注册了多个集群, 无法回填)属于任意集群,
+// 以免升级后无法查看, 审核或删除.
+func (a Application) InCluster(name string) bool {
+	return a.Cluster == "" || a.Cluster == name
 }
 
-// GetApplications 根据申请人ID筛选其申请, 并按照 State, ApplyAt 降序排序.
-func (c *Client) GetApplications(ctx context.Context, applyType string, applier string, paging bool, page, pageSize int) (Applications, int, error) {
+// GetApplications 根据集群及申请人筛选其申请, 并按照 State, ApplyAt 降序排序. cluster 为空时不按集群过滤.
+func (c *Client) GetApplications(ctx context.Context, applyType string, cluster string, applier string, paging bool, page, pageSize int) (Applications, int, error) {
 	conn, err := c.pool.Acquire(ctx)
 	if err != nil {
 		return nil, 0, fmt.Errorf("无法获取数据库连接: %w", err)
@@ -281,6 +304,14 @@ func (c *Client) GetApplications(ctx context.Context, applyType string, applier
 	whereSB.WriteString(fmt.Sprint(idx))
 	args = append(args, applyType)
 	idx++
+	if cluster != "" {
+		// 集群为空的历史申请属于任意集群, 见 Application.InCluster
+		whereSB.WriteString(" AND (cluster = $")
+		whereSB.WriteString(fmt.Sprint(idx))
+		whereSB.WriteString(" OR cluster = '')")
+		args = append(args, cluster)
+		idx++
+	}
 	if applier != "" {
 		whereSB.WriteString(" AND applier = $")
 		whereSB.WriteString(fmt.Sprint(idx))
@@ -297,7 +328,7 @@ func (c *Client) GetApplications(ctx context.Context, applyType string, applier
 
 	// 3) 查询列表（可分页）
 	var listSB strings.Builder
-	listSB.WriteString("SELECT id, state, applyat, reviewat, applier, reviewer, decision, content::text FROM applications")
+	listSB.WriteString("SELECT " + applicationColumns + " FROM applications")
 	listSB.WriteString(whereSB.String())
 	listSB.WriteString(" ORDER BY state DESC, applyat DESC")
 
@@ -329,24 +360,10 @@ func (c *Client) GetApplications(ctx context.Context, applyType string, applier
 
 	list := make(Applications, 0)
 	for rows.Next() {
-		var (
-			a   Application
-			rAt sql.NullTime
-			rer sql.NullString
-			dec sql.NullString
-		)
-		if err := rows.Scan(&a.ID, &a.State, &a.ApplyAt, &rAt, &a.Applier, &rer, &dec, &a.Content); err != nil {
+		a, err := scanApplication(rows)
+		if err != nil {
 			return nil, 0, fmt.Errorf("读取数据失败: %w", err)
 		}
-		if rAt.Valid {
-			a.ReviewAt = rAt.Time
-		}
-		if rer.Valid {
-			a.Reviewer = rer.String
-		}
-		if dec.Valid {
-			a.Decision = dec.String
-		}
 		list = append(list, a)
 	}
 	if err := rows.Err(); err != nil {
@@ -356,6 +373,46 @@ func (c *Client) GetApplications(ctx context.Context, applyType string, applier
 	return list, int(total), nil
 }
 
+// GetApplication 获取申请(id), 申请不存在时返回 ErrApplicationNotFound.
+func (c *Client) GetApplication(ctx context.Context, id int) (Application, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return Application{}, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	const q = "SELECT " + applicationColumns + " FROM applications WHERE id = $1"
+	a, err := scanApplication(conn.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Application{}, fmt.Errorf("%w: id=%d", ErrApplicationNotFound, id)
+		}
+		return Application{}, fmt.Errorf("查询数据库失败: %w", err)
+	}
+	return a, nil
+}
+
+// applicationColumns scanApplication 读取的列.
+const applicationColumns = "id, class, cluster, state, applyat, reviewat, state_changed_at, notified_at, applier, reviewer, decision, content::text"
+
+// scanApplication 按 applicationColumns 的顺序读取申请.
+func scanApplication(row pgx.Row) (Application, error) {
+	var (
+		a   Application
+		rAt sql.NullTime
+		sAt sql.NullTime
+		nAt sql.NullTime
+		rer sql.NullString
+		dec sql.NullString
+	)
+	if err := row.Scan(&a.ID, &a.Class, &a.Cluster, &a.State, &a.ApplyAt, &rAt, &sAt, &nAt, &a.Applier, &rer, &dec, &a.Content); err != nil {
+		return Application{}, err
+	}
+	a.ReviewAt, a.StateChangedAt, a.NotifiedAt = rAt.Time, sAt.Time, nAt.Time
+	a.Reviewer, a.Decision = rer.String, dec.String
+	return a, nil
+}
+
 // GetApplicaitionDicision 获取某个申请的审核结果.
 func (c *Client) GetApplicationDecision(ctx context.Context, id int) (string, error) {
 	conn, err := c.pool.Acquire(ctx)
@@ -367,8 +424,8 @@ func (c *Client) GetApplicationDecision(ctx context.Context, id int) (string, er
 	var dec sql.NullString
 	err = conn.QueryRow(ctx, "SELECT decision FROM applications WHERE id = $1", id).Scan(&dec)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return "", fmt.Errorf("application not found: id=%d", id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("%w: id=%d", ErrApplicationNotFound, id)
 		}
 		return "", fmt.Errorf("查询数据库失败: %w", err)
 	}
@@ -393,10 +450,10 @@ func (c *Client) AddApplication(ctx context.Context, app Application) error {
 
 	// 仅插入必要字段，ApplyAt 走默认 now()；class 固定为配额申请
 	const q = `
-        INSERT INTO applications (class, state, applier, content)
-        VALUES ($1, $2, $3, $4::json)
+        INSERT INTO applications (class, cluster, state, applier, content)
+        VALUES ($1, $2, $3, $4, $5::json)
     `
-	if _, err := conn.Exec(ctx, q, app.Class, app.State, app.Applier, app.Content); err != nil {
+	if _, err := conn.Exec(ctx, q, app.Class, app.Cluster, app.State, app.Applier, app.Content); err != nil {
 		return fmt.Errorf("插入申请失败: %w", err)
 	}
 	return nil
@@ -422,7 +479,9 @@ func (c *Client) UpdateApplication(ctx context.Context, id int, content string)
             applyat = now(),
             reviewat = NULL,
             reviewer = NULL,
-            decision = NULL
+            decision = NULL,
+            state_changed_at = NULL,
+            notified_at = NULL
         WHERE id = $3
     `
 	tag, err := conn.Exec(ctx, q, content, APPLICATION_STATE_REVIEWING, id)
@@ -430,7 +489,7 @@ func (c *Client) UpdateApplication(ctx context.Context, id int, content string)
 		return fmt.Errorf("更新申请失败: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("application not found: id=%d", id)
+		return fmt.Errorf("%w: id=%d", ErrApplicationNotFound, id)
 	}
 	return nil
 }
@@ -448,13 +507,13 @@ func (c *Client) DelApplication(ctx context.Context, id int) error {
 		return fmt.Errorf("删除申请失败: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("application not found: id=%d", id)
+		return fmt.Errorf("%w: id=%d", ErrApplicationNotFound, id)
 	}
 	return nil
 }
 
-// DoQuotaReview 执行审核, 根据参数更新 state, decision, content 字段. 同时更新 reviewat = now().
-func (c *Client) DoReview(ctx context.Context, id, state int, descision, content string) error {
+// DoQuotaReview 执行审核, 根据参数更新 state, reviewer, decision, content 字段. 同时更新 reviewat = now().
+func (c *Client) DoReview(ctx context.Context, id, state int, reviewer, descision, content string) error {
 	conn, err := c.pool.Acquire(ctx)
 	if err != nil {
 		return fmt.Errorf("无法获取数据库连接: %w", err)
@@ -470,19 +529,107 @@ func (c *Client) DoReview(ctx context.Context, id, state int, descision, content
         SET decision = $1,
             content  = $2::json,
             reviewat = now(),
-            state    = $3
-        WHERE id = $4
+            state    = $3,
+            reviewer = NULLIF($4, ''),
+            state_changed_at = NULL,
+            notified_at = NULL
+        WHERE id = $5
     `
-	tag, err := conn.Exec(ctx, q, descision, content, state, id)
+	tag, err := conn.Exec(ctx, q, descision, content, state, reviewer, id)
 	if err != nil {
 		return fmt.Errorf("审核更新失败: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("application not found: id=%d", id)
+		return fmt.Errorf("%w: id=%d", ErrApplicationNotFound, id)
+	}
+	return nil
+}
+
+// GetApplicationsByState 获取 class 类申请中状态为 states 之一的全部申请, 按 ID 升序排列.
+func (c *Client) GetApplicationsByState(ctx context.Context, class string, states ...int) (Applications, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	q := "SELECT " + applicationColumns + " FROM applications WHERE class = $1 AND state = ANY($2) ORDER BY id"
+	rows, err := conn.Query(ctx, q, class, states)
+	if err != nil {
+		return nil, fmt.Errorf("查询数据库失败: %w", err)
+	}
+	defer rows.Close()
+
+	list := make(Applications, 0)
+	for rows.Next() {
+		a, err := scanApplication(rows)
+		if err != nil {
+			return nil, fmt.Errorf("读取数据失败: %w", err)
+		}
+		list = append(list, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取数据失败: %w", err)
+	}
+	return list, nil
+}
+
+// TransitApplication 仅当申请(id)的状态为 from 时将其更新为 to, 并记录 state_changed_at = now().
+// 返回是否更新, 状态已被其他实例或审核人修改时返回 false.
+func (c *Client) TransitApplication(ctx context.Context, id, from, to int) (bool, error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	const q = "UPDATE applications SET state = $1, state_changed_at = now() WHERE id = $2 AND state = $3"
+	tag, err := conn.Exec(ctx, q, to, id, from)
+	if err != nil {
+		return false, fmt.Errorf("更新申请状态失败: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// MarkApplicationNotified 记录申请(id)的到期提醒已发送.
+func (c *Client) MarkApplicationNotified(ctx context.Context, id int) error {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "UPDATE applications SET notified_at = now() WHERE id = $1", id); err != nil {
+		return fmt.Errorf("更新申请失败: %w", err)
 	}
 	return nil
 }
 
+// LOCK_RESERVATION_LIFECYCLE 资源预约生命周期扫描使用的 advisory lock ID, 多个实例中同一时间仅一个实例扫描.
+const LOCK_RESERVATION_LIFECYCLE int64 = 0x63736a6c
+
+// TryAdvisoryLock 尝试获取会话级 advisory lock(id), 不等待. 获取成功时返回释放锁的函数, 锁被其他实例持有时 ok 为 false.
+// 持有锁期间占用一个数据库连接, 调用方须调用 unlock.
+func (c *Client) TryAdvisoryLock(ctx context.Context, id int64) (unlock func(), ok bool, err error) {
+	conn, err := c.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("无法获取数据库连接: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("unable to acquire advisory lock: %w", err)
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+	return func() {
+		conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", id)
+		conn.Release()
+	}, true, nil
+}
+
 // func (c *Client) DoSlurmApplicationReview(ctx context.Context, id, state int, decision string) error {
 // 	conn, err := c.pool.Acquire(ctx)
 // 	if err != nil {
@@ -0,0 +1,101 @@
+package slurmrest
+
+import (
+	"context"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/cache"
+	pgmodel "csjk-bk/internal/pkg/client/postgres/model"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+)
+
+// Backend slurmrestd 客户端接口, 各模块 handler 依赖该接口而非具体实现.
+// 目前有两种实现: Client(自定义 slurmrest 服务, /api/v1/slurm/...) 及 Official(官方 slurmrestd, /slurm/..., /slurmdb/...).
+// 实现不支持的操作返回 ErrUnsupported.
+type Backend interface {
+	GetNodes(ctx context.Context, addr string, partitions []string, paging bool, page, pageSize int) (model.Nodes, int, error)
+	UpdateNodeState(ctx context.Context, addr string, nodes []string, state NodeState, reason string) error
+	GetSchedulingJobs(ctx context.Context, addr string, filter model.SchedulingJobFilter, paging bool, page, pageSize int) (model.JobsInScheduling, int64, error)
+	GetJobStepsOfScheduling(ctx context.Context, addr, jobid string) (model.JobsStepsInScheduling, error)
+	GetSchedulingJob(ctx context.Context, addr, jobid string) (model.JobInScheduling, error)
+	ControlJob(ctx context.Context, addr, jobid string, action JobAction, signal string) error
+	SubmitJob(ctx context.Context, addr string, job model.JobSubmission, validateOnly bool) (model.JobSubmitResult, error)
+	GetReservations(ctx context.Context, addr string, paging bool, page, pageSize int) (model.Reservations, int, error)
+	CreateReservation(ctx context.Context, addr string, resv model.Reservation) (string, error)
+	UpdateReservation(ctx context.Context, addr string, resv model.Reservation) error
+	DeleteReservation(ctx context.Context, addr, name string) error
+	GetPartitions(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error)
+	GetPartitionByName(ctx context.Context, addr string, name string) (map[string]string, error)
+	GetQos(ctx context.Context, addr string, id uint32) (model.QoS, error)
+	GetQosAll(ctx context.Context, addr string, paging bool, page, pageSize int) (model.QoSes, int, error)
+	CreateQoS(ctx context.Context, addr string, qos model.QoSWrite) error
+	UpdateQoS(ctx context.Context, addr string, qos model.QoSWrite) error
+	DeleteQoS(ctx context.Context, addr, name string) error
+	GetTres(ctx context.Context, addr string) (model.TresList, error)
+	GetAccounts(ctx context.Context, addr string, paging bool, page, pageSize int) (model.Accounts, int, error)
+	GetAccountByName(ctx context.Context, addr, name string) (model.Account, error)
+	CreateAccount(ctx context.Context, addr string, acct model.AccountWrite) error
+	DeleteAccount(ctx context.Context, addr, name string) error
+	GetChildNodesOfAccount(ctx context.Context, addr, name string) (AccountNode, error)
+	GetAssociationChildNodesOfAccount(ctx context.Context, addr, name string) (AssociationNode, error)
+	GetAssociationDetail(ctx context.Context, addr, acct, user, partition string) (model.AssociationItem, error)
+	AddUserAssociation(ctx context.Context, addr string, ua model.UserAssociation) error
+	UpdateAssociation(ctx context.Context, addr string, assoc model.AssociationWrite) error
+	SetDefaultAccount(ctx context.Context, addr, user, account string) error
+	GetJobsFromAccounting(ctx context.Context, addr string, filter model.JobFilter, paging bool, page, pageSize int) (model.Jobs, int, error)
+	GetJobFromAccounting(ctx context.Context, addr string, jobid uint32) (model.Job, error)
+	GetStepsOfJobFromAccounting(ctx context.Context, addr string, jobid uint32) (model.Steps, error)
+
+	GetLdapUsers(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error)
+	GetAdditionalGroupsOfUser(ctx context.Context, addr string, name string) ([]string, error)
+	AuthLdapUser(ctx context.Context, addr, name, password string) error
+	AddUser(ctx context.Context, addr string, user map[string]string) error
+	UpdateLdapUser(ctx context.Context, addr, user string, attr map[string]string) error
+	AddMemberForGroups(ctx context.Context, addr string, user string, groups []string) error
+	DelLdapUser(ctx context.Context, addr, user string) error
+	AddGroup(ctx context.Context, addr string, group map[string]string) error
+	DelLdapGroup(ctx context.Context, addr, group string) error
+	UpdateLdapGroup(ctx context.Context, addr, group string, attr map[string]string) error
+	GetLdapGroups(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error)
+
+	Ping(ctx context.Context, addr string) error
+}
+
+var (
+	_ Backend = (*Client)(nil)
+	_ Backend = (*Official)(nil)
+)
+
+// ErrUnsupported 集群所用的 slurmrestd 不支持该操作, 如官方 slurmrestd 不提供 Ldap 用户及组管理.
+var ErrUnsupported = apperror.New(apperror.CODE_UNSUPPORTED, "operation not supported by official slurmrestd")
+
+// Backends 按集群的 slurmrestd 接口类型(Cluster.SlurmrestdFlavor)选择客户端.
+type Backends struct {
+	custom   *Client
+	official *Official
+	cache    *cache.Cache
+}
+
+// NewBackends 创建 Backends. custom 对应自定义 slurmrest 服务, official 对应官方 slurmrestd;
+// c 不为空时缓存变化较慢的数据(见 CacheResources), 资源类型即 cache.Load 的 resource.
+func NewBackends(custom *Client, official *Official, c *cache.Cache) *Backends {
+	return &Backends{custom: custom, official: official, cache: c}
+}
+
+// For 返回集群(cl)对应的客户端. 接口类型为 official 时使用集群配置的接口版本及令牌, 否则使用自定义 slurmrest 服务.
+func (b *Backends) For(cl pgmodel.Cluster) Backend {
+	var backend Backend = b.custom
+	if cl.SlurmrestdFlavor == pgmodel.SLURMRESTD_FLAVOR_OFFICIAL {
+		backend = b.official.With(cl.SlurmrestdVersion, cl.SlurmrestdUser, cl.SlurmrestdToken)
+	}
+	if b.cache == nil {
+		return backend
+	}
+	return &cached{Backend: backend, cache: b.cache, cluster: cl.Name}
+}
+
+// Invalidate 使集群(cluster)中资源 resources(CACHE_NODES 等)的缓存失效, resources 为空时使该集群的全部缓存失效.
+// 修改 Slurm 数据或集群配置后调用.
+func (b *Backends) Invalidate(cluster string, resources ...string) {
+	b.cache.Invalidate(cluster, resources...)
+}
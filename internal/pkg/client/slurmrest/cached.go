@@ -0,0 +1,138 @@
+package slurmrest
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"csjk-bk/internal/pkg/cache"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+)
+
+// 可缓存的资源类型, 用于配置 TTL 及 Backends.Invalidate.
+const (
+	CACHE_NODES      = "nodes"      // 节点
+	CACHE_PARTITIONS = "partitions" // 分区
+	CACHE_QOS        = "qos"        // QoS
+	CACHE_ACCOUNTS   = "accounts"   // 账户及关联(association)
+	CACHE_TRES       = "tres"       // TRES 类型
+	CACHE_JOBS       = "jobs"       // 调度队列中的作业, 变化频繁, TTL 应较短
+)
+
+// CacheResources 全部可缓存的资源类型.
+var CacheResources = []string{CACHE_NODES, CACHE_PARTITIONS, CACHE_QOS, CACHE_ACCOUNTS, CACHE_TRES, CACHE_JOBS}
+
+// cached 在 Backend 之前缓存变化较慢的数据(节点, 分区, QoS, 账户, TRES 类型)及短时间内的调度队列, 其余方法直接调用 Backend.
+// 缓存键包含 slurmrestd 地址及全部查询参数. 返回的列表及 map 为缓存值的浅拷贝, 调用方可排序, 过滤或增删元素,
+// 但不得修改元素内部的数据.
+type cached struct {
+	Backend
+	cache   *cache.Cache
+	cluster string
+}
+
+func cacheKey(addr string, args ...any) string {
+	var b strings.Builder
+	b.WriteString(addr)
+	for _, a := range args {
+		fmt.Fprintf(&b, "\x00%v", a)
+	}
+	return b.String()
+}
+
+// pageOf 用于缓存返回 (列表, 总数, error) 的方法.
+type pageOf[S any, N int | int64] struct {
+	items S
+	total N
+}
+
+func loadPage[S ~[]E, E any, N int | int64](ctx context.Context, b *cached, resource, key string, load func(ctx context.Context) (S, N, error)) (S, N, error) {
+	p, err := cache.Load(ctx, b.cache, b.cluster, resource, key, func(ctx context.Context) (pageOf[S, N], error) {
+		items, total, err := load(ctx)
+		return pageOf[S, N]{items: items, total: total}, err
+	})
+	return slices.Clone(p.items), p.total, err
+}
+
+func (b *cached) GetNodes(ctx context.Context, addr string, partitions []string, paging bool, page, pageSize int) (model.Nodes, int, error) {
+	key := cacheKey(addr, "list", strings.Join(partitions, ","), paging, page, pageSize)
+	return loadPage(ctx, b, CACHE_NODES, key, func(ctx context.Context) (model.Nodes, int, error) {
+		return b.Backend.GetNodes(ctx, addr, partitions, paging, page, pageSize)
+	})
+}
+
+// GetSchedulingJobs 缓存调度队列, 避免频繁查询(如列表页轮询)时每次都获取整个队列.
+func (b *cached) GetSchedulingJobs(ctx context.Context, addr string, filter model.SchedulingJobFilter, paging bool, page, pageSize int) (model.JobsInScheduling, int64, error) {
+	key := cacheKey(addr, "list", fmt.Sprintf("%q", filter), paging, page, pageSize)
+	return loadPage(ctx, b, CACHE_JOBS, key, func(ctx context.Context) (model.JobsInScheduling, int64, error) {
+		return b.Backend.GetSchedulingJobs(ctx, addr, filter, paging, page, pageSize)
+	})
+}
+
+func (b *cached) GetPartitions(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error) {
+	key := cacheKey(addr, "list", paging, page, pageSize)
+	return loadPage(ctx, b, CACHE_PARTITIONS, key, func(ctx context.Context) ([]map[string]string, int, error) {
+		return b.Backend.GetPartitions(ctx, addr, paging, page, pageSize)
+	})
+}
+
+func (b *cached) GetPartitionByName(ctx context.Context, addr string, name string) (map[string]string, error) {
+	p, err := cache.Load(ctx, b.cache, b.cluster, CACHE_PARTITIONS, cacheKey(addr, "name", name), func(ctx context.Context) (map[string]string, error) {
+		return b.Backend.GetPartitionByName(ctx, addr, name)
+	})
+	return maps.Clone(p), err
+}
+
+func (b *cached) GetQos(ctx context.Context, addr string, id uint32) (model.QoS, error) {
+	return cache.Load(ctx, b.cache, b.cluster, CACHE_QOS, cacheKey(addr, "id", id), func(ctx context.Context) (model.QoS, error) {
+		return b.Backend.GetQos(ctx, addr, id)
+	})
+}
+
+func (b *cached) GetQosAll(ctx context.Context, addr string, paging bool, page, pageSize int) (model.QoSes, int, error) {
+	key := cacheKey(addr, "list", paging, page, pageSize)
+	return loadPage(ctx, b, CACHE_QOS, key, func(ctx context.Context) (model.QoSes, int, error) {
+		return b.Backend.GetQosAll(ctx, addr, paging, page, pageSize)
+	})
+}
+
+func (b *cached) GetTres(ctx context.Context, addr string) (model.TresList, error) {
+	list, err := cache.Load(ctx, b.cache, b.cluster, CACHE_TRES, cacheKey(addr, "list"), func(ctx context.Context) (model.TresList, error) {
+		return b.Backend.GetTres(ctx, addr)
+	})
+	return slices.Clone(list), err
+}
+
+func (b *cached) GetAccounts(ctx context.Context, addr string, paging bool, page, pageSize int) (model.Accounts, int, error) {
+	key := cacheKey(addr, "list", paging, page, pageSize)
+	return loadPage(ctx, b, CACHE_ACCOUNTS, key, func(ctx context.Context) (model.Accounts, int, error) {
+		return b.Backend.GetAccounts(ctx, addr, paging, page, pageSize)
+	})
+}
+
+func (b *cached) GetAccountByName(ctx context.Context, addr, name string) (model.Account, error) {
+	return cache.Load(ctx, b.cache, b.cluster, CACHE_ACCOUNTS, cacheKey(addr, "name", name), func(ctx context.Context) (model.Account, error) {
+		return b.Backend.GetAccountByName(ctx, addr, name)
+	})
+}
+
+func (b *cached) GetChildNodesOfAccount(ctx context.Context, addr, name string) (AccountNode, error) {
+	return cache.Load(ctx, b.cache, b.cluster, CACHE_ACCOUNTS, cacheKey(addr, "children", name), func(ctx context.Context) (AccountNode, error) {
+		return b.Backend.GetChildNodesOfAccount(ctx, addr, name)
+	})
+}
+
+func (b *cached) GetAssociationChildNodesOfAccount(ctx context.Context, addr, name string) (AssociationNode, error) {
+	return cache.Load(ctx, b.cache, b.cluster, CACHE_ACCOUNTS, cacheKey(addr, "assoc_children", name), func(ctx context.Context) (AssociationNode, error) {
+		return b.Backend.GetAssociationChildNodesOfAccount(ctx, addr, name)
+	})
+}
+
+func (b *cached) GetAssociationDetail(ctx context.Context, addr, acct, user, partition string) (model.AssociationItem, error) {
+	key := cacheKey(addr, "assoc", acct, user, partition)
+	return cache.Load(ctx, b.cache, b.cluster, CACHE_ACCOUNTS, key, func(ctx context.Context) (model.AssociationItem, error) {
+		return b.Backend.GetAssociationDetail(ctx, addr, acct, user, partition)
+	})
+}
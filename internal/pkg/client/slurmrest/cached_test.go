@@ -0,0 +1,124 @@
+package slurmrest
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"csjk-bk/internal/pkg/cache"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+)
+
+// fakeBackend 仅实现被缓存的部分方法, 并记录调用次数.
+type fakeBackend struct {
+	Backend
+	calls atomic.Int64
+}
+
+func (f *fakeBackend) GetQosAll(ctx context.Context, addr string, paging bool, page, pageSize int) (model.QoSes, int, error) {
+	f.calls.Add(1)
+	return model.QoSes{{Name: "normal"}, {Name: "high"}, {Name: "low"}}, 3, nil
+}
+
+func (f *fakeBackend) GetTres(ctx context.Context, addr string) (model.TresList, error) {
+	f.calls.Add(1)
+	return model.TresList{{ID: 1, Type: "cpu"}, {ID: 2, Type: "mem"}}, nil
+}
+
+func (f *fakeBackend) GetPartitionByName(ctx context.Context, addr, name string) (map[string]string, error) {
+	f.calls.Add(1)
+	return map[string]string{"name": name}, nil
+}
+
+func (f *fakeBackend) GetSchedulingJobs(ctx context.Context, addr string, filter model.SchedulingJobFilter, paging bool, page, pageSize int) (model.JobsInScheduling, int64, error) {
+	f.calls.Add(1)
+	return model.JobsInScheduling{{User: "alice"}}, 1, nil
+}
+
+func newCached() (*cached, *fakeBackend) {
+	f := &fakeBackend{}
+	c := cache.New(func(string, string) time.Duration { return time.Minute }, nil)
+	return &cached{Backend: f, cache: c, cluster: "c1"}, f
+}
+
+func qosNames(items model.QoSes) []string {
+	names := make([]string, 0, len(items))
+	for _, q := range items {
+		names = append(names, q.Name)
+	}
+	return names
+}
+
+// TestCachedIsolatesCallers 调用方修改(排序, 过滤, 追加)返回的列表后, 之后的调用方仍得到原始的缓存值.
+func TestCachedIsolatesCallers(t *testing.T) {
+	ctx := context.Background()
+	b, f := newCached()
+
+	first, _, err := b.GetQosAll(ctx, "addr", false, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(first, func(i, j int) bool { return first[i].Name < first[j].Name })
+	first[0] = model.QoS{Name: "changed"}
+	_ = append(first[:1], model.QoS{Name: "appended"})
+
+	second, total, _ := b.GetQosAll(ctx, "addr", false, 0, 0)
+	if want := []string{"normal", "high", "low"}; !slices.Equal(qosNames(second), want) || total != 3 {
+		t.Errorf("GetQosAll after caller modification = %v, %d, want %v, 3", qosNames(second), total, want)
+	}
+
+	tres, _ := b.GetTres(ctx, "addr")
+	tres[0].Type = "changed"
+	if again, _ := b.GetTres(ctx, "addr"); again[0].Type != "cpu" {
+		t.Errorf("GetTres after caller modification = %v, want cpu first", again)
+	}
+
+	p, _ := b.GetPartitionByName(ctx, "addr", "debug")
+	p["name"] = "changed"
+	delete(p, "name")
+	if again, _ := b.GetPartitionByName(ctx, "addr", "debug"); again["name"] != "debug" {
+		t.Errorf("GetPartitionByName after caller modification = %v, want name=debug", again)
+	}
+
+	if got := f.calls.Load(); got != 3 {
+		t.Errorf("backend calls = %d, want 3 (one per method)", got)
+	}
+}
+
+func TestCachedInvalidate(t *testing.T) {
+	ctx := context.Background()
+	b, f := newCached()
+
+	b.GetQosAll(ctx, "addr", false, 0, 0)
+	b.GetTres(ctx, "addr")
+	b.cache.Invalidate("c1", CACHE_QOS)
+	b.GetQosAll(ctx, "addr", false, 0, 0)
+	b.GetTres(ctx, "addr")
+
+	if got := f.calls.Load(); got != 3 {
+		t.Errorf("backend calls = %d, want 3 (only qos reloaded)", got)
+	}
+}
+
+func TestCachedSchedulingJobs(t *testing.T) {
+	ctx := context.Background()
+	b, f := newCached()
+
+	all := model.SchedulingJobFilter{}
+	alice := model.SchedulingJobFilter{Users: []string{"alice"}}
+	b.GetSchedulingJobs(ctx, "addr", all, false, 0, 0)
+	b.GetSchedulingJobs(ctx, "addr", all, false, 0, 0)
+	b.GetSchedulingJobs(ctx, "addr", alice, false, 0, 0)
+	if got := f.calls.Load(); got != 2 {
+		t.Errorf("backend calls = %d, want 2 (one per filter)", got)
+	}
+
+	b.cache.Invalidate("c1", CACHE_JOBS)
+	b.GetSchedulingJobs(ctx, "addr", all, false, 0, 0)
+	if got := f.calls.Load(); got != 3 {
+		t.Errorf("backend calls after Invalidate = %d, want 3", got)
+	}
+}
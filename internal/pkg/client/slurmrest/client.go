@@ -3,9 +3,11 @@ package slurmrest
 import (
 	"bytes"
 	"context"
+	"csjk-bk/internal/pkg/apperror"
 	"csjk-bk/internal/pkg/client/slurmrest/model"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -18,6 +20,22 @@ type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// statusError 将 slurmrestd 的非 2xx 响应转换为带错误码的错误(见 apperror.Upstream), 错误描述取自响应体中的 detail 字段.
+func statusError(resp *http.Response) error {
+	var body struct {
+		Detail string `json:"detail"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&body)
+	return apperror.Upstream("slurmrestd", resp.StatusCode, body.Detail)
+}
+
+// result slurmrest 服务的响应体(response.Response), Results 为具体数据, Count 为分页查询时的总数.
+type result[T any] struct {
+	Count   int    `json:"count"`
+	Results T      `json:"results"`
+	Detail  string `json:"detail"`
+}
+
 // SlurmrestClient 简单的 slurmrestd HTTP 客户端封装。
 // 仅保留最小必要字段，侧重可测试性与可注入性。
 type Client struct {
@@ -34,15 +52,72 @@ func New(client Doer, timeout time.Duration, logger *slog.Logger) *Client {
 	}
 }
 
+// get 以 GET 请求 urlStr, 并将响应体解码到 out.
+func (c *Client) get(ctx context.Context, urlStr string, out any) error {
+	return c.do(ctx, http.MethodGet, urlStr, nil, out)
+}
+
+// do 以 method 请求 urlStr, body 不为空时编码为 JSON 请求体, out 不为空时将响应体解码到 out.
+// 非 2xx 响应由 statusError 转换为错误.
+func (c *Client) do(ctx context.Context, method, urlStr string, body, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.send(ctx, method, urlStr, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
+		return statusError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
+		return fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	}
+	return nil
+}
+
+// send 构造并发送请求, body 不为空时编码为 JSON 请求体. 不检查响应状态码, 由调用方关闭响应体.
+func (c *Client) send(ctx context.Context, method, urlStr string, body any) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			c.logger.Error("unable to marshal request payload", "err", err.Error(), "url", urlStr)
+			return nil, fmt.Errorf("unable to marshal request payload: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reader)
+	if err != nil {
+		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
+		return nil, fmt.Errorf("unable to create request for slurmrestd: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to do request for slurmrestd(%s): %w", urlStr, err)
+	}
+	return resp, nil
+}
+
 // GetNodes 获取节点信息
 // 支持过滤参数：重复传递 partition/state 以及单个 node
 //   - partition: ?partition=p1&partition=p2
 //   - state: ?state=idle&state=alloc
 //   - node: ?node=cn001
 func (sc *Client) GetNodes(ctx context.Context, addr string, partitions []string, paging bool, page, page_size int) (model.Nodes, int, error) {
-	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/node/all", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
@@ -55,266 +130,174 @@ func (sc *Client) GetNodes(ctx context.Context, addr string, partitions []string
 
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		sc.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := sc.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		sc.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Count   int         `json:"count"`
-		Results model.Nodes `json:"results"`
-		Detail  string      `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		sc.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	var data result[model.Nodes]
+	if err := sc.get(ctx, u.String(), &data); err != nil {
+		return nil, 0, err
 	}
-
 	return data.Results, data.Count, nil
 }
 
-// GetSchedulingJobs 获取调度作业中作业信息
-func (sc *Client) GetSchedulingJobs(ctx context.Context, addr string, paging bool, page, pageSize int) (model.JobsInScheduling, int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
-	defer cancel()
-
-	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/job/all?paging=%tpage=%d&page_page_size=%d", addr, paging, page, pageSize)
-	sc.logger.Debug(urlStr)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		sc.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := sc.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
+// GetSchedulingJobs 获取调度作业中作业信息, 查询条件(filter)及排序由 slurmrest 服务处理.
+func (sc *Client) GetSchedulingJobs(ctx context.Context, addr string, filter model.SchedulingJobFilter, paging bool, page, pageSize int) (model.JobsInScheduling, int64, error) {
+	// http://addr/api/v1/slurm/scheduling/job/all?paging=xxx&page=xxx&page_size=xxx&user=xxx&state=xxx&reason=xxx&sort=xxx...
+	// 列表条件以逗号分隔
+	base := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/job/all", addr)
+	u, _ := url.Parse(base)
+	q := u.Query()
+	q.Set("paging", fmt.Sprintf("%t", paging))
+	q.Set("page", fmt.Sprint(page))
+	q.Set("page_size", fmt.Sprint(pageSize))
+	for key, list := range map[string][]string{"user": filter.Users, "account": filter.Accounts, "partition": filter.Partitions, "qos": filter.QoS, "state": filter.States, "reason": filter.Reasons, "node": filter.Nodes} {
+		if len(list) != 0 {
+			q.Set(key, strings.Join(list, ","))
+		}
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		sc.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
+	if filter.Sort != "" {
+		q.Set("sort", filter.Sort)
 	}
+	u.RawQuery = q.Encode()
 
-	data := struct {
-		Count   int64                  `json:"count"`
-		Results model.JobsInScheduling `json:"results"`
-		Detail  string                 `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		sc.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	urlStr := u.String()
+	sc.logger.Debug(urlStr)
+	var data result[model.JobsInScheduling]
+	if err := sc.get(ctx, urlStr, &data); err != nil {
+		return nil, 0, err
 	}
-	return data.Results, data.Count, nil
+	return data.Results, int64(data.Count), nil
 }
 
 func (c *Client) GetJobStepsOfScheduling(ctx context.Context, addr, jobid string) (model.JobsStepsInScheduling, error) {
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/job/steps?jobid=%s", addr, jobid)
 	c.logger.Debug(urlStr)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return nil, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
+	var data result[model.JobsStepsInScheduling]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return data.Results, nil
+}
 
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return nil, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
+// GetSchedulingJob 获取调度系统中的单个作业, jobid 可为作业数组任务(如 1234_5).
+func (c *Client) GetSchedulingJob(ctx context.Context, addr, jobid string) (model.JobInScheduling, error) {
+	// GET http://<addr>/api/v1/slurm/scheduling/job/:jobid
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/job/%s", addr, url.PathEscape(jobid))
+	c.logger.Debug(urlStr)
+	var data result[model.JobInScheduling]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return model.JobInScheduling{}, err
 	}
+	return data.Results, nil
+}
 
-	data := struct {
-		Results model.JobsStepsInScheduling `json:"results"`
-		Detail  string                      `json:"detail"`
-	}{}
+// ControlJob 对作业执行控制操作(action). jobid 可为作业(1234), 作业数组任务(1234_5, 1234_[1-3])
+// 或作业步(1234.0, 仅 cancel/signal); signal 仅用于 JOB_ACTION_SIGNAL 及 JOB_ACTION_CANCEL(可为空).
+func (c *Client) ControlJob(ctx context.Context, addr, jobid string, action JobAction, signal string) error {
+	// POST http://<addr>/api/v1/slurm/scheduling/job/:jobid/:action
+	// body = {"signal": "USR1"}
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/job/%s/%s", addr, url.PathEscape(jobid), action)
+	return c.do(ctx, http.MethodPost, urlStr, map[string]string{"signal": signal}, nil)
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return nil, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+// SubmitJob 提交批处理作业, validateOnly 为 true 时仅校验(sbatch --test-only), 不实际提交.
+func (c *Client) SubmitJob(ctx context.Context, addr string, job model.JobSubmission, validateOnly bool) (model.JobSubmitResult, error) {
+	// POST http://<addr>/api/v1/slurm/scheduling/job?validate_only=xxx
+	// body = job
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/job?validate_only=%t", addr, validateOnly)
+	var data result[model.JobSubmitResult]
+	if err := c.do(ctx, http.MethodPost, urlStr, job, &data); err != nil {
+		return model.JobSubmitResult{}, err
 	}
 	return data.Results, nil
 }
 
-// GetSchedulingJobs 获取账户中作业信息
-func (sc *Client) GetAccountingJobs(ctx context.Context, addr string, paging bool, page, pageSize int64) (model.Jobs, int64, error) {
-	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
-	defer cancel()
+// UpdateNodeState 设置节点(nodes)状态, reason 为原因(scontrol update reason=...).
+func (c *Client) UpdateNodeState(ctx context.Context, addr string, nodes []string, state NodeState, reason string) error {
+	// PUT http://<addr>/api/v1/slurm/node/state
+	// body = {"nodes": ["cn001"], "state": "DRAIN", "reason": "xxx"}
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/node/state", addr)
+	return c.do(ctx, http.MethodPut, urlStr, map[string]any{"nodes": nodes, "state": state, "reason": reason}, nil)
+}
 
-	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/job/all?paging=%t&page=%d&page_page_size=%d", addr, paging, page, pageSize)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		sc.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
+// GetReservations 获取全部资源预约, 支持分页.
+func (c *Client) GetReservations(ctx context.Context, addr string, paging bool, page, pageSize int) (model.Reservations, int, error) {
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/reservation/all?paging=%t&page=%d&page_size=%d", addr, paging, page, pageSize)
+	var data result[model.Reservations]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return nil, 0, err
 	}
+	return data.Results, data.Count, nil
+}
 
-	resp, err := sc.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
+// CreateReservation 创建资源预约(scontrol create reservation), 返回预约名称. resv.Name 为空时由 Slurm 生成.
+// Slurm 拒绝时(节点不可用, 参数错误等)返回的错误描述为 Slurm 的错误信息.
+func (c *Client) CreateReservation(ctx context.Context, addr string, resv model.Reservation) (string, error) {
+	// POST http://<addr>/api/v1/slurm/scheduling/reservation
+	// body = resv
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/reservation", addr)
+	var data result[struct {
+		Name string `json:"name"`
+	}]
+	if err := c.do(ctx, http.MethodPost, urlStr, resv, &data); err != nil {
+		return "", err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		sc.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
+	if data.Results.Name == "" {
+		return resv.Name, nil
 	}
+	return data.Results.Name, nil
+}
 
-	data := struct {
-		Count   int64      `json:"count"`
-		Results model.Jobs `json:"results"`
-		Detail  string     `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		sc.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
+// UpdateReservation 更新资源预约(scontrol update reservation), resv.Name 为预约名称, 其余字段为空时不修改.
+func (c *Client) UpdateReservation(ctx context.Context, addr string, resv model.Reservation) error {
+	// PUT http://<addr>/api/v1/slurm/scheduling/reservation/:name
+	// body = resv
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/reservation/%s", addr, url.PathEscape(resv.Name))
+	return c.do(ctx, http.MethodPut, urlStr, resv, nil)
+}
 
-	return data.Results, data.Count, nil
+// DeleteReservation 删除资源预约(scontrol delete reservation).
+func (c *Client) DeleteReservation(ctx context.Context, addr, name string) error {
+	// DELETE http://<addr>/api/v1/slurm/scheduling/reservation/:name
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/reservation/%s", addr, url.PathEscape(name))
+	return c.do(ctx, http.MethodDelete, urlStr, nil, nil)
 }
 
 // GetPartitions 获取全部分区, 支持分页
 func (sc *Client) GetPartitions(ctx context.Context, addr string, paging bool, page, page_size int) ([]map[string]string, int, error) {
-	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
-	defer cancel()
-
 	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/partition/all?paging=%t&page=%d&page_size=%d", addr, paging, page, page_size)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		sc.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := sc.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		sc.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
+	var data result[[]map[string]string]
+	if err := sc.get(ctx, urlStr, &data); err != nil {
+		return nil, 0, err
 	}
-
-	data := struct {
-		Count   int                 `json:"count"`
-		Results []map[string]string `json:"results"`
-		Detail  string              `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		sc.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
-
 	return data.Results, data.Count, nil
 }
 
 func (sc *Client) GetPartitionByName(ctx context.Context, addr string, name string) (map[string]string, error) {
-	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
-	defer cancel()
-
 	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/partition?name=%s", addr, name)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		sc.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return nil, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := sc.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		sc.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return nil, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results map[string]string `json:"results"`
-		Detail  string            `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		sc.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return nil, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	var data result[map[string]string]
+	if err := sc.get(ctx, urlStr, &data); err != nil {
+		return nil, err
 	}
-
 	return data.Results, nil
 }
 
 // GetQos 获取某个 QoS 详情.
 func (c *Client) GetQos(ctx context.Context, addr string, id uint32) (model.QoS, error) {
 	// http://addr/api/v1/slurm/accounting/qos?id=xxx 返回类型为 response.Response, 其中 results 的类型就为 model.QoS
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/accounting/qos", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
 	q.Set("id", fmt.Sprint(id))
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return model.QoS{}, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return model.QoS{}, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return model.QoS{}, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results model.QoS `json:"results"`
-		Detail  string    `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return model.QoS{}, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	var data result[model.QoS]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return model.QoS{}, err
 	}
-
 	return data.Results, nil
 }
 
 // GetQoSAll 获取全部 QoS 信息, 支持分页.
 func (c *Client) GetQosAll(ctx context.Context, addr string, paging bool, page, pageSize int) (model.QoSes, int, error) {
 	// http://addr/api/v1/slurm/accounting/qos/all?paging=xxx&page=xxx&page_size=xxx 返回类型为 response.Response, 其中 results 的类型就为 model.QoSes
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/accounting/qos/all", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
@@ -327,43 +310,47 @@ func (c *Client) GetQosAll(ctx context.Context, addr string, paging bool, page,
 	}
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
+	var data result[model.QoSes]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return nil, 0, err
 	}
+	return data.Results, data.Count, nil
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
-	}
-	defer resp.Body.Close()
+// CreateQoS 创建 QoS(sacctmgr add qos).
+func (c *Client) CreateQoS(ctx context.Context, addr string, qos model.QoSWrite) error {
+	// POST http://<addr>/api/v1/slurm/accounting/qos
+	// body = qos
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("http://%s/api/v1/slurm/accounting/qos", addr), qos, nil)
+}
 
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
+// UpdateQoS 修改 QoS(sacctmgr modify qos), qos.Name 为 QoS 名称, 未设置的字段不修改.
+func (c *Client) UpdateQoS(ctx context.Context, addr string, qos model.QoSWrite) error {
+	// PUT http://<addr>/api/v1/slurm/accounting/qos/:name
+	// body = qos
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("http://%s/api/v1/slurm/accounting/qos/%s", addr, url.PathEscape(qos.Name)), qos, nil)
+}
 
-	data := struct {
-		Count   int         `json:"count"`
-		Results model.QoSes `json:"results"`
-		Detail  string      `json:"detail"`
-	}{}
+// DeleteQoS 删除 QoS(sacctmgr delete qos).
+func (c *Client) DeleteQoS(ctx context.Context, addr, name string) error {
+	// DELETE http://<addr>/api/v1/slurm/accounting/qos/:name
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("http://%s/api/v1/slurm/accounting/qos/%s", addr, url.PathEscape(name)), nil, nil)
+}
 
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+// GetTres 获取集群的 TRES 类型列表(sacctmgr show tres), 用于解析 ID 格式的 TRES 字符串.
+func (c *Client) GetTres(ctx context.Context, addr string) (model.TresList, error) {
+	// http://addr/api/v1/slurm/accounting/tres/all 返回类型为 response.Response, 其中 results 的类型就为 model.TresList
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/tres/all", addr)
+	var data result[model.TresList]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return nil, err
 	}
-
-	return data.Results, data.Count, nil
+	return data.Results, nil
 }
 
 // GetAccounts 获取全部账户信息, 支持分页.
 func (c *Client) GetAccounts(ctx context.Context, add string, paging bool, page, pageSize int) (model.Accounts, int, error) {
 	// http://addr/api/v1/slurm/accounting/account/all?paging=xxx&page=xxx&page_size=xxx
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/accounting/account/all", add)
 	u, _ := url.Parse(base)
 	q := u.Query()
@@ -376,34 +363,10 @@ func (c *Client) GetAccounts(ctx context.Context, add string, paging bool, page,
 	}
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Count   int            `json:"count"`
-		Results model.Accounts `json:"results"`
-		Detail  string         `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	var data result[model.Accounts]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return nil, 0, err
 	}
-
 	return data.Results, data.Count, nil
 }
 
@@ -411,41 +374,25 @@ func (c *Client) GetAccounts(ctx context.Context, add string, paging bool, page,
 func (c *Client) GetAccountByName(ctx context.Context, addr, name string) (model.Account, error) {
 	// http://addr/api/v1/slurm/accounting/account/:name
 	// 响应体 response.Response, results 对应 model.Account
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	// Ensure account name is safely embedded in path
-	safeName := url.PathEscape(name)
-	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/account/%s", addr, safeName)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return model.Account{}, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return model.Account{}, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return model.Account{}, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/account/%s", addr, url.PathEscape(name))
+	var data result[model.Account]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return model.Account{}, err
 	}
+	return data.Results, nil
+}
 
-	data := struct {
-		Results model.Account `json:"results"`
-		Detail  string        `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return model.Account{}, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
+// CreateAccount 创建账户(sacctmgr add account), 并在父账户(acct.Parent, 为空时为 root)下创建账户的关联.
+func (c *Client) CreateAccount(ctx context.Context, addr string, acct model.AccountWrite) error {
+	// POST http://<addr>/api/v1/slurm/accounting/account
+	// body = acct
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("http://%s/api/v1/slurm/accounting/account", addr), acct, nil)
+}
 
-	return data.Results, nil
+// DeleteAccount 删除账户(sacctmgr delete account).
+func (c *Client) DeleteAccount(ctx context.Context, addr, name string) error {
+	// DELETE http://<addr>/api/v1/slurm/accounting/account/:name
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("http://%s/api/v1/slurm/accounting/account/%s", addr, url.PathEscape(name)), nil, nil)
 }
 
 // GetUserByName
@@ -469,39 +416,11 @@ type UserNode struct {
 func (c *Client) GetChildNodesOfAccount(ctx context.Context, addr, name string) (AccountNode, error) {
 	// http://addr/api/v1/slurm/accounting/account/:name/childnodes
 	// 返回类型为 response.Response, results 对应类型为 AccountNode
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	safe := url.PathEscape(name)
-	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/account/%s/childnodes", addr, safe)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return AccountNode{}, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/account/%s/childnodes", addr, url.PathEscape(name))
+	var data result[AccountNode]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return AccountNode{}, err
 	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return AccountNode{}, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return AccountNode{}, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results AccountNode `json:"results"`
-		Detail  string      `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return AccountNode{}, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
-
 	return data.Results, nil
 }
 
@@ -520,39 +439,11 @@ type AssociationUserNode struct {
 func (c *Client) GetAssociationChildNodesOfAccount(ctx context.Context, addr, name string) (AssociationNode, error) {
 	// http://addr/api/v1/slurm/accounting/association/:account/childnodes
 	// 返回类型为 response.Response, results 对应类型为 AssociationNode
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	safe := url.PathEscape(name)
-	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/association/%s/childnodes", addr, safe)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return AssociationNode{}, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return AssociationNode{}, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return AssociationNode{}, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results AssociationNode `json:"results"`
-		Detail  string          `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return AssociationNode{}, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/accounting/association/%s/childnodes", addr, url.PathEscape(name))
+	var data result[AssociationNode]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return AssociationNode{}, err
 	}
-
 	return data.Results, nil
 }
 
@@ -560,9 +451,6 @@ func (c *Client) GetAssociationChildNodesOfAccount(ctx context.Context, addr, na
 func (c *Client) GetAssociationDetail(ctx context.Context, addr, acct, user, partition string) (model.AssociationItem, error) {
 	// http://addr/api/v1/slurm/accounting/association/detail?account=xxx&user=xxx&partition=xxx
 	// 返回类型为 response.Response, results 对应类型为 model.AssociationItem
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/accounting/association/detail", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
@@ -577,43 +465,40 @@ func (c *Client) GetAssociationDetail(ctx context.Context, addr, acct, user, par
 	}
 	u.RawQuery = q.Encode()
 
-	urlStr := u.String()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return model.AssociationItem{}, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
+	var data result[model.AssociationItem]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return model.AssociationItem{}, err
 	}
+	return data.Results, nil
+}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return model.AssociationItem{}, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
+// AddUserAssociation 将用户加入账户(sacctmgr add user <user> account=<account> [partition=<partition>]), 用户不存在时一并创建.
+func (c *Client) AddUserAssociation(ctx context.Context, addr string, ua model.UserAssociation) error {
+	// POST http://<addr>/api/v1/slurm/accounting/association
+	// body = ua
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("http://%s/api/v1/slurm/accounting/association", addr), ua, nil)
+}
 
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return model.AssociationItem{}, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results model.AssociationItem `json:"results"`
-		Detail  string                `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return model.AssociationItem{}, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
+// UpdateAssociation 修改关联限制, 未设置的字段不修改.
+func (c *Client) UpdateAssociation(ctx context.Context, addr string, assoc model.AssociationWrite) error {
+	// PUT http://<addr>/api/v1/slurm/accounting/association
+	// body = assoc
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("http://%s/api/v1/slurm/accounting/association", addr), assoc, nil)
+}
 
-	return data.Results, nil
+// SetDefaultAccount 设置用户的默认账户(sacctmgr modify user <user> set defaultaccount=<account>).
+func (c *Client) SetDefaultAccount(ctx context.Context, addr, user, account string) error {
+	// PUT http://<addr>/api/v1/slurm/accounting/user/:name/default_account
+	// body = {"account": account}
+	body := map[string]string{"account": account}
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("http://%s/api/v1/slurm/accounting/user/%s/default_account", addr, url.PathEscape(user)), body, nil)
 }
 
-func (c *Client) GetJobsFromAccounting(ctx context.Context, addr string, paging bool, page, page_size int) (model.Jobs, int, error) {
-	// http://addr/api/v1/slurm/accounting/job/all?paging=xxx&page=xxx&page_size=xxx
+// GetJobsFromAccounting 获取账户中作业信息, 查询条件(filter)及排序由 slurmrest 服务处理.
+func (c *Client) GetJobsFromAccounting(ctx context.Context, addr string, filter model.JobFilter, paging bool, page, page_size int) (model.Jobs, int, error) {
+	// http://addr/api/v1/slurm/accounting/job/all?paging=xxx&page=xxx&page_size=xxx&user=xxx&state=xxx&submit_from=xxx&sort=xxx...
+	// 列表条件以逗号分隔, 时间为 Unix 时间戳
 	// 返回类型为 response.Response, results 对应类型为 model.Jobs
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/accounting/job/all", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
@@ -624,122 +509,63 @@ func (c *Client) GetJobsFromAccounting(ctx context.Context, addr string, paging
 	if page_size > 0 {
 		q.Set("page_size", fmt.Sprint(page_size))
 	}
-	u.RawQuery = q.Encode()
-
-	urlStr := u.String()
-	fmt.Println(urlStr)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
+	for key, list := range map[string][]string{"user": filter.Users, "account": filter.Accounts, "partition": filter.Partitions, "qos": filter.QoS, "state": filter.States, "node": filter.Nodes} {
+		if len(list) != 0 {
+			q.Set(key, strings.Join(list, ","))
+		}
 	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
+	for key, v := range map[string]int64{"submit_from": filter.SubmitFrom, "submit_to": filter.SubmitTo, "start_from": filter.StartFrom, "start_to": filter.StartTo, "end_from": filter.EndFrom, "end_to": filter.EndTo} {
+		if v > 0 {
+			q.Set(key, fmt.Sprint(v))
+		}
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
+	if filter.Name != "" {
+		q.Set("name", filter.Name)
 	}
-
-	data := struct {
-		Count   int        `json:"count"`
-		Results model.Jobs `json:"results"`
-		Detail  string     `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	if filter.ExitCode != nil {
+		q.Set("exit_code", fmt.Sprint(*filter.ExitCode))
+	}
+	if filter.Sort != "" {
+		q.Set("sort", filter.Sort)
 	}
+	u.RawQuery = q.Encode()
 
+	var data result[model.Jobs]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return nil, 0, err
+	}
 	return data.Results, data.Count, nil
 }
 
 func (c *Client) GetJobFromAccounting(ctx context.Context, addr string, jobid uint32) (model.Job, error) {
 	// http://addr/api/v1/slurm/accouting/job?jobid=xxx
 	// 返回类型为 response.Response, results 对应类型为 model.Job
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/accounting/job", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
 	q.Set("jobid", fmt.Sprint(jobid))
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return model.Job{}, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return model.Job{}, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
+	var data result[model.Job]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return model.Job{}, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return model.Job{}, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results model.Job `json:"results"`
-		Detail  string    `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return model.Job{}, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
-
 	return data.Results, nil
 }
 
 func (c *Client) GetStepsOfJobFromAccounting(ctx context.Context, addr string, jobid uint32) (model.Steps, error) {
 	// http://addr/api/v1/slurm/accounting/job/steps?jobid=xxx
 	// 返回类型为 response.Response, results 对应类型为 model.Steps
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/slurm/accounting/job/steps", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
 	q.Set("jobid", fmt.Sprint(jobid))
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return nil, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
+	var data result[model.Steps]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return nil, err
 	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return nil, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results model.Steps `json:"results"`
-		Detail  string      `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return nil, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
-
 	return data.Results, nil
 }
 
@@ -747,9 +573,6 @@ func (c *Client) GetStepsOfJobFromAccounting(ctx context.Context, addr string, j
 func (c *Client) GetLdapUsers(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error) {
 	// http://addr/api/v1/ldap/users?paging=xxx&page=xxx&page_size=xxx
 	// 返回类型为 response.Response, results 对应类型为 []map[string]string
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/ldap/users", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
@@ -762,34 +585,10 @@ func (c *Client) GetLdapUsers(ctx context.Context, addr string, paging bool, pag
 	}
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
+	var data result[[]map[string]string]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return nil, 0, err
 	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Count   int                 `json:"count"`
-		Results []map[string]string `json:"results"`
-		Detail  string              `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
-	}
-
 	return data.Results, data.Count, nil
 }
 
@@ -797,304 +596,88 @@ func (c *Client) GetLdapUsers(ctx context.Context, addr string, paging bool, pag
 func (c *Client) GetAdditionalGroupsOfUser(ctx context.Context, addr string, name string) ([]string, error) {
 	// http://addr/api/v1/ldap/user/:name/groups
 	// 返回类型为 response.Response, results 对应类型为 []string
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
+	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/user/%s/groups", addr, url.PathEscape(name))
+	var data result[[]string]
+	if err := c.get(ctx, urlStr, &data); err != nil {
+		return nil, err
+	}
+	return data.Results, nil
+}
 
-	safe := url.PathEscape(name)
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/user/%s/groups", addr, safe)
+// ErrInvalidCredentials Ldap 用户名或密码错误.
+var ErrInvalidCredentials = apperror.New(apperror.CODE_UNAUTHENTICATED, "invalid ldap credentials")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return nil, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
+// AuthLdapUser 通过 ldap bind 校验用户密码, 用户名或密码错误时返回 ErrInvalidCredentials.
+func (c *Client) AuthLdapUser(ctx context.Context, addr, name, password string) error {
+	// POST http://addr/api/v1/ldap/user/:name/auth 请求体 {"password": "xxx"}
+	// 2xx 表示校验通过, 401/403 表示用户名或密码错误, 因此不经过 do 的状态码检查.
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
 
-	resp, err := c.client.Do(req)
+	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/user/%s/auth", addr, url.PathEscape(name))
+	resp, err := c.send(ctx, http.MethodPost, urlStr, map[string]string{"password": password})
 	if err != nil {
-		return nil, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
+		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode/100 != 2 {
+	switch {
+	case resp.StatusCode/100 == 2:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrInvalidCredentials
+	default:
 		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return nil, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Results []string `json:"results"`
-		Detail  string   `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return nil, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+		return statusError(resp)
 	}
-
-	return data.Results, nil
 }
 
 // AddUser 创建 Ldap 用户.
 func (c *Client) AddUser(ctx context.Context, addr string, user map[string]string) error {
 	// Post http://<addr>/api/v1/ldap/user 请求体直接使用 user(map[string]string)
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/user", addr)
-
-	payload, err := json.Marshal(user)
-	if err != nil {
-		c.logger.Error("unable to marshal ldap user payload", "err", err.Error())
-		return fmt.Errorf("unable to marshal ldap user payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlStr, bytes.NewReader(payload))
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	data := struct {
-		Results model.AssociationItem `json:"results"`
-		Detail  string                `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to decode response: %w", err)
-	}
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr, "detail", data.Detail)
-		return fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("http://%s/api/v1/ldap/user", addr), user, nil)
 }
 
 func (c *Client) UpdateLdapUser(ctx context.Context, addr, user string, attr map[string]string) error {
 	// PUT http:/<addr>/api/v1/ldap/user/:user
 	// body = attr
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	safe := url.PathEscape(user)
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/user/%s", addr, safe)
-
-	payload, err := json.Marshal(attr)
-	if err != nil {
-		c.logger.Error("unable to marshal ldap user update payload", "err", err.Error())
-		return fmt.Errorf("unable to marshal ldap user update payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, urlStr, bytes.NewReader(payload))
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	data := struct {
-		Detail string `json:"detail"`
-	}{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to decode response: %w", err)
-	}
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr, "detail", data.Detail)
-		return fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("http://%s/api/v1/ldap/user/%s", addr, url.PathEscape(user)), attr, nil)
 }
 
 func (c *Client) AddMemberForGroups(ctx context.Context, addr string, user string, groups []string) error {
 	// http://<addr>/api/v1/ldap/user/:user/groups post
 	// body : {"groups": ["g1", "g2", ...]}
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	safe := url.PathEscape(user)
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/user/%s/groups", addr, safe)
-
 	payload := struct {
 		Groups []string `json:"groups"`
 	}{Groups: groups}
-
-	b, err := json.Marshal(payload)
-	if err != nil {
-		c.logger.Error("unable to marshal groups payload", "err", err.Error())
-		return fmt.Errorf("unable to marshal groups payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlStr, bytes.NewReader(b))
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("http://%s/api/v1/ldap/user/%s/groups", addr, url.PathEscape(user)), payload, nil)
 }
 
 func (c *Client) DelLdapUser(ctx context.Context, addr, user string) error {
 	// DELETE http://<addr>/api/v1/ldap/user/:user
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	safe := url.PathEscape(user)
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/user/%s", addr, safe)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("http://%s/api/v1/ldap/user/%s", addr, url.PathEscape(user)), nil, nil)
 }
 
 func (c *Client) AddGroup(ctx context.Context, addr string, group map[string]string) error {
 	// Post http://<addr>/api/v1/ldap/group
 	// body = 参数 group
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/group", addr)
-
-	payload, err := json.Marshal(group)
-	if err != nil {
-		c.logger.Error("unable to marshal ldap group payload", "err", err.Error())
-		return fmt.Errorf("unable to marshal ldap group payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, urlStr, bytes.NewReader(payload))
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("http://%s/api/v1/ldap/group", addr), group, nil)
 }
 
 func (c *Client) DelLdapGroup(ctx context.Context, addr, group string) error {
 	// DELETE http://<addr>/api/v1/ldap/group/:group
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	safe := url.PathEscape(group)
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/group/%s", addr, safe)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, urlStr, nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("http://%s/api/v1/ldap/group/%s", addr, url.PathEscape(group)), nil, nil)
 }
 
 func (c *Client) UpdateLdapGroup(ctx context.Context, addr, group string, attr map[string]string) error {
 	// PUT http://<addr>/api/v1/ldap/group/:group
 	// body = attr
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
-	safe := url.PathEscape(group)
-	urlStr := fmt.Sprintf("http://%s/api/v1/ldap/group/%s", addr, safe)
-
-	payload, err := json.Marshal(attr)
-	if err != nil {
-		c.logger.Error("unable to marshal ldap group update payload", "err", err.Error())
-		return fmt.Errorf("unable to marshal ldap group update payload: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, urlStr, bytes.NewReader(payload))
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
-		return fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", urlStr)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
-		return fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	return nil
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("http://%s/api/v1/ldap/group/%s", addr, url.PathEscape(group)), attr, nil)
 }
 
 func (c *Client) GetLdapGroups(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error) {
 	// GET http://<addr>/api/v1/ldap/groups?paging=xxx&page=xxx&page_size=xxx
 	// 返回类型为 response.Response, results 对应类型为 []map[string]string
-	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	defer cancel()
-
 	base := fmt.Sprintf("http://%s/api/v1/ldap/groups", addr)
 	u, _ := url.Parse(base)
 	q := u.Query()
@@ -1107,33 +690,15 @@ func (c *Client) GetLdapGroups(ctx context.Context, addr string, paging bool, pa
 	}
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-	if err != nil {
-		c.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to create rrequest for slurmrestd: %w", err)
-	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, 0, fmt.Errorf("unable to do request for slurmrestd", "err", err.Error(), "url", u.String())
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode/100 != 2 {
-		c.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", u.String())
-		return nil, 0, fmt.Errorf("unexcepted status code: %d", resp.StatusCode)
-	}
-
-	data := struct {
-		Count   int                 `json:"count"`
-		Results []map[string]string `json:"results"`
-		Detail  string              `json:"detail"`
-	}{}
-
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		c.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", u.String())
-		return nil, 0, fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	var data result[[]map[string]string]
+	if err := c.get(ctx, u.String(), &data); err != nil {
+		return nil, 0, err
 	}
-
 	return data.Results, data.Count, nil
 }
+
+// Ping 探测 slurmrestd 是否可用, 以查询单个分区的方式确认服务及其与 slurmctld 的连接正常.
+func (sc *Client) Ping(ctx context.Context, addr string) error {
+	urlStr := fmt.Sprintf("http://%s/api/v1/slurm/scheduling/partition/all?paging=true&page=1&page_size=1", addr)
+	return sc.get(ctx, urlStr, nil)
+}
@@ -0,0 +1,101 @@
+package slurmrest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, string) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return New(srv.Client(), time.Second, logger), strings.TrimPrefix(srv.URL, "http://")
+}
+
+func TestClientDecodesResults(t *testing.T) {
+	c, addr := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/ldap/groups" || r.URL.Query().Get("page") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"count": 3, "results": [{"cn": "g1"}], "detail": ""}`))
+	})
+
+	groups, count, err := c.GetLdapGroups(context.Background(), addr, true, 2, 1)
+	if err != nil {
+		t.Fatalf("GetLdapGroups error: %v", err)
+	}
+	if count != 3 || len(groups) != 1 || groups[0]["cn"] != "g1" {
+		t.Errorf("GetLdapGroups = %v, %d, want [{cn: g1}], 3", groups, count)
+	}
+}
+
+func TestClientSendsJSONBody(t *testing.T) {
+	c, addr := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		var resv model.Reservation
+		if err := json.NewDecoder(r.Body).Decode(&resv); err != nil || resv.Name != "maint" {
+			t.Errorf("unexpected body %+v, %v", resv, err)
+		}
+		w.Write([]byte(`{"results": {"name": ""}}`))
+	})
+
+	name, err := c.CreateReservation(context.Background(), addr, model.Reservation{Name: "maint"})
+	if err != nil || name != "maint" {
+		t.Errorf("CreateReservation = %q, %v, want maint", name, err)
+	}
+}
+
+func TestClientStatusError(t *testing.T) {
+	c, addr := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"detail": "account not found"}`))
+	})
+
+	_, err := c.GetAccountByName(context.Background(), addr, "nobody")
+	if code := apperror.CodeOf(err); code != apperror.CODE_NOT_FOUND {
+		t.Errorf("error code = %s, want %s", code, apperror.CODE_NOT_FOUND)
+	}
+	if err == nil || !strings.Contains(err.Error(), "account not found") {
+		t.Errorf("error = %v, want detail from response", err)
+	}
+}
+
+func TestAuthLdapUser(t *testing.T) {
+	tests := []struct {
+		status int
+		want   apperror.Code
+	}{
+		{http.StatusOK, ""},
+		{http.StatusUnauthorized, apperror.CODE_UNAUTHENTICATED},
+		{http.StatusForbidden, apperror.CODE_UNAUTHENTICATED},
+		{http.StatusInternalServerError, apperror.CODE_UPSTREAM_ERROR},
+	}
+	for _, tt := range tests {
+		c, addr := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		})
+		err := c.AuthLdapUser(context.Background(), addr, "alice", "secret")
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("status %d: error = %v, want nil", tt.status, err)
+		case tt.want == apperror.CODE_UNAUTHENTICATED && !errors.Is(err, ErrInvalidCredentials):
+			t.Errorf("status %d: error = %v, want ErrInvalidCredentials", tt.status, err)
+		case tt.want != "" && apperror.CodeOf(err) != tt.want:
+			t.Errorf("status %d: error code = %s, want %s", tt.status, apperror.CodeOf(err), tt.want)
+		}
+	}
+}
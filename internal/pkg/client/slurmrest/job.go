@@ -0,0 +1,20 @@
+package slurmrest
+
+// JobAction 作业控制操作, 对应 scancel/scontrol 的子命令.
+type JobAction string
+
+const (
+	JOB_ACTION_CANCEL  JobAction = "cancel"  // scancel
+	JOB_ACTION_HOLD    JobAction = "hold"    // scontrol hold
+	JOB_ACTION_RELEASE JobAction = "release" // scontrol release
+	JOB_ACTION_REQUEUE JobAction = "requeue" // scontrol requeue
+	JOB_ACTION_SUSPEND JobAction = "suspend" // scontrol suspend
+	JOB_ACTION_RESUME  JobAction = "resume"  // scontrol resume
+	JOB_ACTION_SIGNAL  JobAction = "signal"  // scancel --signal, 需指定信号
+)
+
+// JobActions 全部作业控制操作.
+var JobActions = []JobAction{
+	JOB_ACTION_CANCEL, JOB_ACTION_HOLD, JOB_ACTION_RELEASE, JOB_ACTION_REQUEUE,
+	JOB_ACTION_SUSPEND, JOB_ACTION_RESUME, JOB_ACTION_SIGNAL,
+}
@@ -11,3 +11,11 @@ type Account struct {
 	Description  string `gorm:"column:description" json:"description"`
 	Organization string `gorm:"column:organization" json:"organization"`
 }
+
+// AccountWrite 创建账户的参数, 对应 sacctmgr add account.
+type AccountWrite struct {
+	Name         string `json:"name" binding:"required" example:"physics"` // 账户名称
+	Parent       string `json:"parent" example:"root"`                     // 父账户, 为空时为 root
+	Organization string `json:"organization" example:"school"`             // 单位
+	Description  string `json:"description" example:"physics department"`  // 描述
+}
@@ -36,3 +36,24 @@ type AssociationItem struct {
 	DefQosID       int32  `gorm:"column:def_qos_id" json:"def_qos_id"`
 	QOS            string `gorm:"column:qos" json:"qos"`
 }
+
+// UserAssociation 将用户加入账户的参数, 对应 sacctmgr add user <user> account=<account> [partition=<partition>].
+type UserAssociation struct {
+	User      string `json:"user" binding:"required" example:"alice"`      // 用户名
+	Account   string `json:"account" binding:"required" example:"physics"` // 账户
+	Partition string `json:"partition" example:"cpu"`                      // 分区, 为空时关联全部分区
+}
+
+// AssociationWrite 修改关联限制的参数, 对应 sacctmgr modify user/account where ... set ....
+// Account, User, Partition 确定关联(User 为空时为账户本身的关联), 其余字段为 nil 或空字符串时不修改, 数值限制为 -1 表示清除限制.
+type AssociationWrite struct {
+	Account   string  `json:"account" binding:"required" example:"physics"` // 账户
+	User      string  `json:"user" example:"alice"`                         // 用户
+	Partition string  `json:"partition" example:""`                         // 分区
+	GrpTres   string  `json:"grp_tres,omitempty" example:"cpu=128"`         // 组级总 TRES 资源限制, 仅修改列出的 TRES, count 为 -1 表示清除
+	MaxJobs   *int32  `json:"max_jobs,omitempty"`                           // 运行作业数上限
+	MaxWallPJ *int32  `json:"max_wall_pj,omitempty"`                        // 单作业最大运行时间(分钟)
+	QOS       *string `json:"qos,omitempty" example:"normal,high"`          // 可用 QoS 名称, 逗号分隔
+	DefQoS    *string `json:"def_qos,omitempty" example:"normal"`           // 默认 QoS 名称, 须在可用 QoS 中; 为空字符串时清除
+	Shares    *int32  `json:"shares,omitempty"`                             // 公平份额权重, -1 表示恢复默认值(1)
+}
@@ -70,15 +70,31 @@ type Job struct {
 type JobsInScheduling []JobInScheduling
 
 type JobInScheduling struct {
-	Jobid     string `json:"jobid"`     // 作业ID
-	State     string `json:"state"`     // 状态
-	User      string `json:"user"`      // 用户
-	Account   string `json:"account"`   // 账户
-	CPUs      string `json:"cpus"`      // 资源个数
-	Nodelist  string `json:"nodelist"`  // 节点列表
-	Partition string `json:"partition"` // 分区
-	QoS       string `json:"qos"`       // QoS
-	Reason    string `json:"reason"`    // 原因
+	Jobid      string `json:"jobid"`       // 作业ID
+	State      string `json:"state"`       // 状态
+	User       string `json:"user"`        // 用户
+	Account    string `json:"account"`     // 账户
+	CPUs       string `json:"cpus"`        // 资源个数
+	Nodelist   string `json:"nodelist"`    // 节点列表
+	Partition  string `json:"partition"`   // 分区
+	QoS        string `json:"qos"`         // QoS
+	Reason     string `json:"reason"`      // 原因
+	Priority   int64  `json:"priority"`    // 优先级
+	TimeSubmit int64  `json:"time_submit"` // 提交时间(Unix 时间戳)
+	TimeStart  int64  `json:"time_start"`  // 开始时间(Unix 时间戳), 未开始时为预计开始时间, 0 表示未知
+	TimeEnd    int64  `json:"time_end"`    // 结束时间(Unix 时间戳), 运行中为开始时间加时间限制, 0 表示未知或不限时
+}
+
+// SchedulingJobFilter 调度队列作业的查询条件, 字段为空值时不过滤; 列表字段匹配其中任一值.
+type SchedulingJobFilter struct {
+	Users      []string // 用户名
+	Accounts   []string // 账户
+	Partitions []string // 分区
+	QoS        []string // QoS 名称
+	States     []string // 状态名称, 同 slurm.PrintJobStateString, 如 PENDING, RUNNING
+	Reasons    []string // 原因, 如 Resources, Priority, 大小写不敏感
+	Nodes      []string // 节点名称, 作业分配的节点包含其中任一节点
+	Sort       string   // 排序字段: priority, submit, jobid, 前缀 - 表示降序; 为空时按 slurmrestd 返回顺序
 }
 
 type JobsStepsInScheduling []JobsStepInScheduling
@@ -88,3 +104,22 @@ type JobsStepInScheduling struct {
 	Name  string `json:"Name"`
 	State string `json:"state"`
 }
+
+// JobFilter 账户作业的查询条件, 字段为空值时不过滤; 列表字段匹配其中任一值.
+type JobFilter struct {
+	Users      []string // 用户名
+	Accounts   []string // 账户
+	Partitions []string // 分区
+	QoS        []string // QoS 名称
+	States     []string // 状态名称, 同 slurm.PrintJobStateString, 如 RUNNING, COMPLETED
+	SubmitFrom int64    // 提交时间下限(Unix 时间戳, 含)
+	SubmitTo   int64    // 提交时间上限(Unix 时间戳, 含)
+	StartFrom  int64    // 开始时间下限
+	StartTo    int64    // 开始时间上限
+	EndFrom    int64    // 结束时间下限
+	EndTo      int64    // 结束时间上限
+	Name       string   // 作业名称, 支持 path.Match 通配符, 如 train-*
+	ExitCode   *uint32  // 退出码
+	Nodes      []string // 节点名称, 作业分配的节点包含其中任一节点
+	Sort       string   // 排序字段: jobid, submit, start, end, 前缀 - 表示降序; 为空时按 slurmrestd 返回顺序
+}
@@ -43,3 +43,39 @@ type QoS struct {
 
 // Qoses is a slice of Qos.
 type QoSes []QoS
+
+// QoSWrite 创建或修改 QoS 的参数, 对应 sacctmgr add/modify qos 的属性.
+// 指针字段为 nil 及 TRES 字段为空表示不修改(创建时使用 Slurm 默认值); 数值限制为 -1 表示清除限制.
+// TRES 字段为逗号分隔的 type[/name]=count 或 id=count, 如 cpu=64,mem=256G,gres/gpu=8, 仅修改列出的 TRES,
+// count 为 -1 表示清除该 TRES 的限制.
+type QoSWrite struct {
+	Name                  string   `json:"name"`                                // 名称
+	Description           *string  `json:"description,omitempty"`               // 描述
+	Flags                 *string  `json:"flags,omitempty"`                     // 标志, 逗号分隔, 如 DenyOnLimit,NoReserve; 为空字符串时清除全部标志
+	Priority              *uint32  `json:"priority,omitempty"`                  // 优先级因子
+	Preempt               *string  `json:"preempt,omitempty"`                   // 可被抢占的 QoS 名称, 逗号分隔; 为空字符串时清除
+	PreemptMode           *string  `json:"preempt_mode,omitempty"`              // 抢占模式: OFF, SUSPEND, REQUEUE, CANCEL, 可附加 GANG, WITHIN
+	PreemptExemptTime     *int32   `json:"preempt_exempt_time,omitempty"`       // 抢占豁免时间(秒)
+	UsageFactor           *float64 `json:"usage_factor,omitempty"`              // 资源使用因子
+	UsageThres            *float64 `json:"usage_thres,omitempty"`               // 资源使用阈值
+	GraceTime             *int32   `json:"grace_time,omitempty"`                // 抢占宽限时间(秒)
+	MaxJobsPA             *int32   `json:"max_jobs_pa,omitempty"`               // 每账号运行作业数限制
+	MaxJobsPerUser        *int32   `json:"max_jobs_per_user,omitempty"`         // 每用户运行作业数限制
+	MaxSubmitJobsPA       *int32   `json:"max_submit_jobs_pa,omitempty"`        // 每账号提交作业数限制
+	MaxSubmitJobsPerUser  *int32   `json:"max_submit_jobs_per_user,omitempty"`  // 每用户提交作业数限制
+	MaxWallDurationPerJob *int32   `json:"max_wall_duration_per_job,omitempty"` // 作业运行时间限制(分钟)
+	GrpJobs               *int32   `json:"grp_jobs,omitempty"`                  // 总运行作业数限制
+	GrpSubmitJobs         *int32   `json:"grp_submit_jobs,omitempty"`           // 总提交作业数限制
+	GrpWall               *int32   `json:"grp_wall,omitempty"`                  // 总运行时间限制(分钟)
+	MaxTresPA             string   `json:"max_tres_pa,omitempty"`               // 账号 TRES 限制, MaxTRESPerAccount
+	MaxTresPJ             string   `json:"max_tres_pj,omitempty"`               // 作业 TRES 限制, MaxTRESPerJob
+	MaxTresPN             string   `json:"max_tres_pn,omitempty"`               // 节点 TRES 限制, MaxTRESPerNode
+	MaxTresPU             string   `json:"max_tres_pu,omitempty"`               // 用户 TRES 限制, MaxTRESPerUser
+	MaxTresMinsPJ         string   `json:"max_tres_mins_pj,omitempty"`          // 作业 TRES 时间限制, MaxTRESMinsPerJob
+	MaxTresRunMinsPA      string   `json:"max_tres_run_mins_pa,omitempty"`      // 账号 TRES 运行时间限制, MaxTRESRunMinsPerAccount
+	MaxTresRunMinsPU      string   `json:"max_tres_run_mins_pu,omitempty"`      // 用户 TRES 运行时间限制, MaxTRESRunMinsPerUser
+	MinTresPJ             string   `json:"min_tres_pj,omitempty"`               // 作业 TRES 下限, MinTRESPerJob
+	GrpTres               string   `json:"grp_tres,omitempty"`                  // 总 TRES 限制, GrpTRES
+	GrpTresMins           string   `json:"grp_tres_mins,omitempty"`             // 总 TRES 时间限制, GrpTRESMins
+	GrpTresRunMins        string   `json:"grp_tres_run_mins,omitempty"`         // 总 TRES 运行时间限制, GrpTRESRunMins
+}
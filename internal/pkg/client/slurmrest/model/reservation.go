@@ -0,0 +1,17 @@
+package model
+
+// Reservation Slurm 资源预约, 字段对应 scontrol create/update reservation 的参数.
+type Reservation struct {
+	Name      string `json:"name"`                 // ReservationName, 创建时为空则由 Slurm 生成
+	Partition string `json:"partition,omitempty"`  // PartitionName
+	StartTime string `json:"start_time,omitempty"` // StartTime, 如 2025-01-01T08:00:00, now, now+1hour
+	EndTime   string `json:"end_time,omitempty"`   // EndTime, 创建时与 Duration 二选一
+	Duration  string `json:"duration,omitempty"`   // Duration, 如 120(分钟), 2-00:00:00, UNLIMITED
+	Nodes     string `json:"nodes,omitempty"`      // Nodes, 主机列表表达式, 如 cn[001-004]
+	NodeCnt   int    `json:"node_cnt,omitempty"`   // NodeCnt, 未指定 Nodes 时由 Slurm 选择节点
+	Flags     string `json:"flags,omitempty"`      // Flags, 逗号分隔, 如 MAINT,IGNORE_JOBS
+	Users     string `json:"users,omitempty"`      // Users, 逗号分隔
+	Accounts  string `json:"accounts,omitempty"`   // Accounts, 逗号分隔
+}
+
+type Reservations []Reservation
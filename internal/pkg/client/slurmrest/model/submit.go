@@ -0,0 +1,26 @@
+package model
+
+// JobSubmission 批处理作业提交参数, 对应 sbatch 的脚本及常用选项.
+type JobSubmission struct {
+	Script      string            `json:"script"`                  // 批处理脚本, 以 #! 开头
+	User        string            `json:"user"`                    // 提交用户, 作业以该用户身份运行
+	Name        string            `json:"name,omitempty"`          // --job-name
+	Partition   string            `json:"partition,omitempty"`     // --partition
+	QoS         string            `json:"qos,omitempty"`           // --qos
+	Account     string            `json:"account,omitempty"`       // --account
+	Nodes       string            `json:"nodes,omitempty"`         // --nodes, 如 2 或 1-4
+	Tasks       uint32            `json:"tasks,omitempty"`         // --ntasks
+	CPUsPerTask uint32            `json:"cpus_per_task,omitempty"` // --cpus-per-task
+	TimeLimit   uint32            `json:"time_limit,omitempty"`    // --time, 单位分钟
+	Gres        string            `json:"gres,omitempty"`          // --gres, 如 gpu:2
+	Dependency  string            `json:"dependency,omitempty"`    // --dependency, 如 afterok:1234
+	Array       string            `json:"array,omitempty"`         // --array, 如 1-10%2
+	WorkDir     string            `json:"work_dir,omitempty"`      // --chdir
+	Environment map[string]string `json:"environment,omitempty"`   // 环境变量
+}
+
+// JobSubmitResult 作业提交结果.
+type JobSubmitResult struct {
+	JobID    string   `json:"job_id"`   // 作业号, 仅校验时为空
+	Warnings []string `json:"warnings"` // 提交警告(如 slurmctld 的 job_submit 插件返回的提示)
+}
@@ -0,0 +1,11 @@
+package model
+
+// TresList is a slice of Tres rows.
+type TresList []Tres
+
+// Tres represents a row in tres_table.
+type Tres struct {
+	ID   uint32 `gorm:"column:id;primaryKey" json:"id"`
+	Type string `gorm:"column:type" json:"type"`
+	Name string `gorm:"column:name" json:"name"`
+}
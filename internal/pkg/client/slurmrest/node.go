@@ -0,0 +1,14 @@
+package slurmrest
+
+// NodeState 节点管理操作的目标状态, 对应 scontrol update nodename=... state=....
+type NodeState string
+
+const (
+	NODE_STATE_DRAIN  NodeState = "DRAIN"  // 不再分配新作业, 运行中的作业不受影响
+	NODE_STATE_RESUME NodeState = "RESUME" // 恢复 drain/down 的节点
+	NODE_STATE_DOWN   NodeState = "DOWN"   // 立即下线, 运行中的作业被终止
+	NODE_STATE_REBOOT NodeState = "REBOOT" // 作业结束后重启(scontrol reboot)
+)
+
+// NodeStates 全部节点管理操作.
+var NodeStates = []NodeState{NODE_STATE_DRAIN, NODE_STATE_RESUME, NODE_STATE_DOWN, NODE_STATE_REBOOT}
@@ -0,0 +1,957 @@
+package slurmrest
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+	"csjk-bk/internal/pkg/common/slurm"
+	"csjk-bk/internal/pkg/common/slurm/hostlist"
+)
+
+// DEFAULT_OFFICIAL_VERSION 集群未配置接口版本时使用的官方 slurmrestd 接口版本.
+const DEFAULT_OFFICIAL_VERSION = "v0.0.40"
+
+// Official 官方 slurmrestd(v0.0.40+) 客户端, 使用 /slurm/<version>/... 及 /slurmdb/<version>/... 接口,
+// 并通过 X-SLURM-USER-NAME/X-SLURM-USER-TOKEN 请求头认证. 官方接口不支持分页, 分页在本地完成.
+// 不提供 Ldap 用户及组管理, 相关方法返回 ErrUnsupported.
+type Official struct {
+	client  Doer
+	timeout time.Duration
+	logger  *slog.Logger
+
+	version string
+	user    string
+	token   string
+}
+
+func NewOfficial(client Doer, timeout time.Duration, logger *slog.Logger) *Official {
+	return &Official{
+		client:  client,
+		timeout: timeout,
+		logger:  logger,
+		version: DEFAULT_OFFICIAL_VERSION,
+	}
+}
+
+// With 返回使用接口版本(version, 为空时使用 DEFAULT_OFFICIAL_VERSION)及认证信息(user, token)的客户端副本.
+func (o *Official) With(version, user, token string) *Official {
+	cp := *o
+	cp.version = version
+	if cp.version == "" {
+		cp.version = DEFAULT_OFFICIAL_VERSION
+	}
+	cp.user = user
+	cp.token = token
+	return &cp
+}
+
+// officialStatusError 将官方 slurmrestd 的非 2xx 响应转换为带错误码的错误, 错误描述取自响应体 errors 中的第一项.
+func officialStatusError(resp *http.Response) error {
+	var body struct {
+		Errors []officialError `json:"errors"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&body)
+	detail := ""
+	if len(body.Errors) > 0 {
+		detail = body.Errors[0].Description
+		if detail == "" {
+			detail = body.Errors[0].Error
+		}
+	}
+	return apperror.Upstream("slurmrestd", resp.StatusCode, detail)
+}
+
+// get 请求 http://<addr>/<plugin>/<version>/<path>, 并将响应解码到 out. plugin 为 slurm 或 slurmdb,
+// path 中的名称等参数需由调用方使用 url.PathEscape 转义.
+func (o *Official) get(ctx context.Context, addr, plugin, path string, query url.Values, out any) error {
+	return o.do(ctx, http.MethodGet, addr, plugin, path, query, nil, out)
+}
+
+// do 以 method 请求 http://<addr>/<plugin>/<version>/<path>, body 不为空时编码为 JSON 请求体,
+// out 不为空时将响应解码到 out.
+func (o *Official) do(ctx context.Context, method, addr, plugin, path string, query url.Values, body, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	urlStr := fmt.Sprintf("http://%s/%s/%s/%s", addr, plugin, o.version, path)
+	if len(query) != 0 {
+		urlStr += "?" + query.Encode()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("unable to marshal slurmrestd request body: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+	if err != nil {
+		o.logger.Error("unable to create request for slurmrestd", "err", err.Error(), "url", urlStr)
+		return fmt.Errorf("unable to create request for slurmrestd: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if o.user != "" {
+		req.Header.Set("X-SLURM-USER-NAME", o.user)
+	}
+	if o.token != "" {
+		req.Header.Set("X-SLURM-USER-TOKEN", o.token)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to do request for slurmrestd(%s): %w", urlStr, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		o.logger.Error("unexcepted status code", "code", resp.StatusCode, "url", urlStr)
+		return officialStatusError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		o.logger.Error("unable to decode slurmrestd response", "err", err.Error(), "url", urlStr)
+		return fmt.Errorf("unable to decode slurmrestd response: %w", err)
+	}
+	return nil
+}
+
+// pageRange 返回第 page 页(从 1 开始)在长度为 n 的列表中的下标范围 [lo, hi). 不分页时返回整个列表.
+func pageRange(n int, paging bool, page, pageSize int) (int, int) {
+	if !paging || pageSize <= 0 {
+		return 0, n
+	}
+	lo := (page - 1) * pageSize
+	if lo < 0 {
+		lo = 0
+	}
+	if lo > n {
+		lo = n
+	}
+	return lo, min(lo+pageSize, n)
+}
+
+func (o *Official) GetNodes(ctx context.Context, addr string, partitions []string, paging bool, page, pageSize int) (model.Nodes, int, error) {
+	var data struct {
+		Nodes []officialNode `json:"nodes"`
+	}
+	if err := o.get(ctx, addr, "slurm", "nodes", nil, &data); err != nil {
+		return nil, 0, err
+	}
+
+	filter := make([]string, 0, len(partitions))
+	for _, p := range partitions {
+		if p != "" {
+			filter = append(filter, p)
+		}
+	}
+	nodes := make(model.Nodes, 0, len(data.Nodes))
+	for _, n := range data.Nodes {
+		if len(filter) != 0 && !slices.ContainsFunc(n.Partitions, func(p string) bool { return slices.Contains(filter, p) }) {
+			continue
+		}
+		nodes = append(nodes, n.toModel())
+	}
+
+	lo, hi := pageRange(len(nodes), paging, page, pageSize)
+	return nodes[lo:hi], len(nodes), nil
+}
+
+// UpdateNodeState 逐个更新节点状态, 官方接口不支持重启(REBOOT), 返回 ErrUnsupported.
+func (o *Official) UpdateNodeState(ctx context.Context, addr string, nodes []string, state NodeState, reason string) error {
+	if state == NODE_STATE_REBOOT {
+		return ErrUnsupported
+	}
+	body := struct {
+		State  []string `json:"state"`
+		Reason string   `json:"reason,omitempty"`
+	}{State: []string{string(state)}, Reason: reason}
+	for _, n := range nodes {
+		if err := o.do(ctx, http.MethodPost, addr, "slurm", "node/"+url.PathEscape(n), nil, body, nil); err != nil {
+			return fmt.Errorf("unable to update state of node %s: %w", n, err)
+		}
+	}
+	return nil
+}
+
+// GetSchedulingJobs 官方接口不支持按条件查询调度队列, 查询条件及排序均在本地处理.
+func (o *Official) GetSchedulingJobs(ctx context.Context, addr string, filter model.SchedulingJobFilter, paging bool, page, pageSize int) (model.JobsInScheduling, int64, error) {
+	var data struct {
+		Jobs []officialJob `json:"jobs"`
+	}
+	if err := o.get(ctx, addr, "slurm", "jobs", nil, &data); err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make(model.JobsInScheduling, 0, len(data.Jobs))
+	for _, j := range data.Jobs {
+		if job := j.toModel(); matchSchedulingJob(filter, job) {
+			jobs = append(jobs, job)
+		}
+	}
+	sortSchedulingJobs(jobs, filter.Sort)
+
+	lo, hi := pageRange(len(jobs), paging, page, pageSize)
+	return jobs[lo:hi], int64(len(jobs)), nil
+}
+
+// matchSchedulingJob 判断调度队列中的作业(job)是否满足查询条件.
+func matchSchedulingJob(f model.SchedulingJobFilter, job model.JobInScheduling) bool {
+	in := func(list []string, v string) bool { return len(list) == 0 || slices.Contains(list, v) }
+	return in(f.Users, job.User) && in(f.Accounts, job.Account) && in(f.Partitions, job.Partition) && in(f.QoS, job.QoS) &&
+		in(f.States, job.State) &&
+		(len(f.Reasons) == 0 || slices.ContainsFunc(f.Reasons, func(r string) bool { return strings.EqualFold(r, job.Reason) })) &&
+		(len(f.Nodes) == 0 || onNodes(job.Nodelist, f.Nodes))
+}
+
+// onNodes 判断作业的节点列表(nodelist)是否包含 nodes 中的任一节点.
+func onNodes(nodelist string, nodes []string) bool {
+	hosts, err := hostlist.Expand(nodelist)
+	return err == nil && len(hostlist.Intersect(hosts, nodes)) > 0
+}
+
+// sortSchedulingJobs 按 key(priority, submit, jobid, 前缀 - 表示降序)排序, key 为空或未知时不排序.
+func sortSchedulingJobs(jobs model.JobsInScheduling, key string) {
+	desc := strings.HasPrefix(key, "-")
+	var field func(model.JobInScheduling) int64
+	switch strings.TrimPrefix(key, "-") {
+	case "priority":
+		field = func(j model.JobInScheduling) int64 { return j.Priority }
+	case "submit":
+		field = func(j model.JobInScheduling) int64 { return j.TimeSubmit }
+	case "jobid":
+		field = func(j model.JobInScheduling) int64 {
+			id, _ := strconv.ParseInt(j.Jobid, 10, 64)
+			return id
+		}
+	default:
+		return
+	}
+	slices.SortStableFunc(jobs, func(a, b model.JobInScheduling) int {
+		if desc {
+			a, b = b, a
+		}
+		return cmp.Compare(field(a), field(b))
+	})
+}
+
+func (o *Official) GetSchedulingJob(ctx context.Context, addr, jobid string) (model.JobInScheduling, error) {
+	var data struct {
+		Jobs []officialJob `json:"jobs"`
+	}
+	if err := o.get(ctx, addr, "slurm", "job/"+url.PathEscape(jobid), nil, &data); err != nil {
+		return model.JobInScheduling{}, err
+	}
+	if len(data.Jobs) == 0 {
+		return model.JobInScheduling{}, apperror.New(apperror.CODE_NOT_FOUND, "job not found: "+jobid)
+	}
+	return data.Jobs[0].toModel(), nil
+}
+
+// ControlJob 官方接口仅支持 cancel, signal(DELETE /job/{id}) 及 hold, release(更新作业的 hold 属性),
+// 其余操作返回 ErrUnsupported.
+func (o *Official) ControlJob(ctx context.Context, addr, jobid string, action JobAction, signal string) error {
+	path := "job/" + url.PathEscape(jobid)
+	switch action {
+	case JOB_ACTION_CANCEL, JOB_ACTION_SIGNAL:
+		query := url.Values{}
+		if signal != "" {
+			query.Set("signal", signal)
+		}
+		return o.do(ctx, http.MethodDelete, addr, "slurm", path, query, nil, nil)
+	case JOB_ACTION_HOLD, JOB_ACTION_RELEASE:
+		body := map[string]bool{"hold": action == JOB_ACTION_HOLD}
+		return o.do(ctx, http.MethodPost, addr, "slurm", path, nil, body, nil)
+	}
+	return ErrUnsupported
+}
+
+// SubmitJob 作业以 job.User 的身份提交(X-SLURM-USER-NAME), 集群配置的令牌需属于 SlurmUser 才能代其他用户提交.
+// 官方接口不支持仅校验, validateOnly 为 true 时返回 ErrUnsupported.
+func (o *Official) SubmitJob(ctx context.Context, addr string, job model.JobSubmission, validateOnly bool) (model.JobSubmitResult, error) {
+	if validateOnly {
+		return model.JobSubmitResult{}, ErrUnsupported
+	}
+	client := o
+	if job.User != "" {
+		client = o.With(o.version, job.User, o.token)
+	}
+
+	body := struct {
+		Script string          `json:"script"`
+		Job    officialJobDesc `json:"job"`
+	}{Script: job.Script, Job: newOfficialJobDesc(job)}
+	var data struct {
+		JobID         officialNumber  `json:"job_id"`
+		JobSubmitUser string          `json:"job_submit_user_msg"`
+		Warnings      []officialError `json:"warnings"`
+	}
+	if err := client.do(ctx, http.MethodPost, addr, "slurm", "job/submit", nil, body, &data); err != nil {
+		return model.JobSubmitResult{}, err
+	}
+
+	result := model.JobSubmitResult{JobID: strconv.FormatInt(data.JobID.value(), 10), Warnings: []string{}}
+	if data.JobSubmitUser != "" {
+		result.Warnings = append(result.Warnings, data.JobSubmitUser)
+	}
+	for _, w := range data.Warnings {
+		result.Warnings = append(result.Warnings, w.Description)
+	}
+	return result, nil
+}
+
+// GetJobStepsOfScheduling 官方 slurm 接口不返回运行中作业的作业步, 从 slurmdb 中获取.
+func (o *Official) GetJobStepsOfScheduling(ctx context.Context, addr, jobid string) (model.JobsStepsInScheduling, error) {
+	job, err := o.getAccountingJob(ctx, addr, jobid)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make(model.JobsStepsInScheduling, 0, len(job.Steps))
+	for _, s := range job.Steps {
+		steps = append(steps, model.JobsStepInScheduling{ID: s.Step.ID, Name: s.Step.Name, State: strings.Join(s.State, "+")})
+	}
+	return steps, nil
+}
+
+// GetJobsFromAccounting 查询条件尽量由 slurmdbd 过滤(见 accountingQuery), 其余条件及排序在本地处理.
+func (o *Official) GetJobsFromAccounting(ctx context.Context, addr string, filter model.JobFilter, paging bool, page, pageSize int) (model.Jobs, int, error) {
+	query, rest := accountingQuery(filter)
+	var data struct {
+		Jobs []officialAcctJob `json:"jobs"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "jobs", query, &data); err != nil {
+		return nil, 0, err
+	}
+	qosIDs, err := o.qosIDs(ctx, addr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make(model.Jobs, 0, len(data.Jobs))
+	for _, j := range data.Jobs {
+		job := j.toModel(qosIDs)
+		if matchJob(rest, j.User, j.QoS, job) {
+			jobs = append(jobs, job)
+		}
+	}
+	sortJobs(jobs, filter.Sort)
+
+	lo, hi := pageRange(len(jobs), paging, page, pageSize)
+	return jobs[lo:hi], len(jobs), nil
+}
+
+// accountingQuery 将查询条件转换为 /slurmdb/<version>/jobs 的查询参数, 返回 slurmdbd 无法表示, 需在本地过滤的条件(rest).
+// slurmdbd 的 start_time/end_time 为时间窗口(作业在窗口内处于可调度或运行状态), 且要求作业有可调度时间,
+// 因此仅由开始时间范围推出窗口, 提交及结束时间在本地过滤; 指定状态时 slurmdbd 按状态的发生时间解释窗口, 此时不下推窗口.
+// 作业名称含通配符时在本地匹配.
+func accountingQuery(f model.JobFilter) (url.Values, model.JobFilter) {
+	query := url.Values{}
+	set := func(key string, list []string) {
+		if len(list) != 0 {
+			query.Set(key, strings.Join(list, ","))
+		}
+	}
+	set("users", f.Users)
+	set("account", f.Accounts)
+	set("partition", f.Partitions)
+	set("qos", f.QoS)
+	set("state", f.States)
+	if len(f.Nodes) != 0 {
+		query.Set("node", hostlist.Compress(f.Nodes))
+	}
+	if f.ExitCode != nil {
+		query.Set("exit_code", strconv.FormatUint(uint64(*f.ExitCode), 10))
+	}
+	rest := model.JobFilter{
+		SubmitFrom: f.SubmitFrom, SubmitTo: f.SubmitTo,
+		StartFrom: f.StartFrom, StartTo: f.StartTo,
+		EndFrom: f.EndFrom, EndTo: f.EndTo,
+		Sort: f.Sort,
+	}
+	if f.Name != "" {
+		if strings.ContainsAny(f.Name, `*?[\`) {
+			rest.Name = f.Name
+		} else {
+			query.Set("job_name", f.Name)
+		}
+	}
+	if len(f.States) == 0 {
+		if f.StartFrom > 0 {
+			query.Set("start_time", strconv.FormatInt(f.StartFrom, 10))
+		}
+		if f.StartTo > 0 {
+			query.Set("end_time", strconv.FormatInt(f.StartTo+1, 10)) // slurmdbd 要求可调度时间早于 end_time
+		}
+	}
+	return query, rest
+}
+
+// matchJob 判断作业(job)是否满足查询条件, user 及 qos 为作业的用户名及 QoS 名称.
+func matchJob(f model.JobFilter, user, qos string, job model.Job) bool {
+	in := func(list []string, v string) bool { return len(list) == 0 || slices.Contains(list, v) }
+	between := func(v uint64, from, to int64) bool {
+		return (from <= 0 || int64(v) >= from) && (to <= 0 || (v != 0 && int64(v) <= to))
+	}
+	if !in(f.Users, user) || !in(f.Accounts, job.Account) || !in(f.Partitions, job.Partition) || !in(f.QoS, qos) ||
+		!in(f.States, slurm.PrintJobStateString(job.State)) {
+		return false
+	}
+	if !between(job.TimeSubmit, f.SubmitFrom, f.SubmitTo) || !between(job.TimeStart, f.StartFrom, f.StartTo) || !between(job.TimeEnd, f.EndFrom, f.EndTo) {
+		return false
+	}
+	if f.Name != "" {
+		if ok, _ := path.Match(f.Name, job.JobName); !ok {
+			return false
+		}
+	}
+	if f.ExitCode != nil && job.ExitCode != *f.ExitCode {
+		return false
+	}
+	return len(f.Nodes) == 0 || onNodes(job.Nodelist, f.Nodes)
+}
+
+// sortJobs 按 key(jobid, submit, start, end, 前缀 - 表示降序)排序, key 为空或未知时不排序.
+func sortJobs(jobs model.Jobs, key string) {
+	desc := strings.HasPrefix(key, "-")
+	var field func(model.Job) uint64
+	switch strings.TrimPrefix(key, "-") {
+	case "jobid":
+		field = func(j model.Job) uint64 { return uint64(j.IDJob) }
+	case "submit":
+		field = func(j model.Job) uint64 { return j.TimeSubmit }
+	case "start":
+		field = func(j model.Job) uint64 { return j.TimeStart }
+	case "end":
+		field = func(j model.Job) uint64 { return j.TimeEnd }
+	default:
+		return
+	}
+	slices.SortStableFunc(jobs, func(a, b model.Job) int {
+		if desc {
+			a, b = b, a
+		}
+		return cmp.Compare(field(a), field(b))
+	})
+}
+
+func (o *Official) GetJobFromAccounting(ctx context.Context, addr string, jobid uint32) (model.Job, error) {
+	job, err := o.getAccountingJob(ctx, addr, strconv.FormatUint(uint64(jobid), 10))
+	if err != nil {
+		return model.Job{}, err
+	}
+	qosIDs, err := o.qosIDs(ctx, addr)
+	if err != nil {
+		return model.Job{}, err
+	}
+	return job.toModel(qosIDs), nil
+}
+
+func (o *Official) GetStepsOfJobFromAccounting(ctx context.Context, addr string, jobid uint32) (model.Steps, error) {
+	job, err := o.getAccountingJob(ctx, addr, strconv.FormatUint(uint64(jobid), 10))
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make(model.Steps, 0, len(job.Steps))
+	for _, s := range job.Steps {
+		steps = append(steps, s.toModel())
+	}
+	return steps, nil
+}
+
+func (o *Official) getAccountingJob(ctx context.Context, addr, jobid string) (officialAcctJob, error) {
+	var data struct {
+		Jobs []officialAcctJob `json:"jobs"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "job/"+url.PathEscape(jobid), nil, &data); err != nil {
+		return officialAcctJob{}, err
+	}
+	if len(data.Jobs) == 0 {
+		return officialAcctJob{}, apperror.New(apperror.CODE_NOT_FOUND, "job not found: "+jobid)
+	}
+	return data.Jobs[0], nil
+}
+
+// RESERVATION_WRITE_VERSION 官方接口自该版本(Slurm 25.05)起支持创建, 更新及删除预约.
+const RESERVATION_WRITE_VERSION = "v0.0.43"
+
+// versionAtLeast 判断客户端使用的接口版本(vX.Y.Z)是否不低于 want.
+func (o *Official) versionAtLeast(want string) bool {
+	parse := func(v string) []int {
+		var nums []int
+		for _, p := range strings.Split(strings.TrimPrefix(v, "v"), ".") {
+			n, _ := strconv.Atoi(p)
+			nums = append(nums, n)
+		}
+		return nums
+	}
+	return slices.Compare(parse(o.version), parse(want)) >= 0
+}
+
+func (o *Official) GetReservations(ctx context.Context, addr string, paging bool, page, pageSize int) (model.Reservations, int, error) {
+	var data struct {
+		Reservations []officialReservation `json:"reservations"`
+	}
+	if err := o.get(ctx, addr, "slurm", "reservations", nil, &data); err != nil {
+		return nil, 0, err
+	}
+
+	lo, hi := pageRange(len(data.Reservations), paging, page, pageSize)
+	list := make(model.Reservations, 0, hi-lo)
+	for _, r := range data.Reservations[lo:hi] {
+		list = append(list, r.toModel())
+	}
+	return list, len(data.Reservations), nil
+}
+
+// CreateReservation 官方接口 v0.0.43 之前不支持创建预约, 返回 ErrUnsupported. 官方接口不返回生成的预约名称,
+// 因此 resv.Name 不能为空.
+func (o *Official) CreateReservation(ctx context.Context, addr string, resv model.Reservation) (string, error) {
+	if !o.versionAtLeast(RESERVATION_WRITE_VERSION) {
+		return "", ErrUnsupported
+	}
+	if resv.Name == "" {
+		return "", apperror.New(apperror.CODE_VALIDATION_FAILED, "reservation name is required by official slurmrestd")
+	}
+	body, err := newOfficialReservationDesc(resv, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if err := o.do(ctx, http.MethodPost, addr, "slurm", "reservation", nil, body, nil); err != nil {
+		return "", err
+	}
+	return resv.Name, nil
+}
+
+// UpdateReservation 官方接口对已存在的同名预约执行更新, v0.0.43 之前返回 ErrUnsupported.
+func (o *Official) UpdateReservation(ctx context.Context, addr string, resv model.Reservation) error {
+	if !o.versionAtLeast(RESERVATION_WRITE_VERSION) {
+		return ErrUnsupported
+	}
+	body, err := newOfficialReservationDesc(resv, time.Now())
+	if err != nil {
+		return err
+	}
+	return o.do(ctx, http.MethodPost, addr, "slurm", "reservation", nil, body, nil)
+}
+
+// DeleteReservation 官方接口 v0.0.43 之前返回 ErrUnsupported.
+func (o *Official) DeleteReservation(ctx context.Context, addr, name string) error {
+	if !o.versionAtLeast(RESERVATION_WRITE_VERSION) {
+		return ErrUnsupported
+	}
+	return o.do(ctx, http.MethodDelete, addr, "slurm", "reservation/"+url.PathEscape(name), nil, nil, nil)
+}
+
+func (o *Official) GetPartitions(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error) {
+	var data struct {
+		Partitions []officialPartition `json:"partitions"`
+	}
+	if err := o.get(ctx, addr, "slurm", "partitions", nil, &data); err != nil {
+		return nil, 0, err
+	}
+
+	lo, hi := pageRange(len(data.Partitions), paging, page, pageSize)
+	list := make([]map[string]string, 0, hi-lo)
+	for _, p := range data.Partitions[lo:hi] {
+		list = append(list, p.toMap())
+	}
+	return list, len(data.Partitions), nil
+}
+
+func (o *Official) GetPartitionByName(ctx context.Context, addr string, name string) (map[string]string, error) {
+	var data struct {
+		Partitions []officialPartition `json:"partitions"`
+	}
+	if err := o.get(ctx, addr, "slurm", "partition/"+url.PathEscape(name), nil, &data); err != nil {
+		return nil, err
+	}
+	if len(data.Partitions) == 0 {
+		return nil, apperror.New(apperror.CODE_NOT_FOUND, "partition not found: "+name)
+	}
+	return data.Partitions[0].toMap(), nil
+}
+
+func (o *Official) getQos(ctx context.Context, addr string) ([]officialQoS, error) {
+	var data struct {
+		QoS []officialQoS `json:"qos"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "qos", nil, &data); err != nil {
+		return nil, err
+	}
+	return data.QoS, nil
+}
+
+// qosIDs 返回 QoS 名称到 ID 的映射, 官方接口中作业及关联仅返回 QoS 名称.
+func (o *Official) qosIDs(ctx context.Context, addr string) (map[string]int32, error) {
+	list, err := o.getQos(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int32, len(list))
+	for _, q := range list {
+		ids[q.Name] = q.ID
+	}
+	return ids, nil
+}
+
+func (o *Official) GetQos(ctx context.Context, addr string, id uint32) (model.QoS, error) {
+	list, err := o.getQos(ctx, addr)
+	if err != nil {
+		return model.QoS{}, err
+	}
+	for _, q := range list {
+		if uint32(q.ID) == id {
+			return q.toModel(), nil
+		}
+	}
+	return model.QoS{}, apperror.New(apperror.CODE_NOT_FOUND, fmt.Sprintf("qos not found: id=%d", id))
+}
+
+func (o *Official) GetQosAll(ctx context.Context, addr string, paging bool, page, pageSize int) (model.QoSes, int, error) {
+	list, err := o.getQos(ctx, addr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lo, hi := pageRange(len(list), paging, page, pageSize)
+	qoses := make(model.QoSes, 0, hi-lo)
+	for _, q := range list[lo:hi] {
+		qoses = append(qoses, q.toModel())
+	}
+	return qoses, len(list), nil
+}
+
+// CreateQoS 官方接口 POST slurmdb/qos 创建或修改同名 QoS, 创建前检查同名 QoS 是否存在.
+func (o *Official) CreateQoS(ctx context.Context, addr string, qos model.QoSWrite) error {
+	list, err := o.getQos(ctx, addr)
+	if err != nil {
+		return err
+	}
+	for _, q := range list {
+		if q.Name == qos.Name {
+			return apperror.New(apperror.CODE_CONFLICT, "qos already exists: "+qos.Name)
+		}
+	}
+	return o.postQoS(ctx, addr, qos)
+}
+
+func (o *Official) UpdateQoS(ctx context.Context, addr string, qos model.QoSWrite) error {
+	return o.postQoS(ctx, addr, qos)
+}
+
+func (o *Official) postQoS(ctx context.Context, addr string, qos model.QoSWrite) error {
+	desc, err := newOfficialQoSDesc(qos)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{"qos": []officialDesc{desc}}
+	return o.do(ctx, http.MethodPost, addr, "slurmdb", "qos", nil, body, nil)
+}
+
+func (o *Official) DeleteQoS(ctx context.Context, addr, name string) error {
+	return o.do(ctx, http.MethodDelete, addr, "slurmdb", "qos/"+url.PathEscape(name), nil, nil, nil)
+}
+
+func (o *Official) GetTres(ctx context.Context, addr string) (model.TresList, error) {
+	var data struct {
+		Tres []officialTres `json:"TRES"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "tres", nil, &data); err != nil {
+		return nil, err
+	}
+
+	list := make(model.TresList, 0, len(data.Tres))
+	for _, t := range data.Tres {
+		list = append(list, model.Tres{ID: uint32(t.ID), Type: t.Type, Name: t.Name})
+	}
+	return list, nil
+}
+
+func (o *Official) GetAccounts(ctx context.Context, addr string, paging bool, page, pageSize int) (model.Accounts, int, error) {
+	var data struct {
+		Accounts []officialAccount `json:"accounts"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "accounts", nil, &data); err != nil {
+		return nil, 0, err
+	}
+
+	lo, hi := pageRange(len(data.Accounts), paging, page, pageSize)
+	accounts := make(model.Accounts, 0, hi-lo)
+	for _, a := range data.Accounts[lo:hi] {
+		accounts = append(accounts, a.toModel())
+	}
+	return accounts, len(data.Accounts), nil
+}
+
+func (o *Official) GetAccountByName(ctx context.Context, addr, name string) (model.Account, error) {
+	var data struct {
+		Accounts []officialAccount `json:"accounts"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "account/"+url.PathEscape(name), nil, &data); err != nil {
+		return model.Account{}, err
+	}
+	if len(data.Accounts) == 0 {
+		return model.Account{}, apperror.New(apperror.CODE_NOT_FOUND, "account not found: "+name)
+	}
+	return data.Accounts[0].toModel(), nil
+}
+
+// CreateAccount 官方接口 POST slurmdb/accounts_association, 同时创建账户及其在父账户下的关联.
+func (o *Official) CreateAccount(ctx context.Context, addr string, acct model.AccountWrite) error {
+	cond := map[string]any{"accounts": []string{acct.Name}}
+	if acct.Parent != "" {
+		cond["association"] = map[string]string{"parent": acct.Parent}
+	}
+	body := map[string]any{
+		"association_condition": cond,
+		"account":               map[string]string{"description": acct.Description, "organization": acct.Organization},
+	}
+	return o.do(ctx, http.MethodPost, addr, "slurmdb", "accounts_association", nil, body, nil)
+}
+
+func (o *Official) DeleteAccount(ctx context.Context, addr, name string) error {
+	return o.do(ctx, http.MethodDelete, addr, "slurmdb", "account/"+url.PathEscape(name), nil, nil, nil)
+}
+
+func (o *Official) getAssociations(ctx context.Context, addr string, query url.Values) ([]officialAssociation, error) {
+	var data struct {
+		Associations []officialAssociation `json:"associations"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "associations", query, &data); err != nil {
+		return nil, err
+	}
+	return data.Associations, nil
+}
+
+// GetChildNodesOfAccount 根据全部关联构造账户(name)的子账户及子用户, 子用户的可用账户为其拥有关联的全部账户.
+func (o *Official) GetChildNodesOfAccount(ctx context.Context, addr, name string) (AccountNode, error) {
+	account, err := o.GetAccountByName(ctx, addr, name)
+	if err != nil {
+		return AccountNode{}, err
+	}
+	assocs, err := o.getAssociations(ctx, addr, nil)
+	if err != nil {
+		return AccountNode{}, err
+	}
+	var users struct {
+		Users []officialUser `json:"users"`
+	}
+	if err := o.get(ctx, addr, "slurmdb", "users", nil, &users); err != nil {
+		return AccountNode{}, err
+	}
+	levels := make(map[string]int, len(users.Users))
+	for _, u := range users.Users {
+		levels[u.Name] = u.adminLevel()
+	}
+
+	node := AccountNode{
+		Name:         account.Name,
+		Organization: account.Organization,
+		Description:  account.Description,
+		SubAccounts:  make([]string, 0),
+		SubUsers:     make([]UserNode, 0),
+	}
+	available := make(map[string][]string)
+	for _, a := range assocs {
+		if a.User != "" && !slices.Contains(available[a.User], a.Account) {
+			available[a.User] = append(available[a.User], a.Account)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, a := range assocs {
+		switch {
+		case a.User == "" && a.ParentAccount == name && a.Account != name:
+			if !slices.Contains(node.SubAccounts, a.Account) {
+				node.SubAccounts = append(node.SubAccounts, a.Account)
+			}
+		case a.User != "" && a.Account == name && !seen[a.User]:
+			seen[a.User] = true
+			node.SubUsers = append(node.SubUsers, UserNode{Name: a.User, AdminLevel: levels[a.User], AvailableAccounts: available[a.User]})
+		}
+	}
+	return node, nil
+}
+
+// GetAssociationChildNodesOfAccount 根据账户(name)下的关联构造子账户及子用户的关联分区.
+func (o *Official) GetAssociationChildNodesOfAccount(ctx context.Context, addr, name string) (AssociationNode, error) {
+	assocs, err := o.getAssociations(ctx, addr, nil)
+	if err != nil {
+		return AssociationNode{}, err
+	}
+
+	node := AssociationNode{Name: name, SubAccounts: make([]string, 0), SubUsers: make([]AssociationUserNode, 0)}
+	users := make(map[string]int)
+	for _, a := range assocs {
+		switch {
+		case a.User == "" && a.Account == name:
+			node.Partition = a.Partition
+		case a.User == "" && a.ParentAccount == name:
+			if !slices.Contains(node.SubAccounts, a.Account) {
+				node.SubAccounts = append(node.SubAccounts, a.Account)
+			}
+		case a.User != "" && a.Account == name:
+			i, ok := users[a.User]
+			if !ok {
+				i = len(node.SubUsers)
+				users[a.User] = i
+				node.SubUsers = append(node.SubUsers, AssociationUserNode{Name: a.User, Partitions: make([]string, 0)})
+			}
+			if a.Partition != "" {
+				node.SubUsers[i].Partitions = append(node.SubUsers[i].Partitions, a.Partition)
+			}
+		}
+	}
+	return node, nil
+}
+
+// findAssociation 返回账户(acct), 用户(user), 分区(partition)对应的关联, 不存在时返回 CODE_NOT_FOUND.
+func (o *Official) findAssociation(ctx context.Context, addr, acct, user, partition string) (officialAssociation, error) {
+	q := url.Values{}
+	q.Set("account", acct)
+	if user != "" {
+		q.Set("user", user)
+	}
+	if partition != "" {
+		q.Set("partition", partition)
+	}
+	assocs, err := o.getAssociations(ctx, addr, q)
+	if err != nil {
+		return officialAssociation{}, err
+	}
+	// 过滤条件为空时仅匹配账户本身的关联
+	idx := slices.IndexFunc(assocs, func(a officialAssociation) bool {
+		return a.Account == acct && a.User == user && (partition == "" || a.Partition == partition)
+	})
+	if idx < 0 {
+		return officialAssociation{}, apperror.New(apperror.CODE_NOT_FOUND, fmt.Sprintf("association not found: account=%s, user=%s, partition=%s", acct, user, partition))
+	}
+	return assocs[idx], nil
+}
+
+func (o *Official) GetAssociationDetail(ctx context.Context, addr, acct, user, partition string) (model.AssociationItem, error) {
+	assoc, err := o.findAssociation(ctx, addr, acct, user, partition)
+	if err != nil {
+		return model.AssociationItem{}, err
+	}
+	qosIDs, err := o.qosIDs(ctx, addr)
+	if err != nil {
+		return model.AssociationItem{}, err
+	}
+	return assoc.toModel(qosIDs), nil
+}
+
+// AddUserAssociation 官方接口 POST slurmdb/users_association, 用户不存在时一并创建.
+func (o *Official) AddUserAssociation(ctx context.Context, addr string, ua model.UserAssociation) error {
+	cond := map[string]any{"users": []string{ua.User}, "accounts": []string{ua.Account}}
+	if ua.Partition != "" {
+		cond["partitions"] = []string{ua.Partition}
+	}
+	body := map[string]any{"association_condition": cond}
+	return o.do(ctx, http.MethodPost, addr, "slurmdb", "users_association", nil, body, nil)
+}
+
+// UpdateAssociation 官方接口 POST slurmdb/associations 修改已存在的关联, 关联所属的集群取自当前关联.
+func (o *Official) UpdateAssociation(ctx context.Context, addr string, assoc model.AssociationWrite) error {
+	cur, err := o.findAssociation(ctx, addr, assoc.Account, assoc.User, assoc.Partition)
+	if err != nil {
+		return err
+	}
+	assoc.Partition = cur.Partition
+	desc, err := newOfficialAssociationDesc(assoc, cur.Cluster)
+	if err != nil {
+		return err
+	}
+	body := map[string]any{"associations": []officialDesc{desc}}
+	return o.do(ctx, http.MethodPost, addr, "slurmdb", "associations", nil, body, nil)
+}
+
+// SetDefaultAccount 官方接口 POST slurmdb/users 修改用户的默认账户.
+func (o *Official) SetDefaultAccount(ctx context.Context, addr, user, account string) error {
+	body := map[string]any{"users": []officialDesc{{"name": user, "default": map[string]string{"account": account}}}}
+	return o.do(ctx, http.MethodPost, addr, "slurmdb", "users", nil, body, nil)
+}
+
+func (o *Official) GetLdapUsers(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error) {
+	return nil, 0, ErrUnsupported
+}
+
+func (o *Official) GetAdditionalGroupsOfUser(ctx context.Context, addr string, name string) ([]string, error) {
+	return nil, ErrUnsupported
+}
+
+func (o *Official) AuthLdapUser(ctx context.Context, addr, name, password string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) AddUser(ctx context.Context, addr string, user map[string]string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) UpdateLdapUser(ctx context.Context, addr, user string, attr map[string]string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) AddMemberForGroups(ctx context.Context, addr string, user string, groups []string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) DelLdapUser(ctx context.Context, addr, user string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) AddGroup(ctx context.Context, addr string, group map[string]string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) DelLdapGroup(ctx context.Context, addr, group string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) UpdateLdapGroup(ctx context.Context, addr, group string, attr map[string]string) error {
+	return ErrUnsupported
+}
+
+func (o *Official) GetLdapGroups(ctx context.Context, addr string, paging bool, page, pageSize int) ([]map[string]string, int, error) {
+	return nil, 0, ErrUnsupported
+}
+
+// Ping 探测官方 slurmrestd 是否可用, 并确认其与 slurmctld 的连接正常.
+func (o *Official) Ping(ctx context.Context, addr string) error {
+	var data struct {
+		Pings []struct {
+			Hostname string `json:"hostname"`
+			Pinged   string `json:"pinged"`
+		} `json:"pings"`
+	}
+	if err := o.get(ctx, addr, "slurm", "ping", nil, &data); err != nil {
+		return err
+	}
+	for _, p := range data.Pings {
+		if p.Pinged == "UP" {
+			return nil
+		}
+	}
+	return apperror.New(apperror.CODE_UPSTREAM_ERROR, "slurmrestd: no slurmctld responding")
+}
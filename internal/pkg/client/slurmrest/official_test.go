@@ -0,0 +1,54 @@
+package slurmrest
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+)
+
+func TestAccountingQuery(t *testing.T) {
+	exitCode := uint32(1)
+	tests := []struct {
+		name      string
+		filter    model.JobFilter
+		wantQuery url.Values
+		wantRest  model.JobFilter
+	}{
+		{"empty", model.JobFilter{}, url.Values{}, model.JobFilter{}},
+		{
+			"pushed down",
+			model.JobFilter{
+				Users: []string{"alice", "bob"}, Accounts: []string{"root"}, Partitions: []string{"cpu"}, QoS: []string{"normal"},
+				Nodes: []string{"cn1", "cn2", "cn3"}, Name: "train", ExitCode: &exitCode, StartFrom: 100, StartTo: 200, Sort: "-submit",
+			},
+			url.Values{
+				"users": {"alice,bob"}, "account": {"root"}, "partition": {"cpu"}, "qos": {"normal"},
+				"node": {"cn[1-3]"}, "job_name": {"train"}, "exit_code": {"1"}, "start_time": {"100"}, "end_time": {"201"},
+			},
+			model.JobFilter{StartFrom: 100, StartTo: 200, Sort: "-submit"},
+		},
+		{
+			"local only",
+			model.JobFilter{Name: "train-*", SubmitFrom: 100, EndTo: 300},
+			url.Values{},
+			model.JobFilter{Name: "train-*", SubmitFrom: 100, EndTo: 300},
+		},
+		{
+			"states without window",
+			model.JobFilter{States: []string{"COMPLETED", "FAILED"}, StartFrom: 100},
+			url.Values{"state": {"COMPLETED,FAILED"}},
+			model.JobFilter{StartFrom: 100},
+		},
+	}
+	for _, tt := range tests {
+		query, rest := accountingQuery(tt.filter)
+		if !reflect.DeepEqual(query, tt.wantQuery) {
+			t.Errorf("%s: query = %v, want %v", tt.name, query, tt.wantQuery)
+		}
+		if !reflect.DeepEqual(rest, tt.wantRest) {
+			t.Errorf("%s: rest = %+v, want %+v", tt.name, rest, tt.wantRest)
+		}
+	}
+}
@@ -0,0 +1,976 @@
+package slurmrest
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/slurmrest/model"
+	"csjk-bk/internal/pkg/common/slurm"
+)
+
+// 官方 slurmrestd(v0.0.40+) 请求及响应中用到的字段, 其余字段忽略.
+
+// officialNumber 官方 slurmrestd 中可能未设置或为无穷大的数值, 如 {"set": true, "infinite": false, "number": 10}.
+// 同时兼容直接返回数值的字段.
+type officialNumber struct {
+	Set      bool    `json:"set"`
+	Infinite bool    `json:"infinite"`
+	Number   float64 `json:"number"`
+}
+
+func (n *officialNumber) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '{' {
+		type raw officialNumber
+		return json.Unmarshal(b, (*raw)(n))
+	}
+	if string(b) == "null" {
+		return nil
+	}
+	n.Set = true
+	return json.Unmarshal(b, &n.Number)
+}
+
+// value 返回数值, 未设置或无穷大时返回 0.
+func (n officialNumber) value() int64 {
+	if !n.Set || n.Infinite {
+		return 0
+	}
+	return int64(n.Number)
+}
+
+// limit 返回限制值, 未设置或无穷大(不限制)时返回 -1, 与 slurmdbd 数据库中的表示一致.
+func (n officialNumber) limit() int32 {
+	if !n.Set || n.Infinite {
+		return -1
+	}
+	return int32(n.Number)
+}
+
+// String 按 scontrol 的格式输出: 无穷大为 UNLIMITED, 未设置为空字符串.
+func (n officialNumber) String() string {
+	switch {
+	case n.Infinite:
+		return "UNLIMITED"
+	case !n.Set:
+		return ""
+	default:
+		return strconv.FormatInt(int64(n.Number), 10)
+	}
+}
+
+// officialStrings 兼容字符串及字符串数组两种格式, 如作业步状态.
+type officialStrings []string
+
+func (s *officialStrings) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var v string
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*s = officialStrings{v}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(s))
+}
+
+type officialTres struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	ID    int64  `json:"id"`
+	Count int64  `json:"count"`
+}
+
+// tresString 将 TRES 列表转换为 slurmdbd 数据库中的格式, 如 1=4,2=4096; 缺少 ID 时使用 type/name=count.
+func tresString(list []officialTres) string {
+	parts := make([]string, 0, len(list))
+	for _, t := range list {
+		key := strconv.FormatInt(t.ID, 10)
+		if t.ID <= 0 {
+			key = t.Type
+			if t.Name != "" {
+				key += "/" + t.Name
+			}
+		}
+		parts = append(parts, key+"="+strconv.FormatInt(t.Count, 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+type officialError struct {
+	Description string `json:"description"`
+	Error       string `json:"error"`
+}
+
+type officialNode struct {
+	Name       string   `json:"name"`
+	State      []string `json:"state"`
+	Partitions []string `json:"partitions"`
+	RealMemory int64    `json:"real_memory"`
+	CPUs       int64    `json:"cpus"`
+	Sockets    int64    `json:"sockets"`
+	Cores      int64    `json:"cores"`
+	Threads    int64    `json:"threads"`
+	Gres       string   `json:"gres"`
+}
+
+func (n officialNode) toModel() *model.Node {
+	return &model.Node{
+		Name:      n.Name,
+		State:     strings.Join(n.State, "+"),
+		Partition: n.Partitions,
+		Memory:    n.RealMemory,
+		CPUs:      n.CPUs,
+		Socket:    n.Sockets,
+		Cores:     n.Cores,
+		Threads:   n.Threads,
+		GPU:       n.Gres,
+	}
+}
+
+type officialJob struct {
+	JobID       int64          `json:"job_id"`
+	JobState    []string       `json:"job_state"`
+	UserName    string         `json:"user_name"`
+	Account     string         `json:"account"`
+	CPUs        officialNumber `json:"cpus"`
+	Nodes       string         `json:"nodes"`
+	Partition   string         `json:"partition"`
+	QoS         string         `json:"qos"`
+	StateReason string         `json:"state_reason"`
+	Priority    officialNumber `json:"priority"`
+	SubmitTime  officialNumber `json:"submit_time"`
+	StartTime   officialNumber `json:"start_time"`
+	EndTime     officialNumber `json:"end_time"`
+}
+
+func (j officialJob) toModel() model.JobInScheduling {
+	return model.JobInScheduling{
+		Jobid:      strconv.FormatInt(j.JobID, 10),
+		State:      slurm.PrintJobStateString(slurm.ParseJobState(j.JobState)),
+		User:       j.UserName,
+		Account:    j.Account,
+		CPUs:       j.CPUs.String(),
+		Nodelist:   j.Nodes,
+		Partition:  j.Partition,
+		QoS:        j.QoS,
+		Reason:     j.StateReason,
+		Priority:   j.Priority.value(),
+		TimeSubmit: j.SubmitTime.value(),
+		TimeStart:  j.StartTime.value(),
+		TimeEnd:    j.EndTime.value(),
+	}
+}
+
+type officialAcctJob struct {
+	JobID           int64          `json:"job_id"`
+	Name            string         `json:"name"`
+	User            string         `json:"user"`
+	Account         string         `json:"account"`
+	Partition       string         `json:"partition"`
+	QoS             string         `json:"qos"`
+	Nodes           string         `json:"nodes"`
+	AllocationNodes officialNumber `json:"allocation_nodes"`
+	Constraints     string         `json:"constraints"`
+	State           struct {
+		Current officialStrings `json:"current"`
+	} `json:"state"`
+	Time struct {
+		Submission officialNumber `json:"submission"`
+		Eligible   officialNumber `json:"eligible"`
+		Start      officialNumber `json:"start"`
+		End        officialNumber `json:"end"`
+		Suspended  officialNumber `json:"suspended"`
+		Limit      officialNumber `json:"limit"`
+	} `json:"time"`
+	ExitCode struct {
+		ReturnCode officialNumber `json:"return_code"`
+	} `json:"exit_code"`
+	DerivedExitCode struct {
+		ReturnCode officialNumber `json:"return_code"`
+	} `json:"derived_exit_code"`
+	Priority    officialNumber `json:"priority"`
+	Association struct {
+		ID officialNumber `json:"id"`
+	} `json:"association"`
+	Array struct {
+		JobID  officialNumber `json:"job_id"`
+		TaskID officialNumber `json:"task_id"`
+	} `json:"array"`
+	Tres struct {
+		Allocated []officialTres `json:"allocated"`
+		Requested []officialTres `json:"requested"`
+	} `json:"tres"`
+	WorkingDirectory string `json:"working_directory"`
+	SubmitLine       string `json:"submit_line"`
+	StdIn            string `json:"stdin"`
+	StdOut           string `json:"stdout"`
+	StdErr           string `json:"stderr"`
+	Wckey            struct {
+		Wckey string `json:"wckey"`
+	} `json:"wckey"`
+	Steps []officialStep `json:"steps"`
+}
+
+// toModel 转换为 model.Job. 官方接口仅返回 QoS 名称, qosIDs 为 QoS 名称到 ID 的映射;
+// 用户及组仅返回名称, IDUser/IDGroup 为 0.
+func (j officialAcctJob) toModel(qosIDs map[string]int32) model.Job {
+	return model.Job{
+		Account:       j.Account,
+		Constraints:   j.Constraints,
+		DerivedEC:     uint32(j.DerivedExitCode.ReturnCode.value()),
+		ExitCode:      uint32(j.ExitCode.ReturnCode.value()),
+		JobName:       j.Name,
+		IDAssoc:       uint32(j.Association.ID.value()),
+		IDArrayJob:    uint32(j.Array.JobID.value()),
+		IDArrayTask:   uint32(j.Array.TaskID.value()),
+		IDJob:         uint32(j.JobID),
+		IDQOS:         uint32(qosIDs[j.QoS]),
+		Nodelist:      j.Nodes,
+		NodesAlloc:    uint32(j.AllocationNodes.value()),
+		Partition:     j.Partition,
+		Priority:      uint32(j.Priority.value()),
+		State:         slurm.ParseJobState(j.State.Current),
+		TimeLimit:     uint32(j.Time.Limit.value()),
+		TimeSubmit:    uint64(j.Time.Submission.value()),
+		TimeEligible:  uint64(j.Time.Eligible.value()),
+		TimeStart:     uint64(j.Time.Start.value()),
+		TimeEnd:       uint64(j.Time.End.value()),
+		TimeSuspended: uint64(j.Time.Suspended.value()),
+		Wckey:         j.Wckey.Wckey,
+		WorkDir:       j.WorkingDirectory,
+		StdErr:        j.StdErr,
+		StdIn:         j.StdIn,
+		StdOut:        j.StdOut,
+		SubmitLine:    j.SubmitLine,
+		TresAlloc:     tresString(j.Tres.Allocated),
+		TresReq:       tresString(j.Tres.Requested),
+	}
+}
+
+type officialStep struct {
+	Step struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"step"`
+	State officialStrings `json:"state"`
+	Nodes struct {
+		Count officialNumber `json:"count"`
+		Range string         `json:"range"`
+	} `json:"nodes"`
+	Tasks struct {
+		Count officialNumber `json:"count"`
+	} `json:"tasks"`
+	Time struct {
+		Start     officialNumber `json:"start"`
+		End       officialNumber `json:"end"`
+		Suspended officialNumber `json:"suspended"`
+	} `json:"time"`
+	ExitCode struct {
+		ReturnCode officialNumber `json:"return_code"`
+	} `json:"exit_code"`
+	Tres struct {
+		Requested officialTresUsage `json:"requested"` // 输入方向用量, 对应 TRESUsageIn*
+		Consumed  officialTresUsage `json:"consumed"`  // 输出方向用量, 对应 TRESUsageOut*
+		Allocated []officialTres    `json:"allocated"`
+	} `json:"tres"`
+}
+
+// officialTresUsage 作业步各任务的 TRES 用量统计, 数值与 slurmdbd 中存储的一致(cpu 为毫秒, 容量为字节).
+type officialTresUsage struct {
+	Max     []officialTres `json:"max"`
+	Min     []officialTres `json:"min"`
+	Average []officialTres `json:"average"`
+	Total   []officialTres `json:"total"`
+}
+
+// 特殊作业步 ID, 与 slurm.h 中 SLURM_*_CONT 等常量一致(按 int32 解释).
+var officialStepIDs = map[string]int32{
+	"pending":     -3,
+	"extern":      -4,
+	"batch":       -5,
+	"interactive": -6,
+}
+
+// stepID 解析作业步 ID, 如 "123.0" 为 0, "123.batch" 为 -5.
+func (s officialStep) stepID() int32 {
+	id := s.Step.ID
+	if i := strings.LastIndexByte(id, '.'); i >= 0 {
+		id = id[i+1:]
+	}
+	if v, ok := officialStepIDs[id]; ok {
+		return v
+	}
+	n, _ := strconv.ParseInt(id, 10, 32)
+	return int32(n)
+}
+
+func (s officialStep) toModel() model.Step {
+	return model.Step{
+		ExitCode:      int32(s.ExitCode.ReturnCode.value()),
+		IDStep:        s.stepID(),
+		Nodelist:      s.Nodes.Range,
+		NodesAlloc:    uint32(s.Nodes.Count.value()),
+		State:         slurm.ParseJobState(s.State),
+		StepName:      s.Step.Name,
+		TaskCnt:       uint32(s.Tasks.Count.value()),
+		TimeStart:     uint64(s.Time.Start.value()),
+		TimeEnd:       uint64(s.Time.End.value()),
+		TimeSuspended: uint64(s.Time.Suspended.value()),
+		TRESAlloc:     tresString(s.Tres.Allocated),
+
+		TRESUsageInMax:  tresString(s.Tres.Requested.Max),
+		TRESUsageInMin:  tresString(s.Tres.Requested.Min),
+		TRESUsageInAve:  tresString(s.Tres.Requested.Average),
+		TRESUsageInTot:  tresString(s.Tres.Requested.Total),
+		TRESUsageOutMax: tresString(s.Tres.Consumed.Max),
+		TRESUsageOutMin: tresString(s.Tres.Consumed.Min),
+		TRESUsageOutAve: tresString(s.Tres.Consumed.Average),
+		TRESUsageOutTot: tresString(s.Tres.Consumed.Total),
+	}
+}
+
+type officialPartition struct {
+	Name  string `json:"name"`
+	Nodes struct {
+		AllowedAllocation string `json:"allowed_allocation"`
+		Configured        string `json:"configured"`
+		Total             int64  `json:"total"`
+	} `json:"nodes"`
+	Accounts struct {
+		Allowed string `json:"allowed"`
+		Deny    string `json:"deny"`
+	} `json:"accounts"`
+	Groups struct {
+		Allowed string `json:"allowed"`
+	} `json:"groups"`
+	CPUs struct {
+		Total int64 `json:"total"`
+	} `json:"cpus"`
+	Defaults struct {
+		MemoryPerCPU  officialNumber `json:"partition_memory_per_cpu"`
+		MemoryPerNode officialNumber `json:"partition_memory_per_node"`
+	} `json:"defaults"`
+	Maximums struct {
+		CPUsPerNode   officialNumber `json:"cpus_per_node"`
+		MemoryPerCPU  officialNumber `json:"partition_memory_per_cpu"`
+		MemoryPerNode officialNumber `json:"partition_memory_per_node"`
+		Time          officialNumber `json:"time"`
+	} `json:"maximums"`
+	Partition struct {
+		State []string `json:"state"`
+	} `json:"partition"`
+	Alternate   string         `json:"alternate"`
+	SuspendTime officialNumber `json:"suspend_time"`
+	Timeouts    struct {
+		Suspend officialNumber `json:"suspend"`
+	} `json:"timeouts"`
+}
+
+// toMap 转换为与自定义 slurmrest 服务一致的 scontrol show partition 键值格式, 官方接口未返回的键缺省.
+func (p officialPartition) toMap() map[string]string {
+	return map[string]string{
+		"PartitionName":  p.Name,
+		"State":          strings.Join(p.Partition.State, ","),
+		"Nodes":          p.Nodes.Configured,
+		"TotalNodes":     strconv.FormatInt(p.Nodes.Total, 10),
+		"TotalCPUs":      strconv.FormatInt(p.CPUs.Total, 10),
+		"AllocNodes":     p.Nodes.AllowedAllocation,
+		"AllowAccounts":  p.Accounts.Allowed,
+		"DenyAccounts":   p.Accounts.Deny,
+		"AllowGroups":    p.Groups.Allowed,
+		"Alternate":      p.Alternate,
+		"MaxTime":        p.Maximums.Time.String(),
+		"MaxCPUsPerNode": p.Maximums.CPUsPerNode.String(),
+		"DefMemPerCPU":   p.Defaults.MemoryPerCPU.String(),
+		"DefMemPerNode":  p.Defaults.MemoryPerNode.String(),
+		"MaxMemPerCPU":   p.Maximums.MemoryPerCPU.String(),
+		"MaxMemPerNode":  p.Maximums.MemoryPerNode.String(),
+		"SuspendTime":    p.SuspendTime.String(),
+		"SuspendTimeout": p.Timeouts.Suspend.String(),
+	}
+}
+
+type officialPerAccountUser struct {
+	Account officialNumber `json:"account"`
+	User    officialNumber `json:"user"`
+}
+
+type officialTresPer struct {
+	Account []officialTres `json:"account"`
+	Job     []officialTres `json:"job"`
+	Node    []officialTres `json:"node"`
+	User    []officialTres `json:"user"`
+	QoS     []officialTres `json:"qos"`
+}
+
+type officialQoS struct {
+	ID          int32          `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Flags       []string       `json:"flags"`
+	Priority    officialNumber `json:"priority"`
+	UsageFactor officialNumber `json:"usage_factor"`
+	UsageThres  officialNumber `json:"usage_threshold"`
+	Preempt     struct {
+		List       []string       `json:"list"`
+		Mode       []string       `json:"mode"`
+		ExemptTime officialNumber `json:"exempt_time"`
+	} `json:"preempt"`
+	Limits struct {
+		GraceTime officialNumber `json:"grace_time"`
+		Factor    officialNumber `json:"factor"`
+		Max       struct {
+			ActiveJobs struct {
+				Accruing officialNumber `json:"accruing"`
+				Count    officialNumber `json:"count"`
+			} `json:"active_jobs"`
+			Jobs struct {
+				ActiveJobs struct {
+					Per officialPerAccountUser `json:"per"`
+				} `json:"active_jobs"`
+				Per   officialPerAccountUser `json:"per"`
+				Count officialNumber         `json:"count"`
+			} `json:"jobs"`
+			Accruing struct {
+				Per officialPerAccountUser `json:"per"`
+			} `json:"accruing"`
+			Tres struct {
+				Total   []officialTres `json:"total"`
+				Minutes struct {
+					Total []officialTres  `json:"total"`
+					Per   officialTresPer `json:"per"`
+				} `json:"minutes"`
+				Per officialTresPer `json:"per"`
+			} `json:"tres"`
+			WallClock struct {
+				Per struct {
+					QoS officialNumber `json:"qos"`
+					Job officialNumber `json:"job"`
+				} `json:"per"`
+			} `json:"wall_clock"`
+		} `json:"max"`
+		Min struct {
+			PriorityThreshold officialNumber `json:"priority_threshold"`
+			Tres              struct {
+				Per struct {
+					Job []officialTres `json:"job"`
+				} `json:"per"`
+			} `json:"tres"`
+		} `json:"min"`
+	} `json:"limits"`
+}
+
+func (q officialQoS) toModel() model.QoS {
+	l := q.Limits
+	// 未知的标志及抢占模式(较新版本新增)忽略
+	var flags uint32
+	for _, f := range q.Flags {
+		v, _ := slurm.ParseQoSFlags(f)
+		flags |= v
+	}
+	var preemptMode uint16
+	for _, m := range q.Preempt.Mode {
+		v, _ := slurm.ParsePreemptMode(m)
+		preemptMode |= v
+	}
+	return model.QoS{
+		ID:                    q.ID,
+		Name:                  q.Name,
+		Description:           q.Description,
+		Flags:                 flags,
+		GraceTime:             uint32(l.GraceTime.value()),
+		MaxJobsPA:             l.Max.Jobs.ActiveJobs.Per.Account.limit(),
+		MaxJobsPerUser:        l.Max.Jobs.ActiveJobs.Per.User.limit(),
+		MaxJobsAccruePA:       l.Max.Accruing.Per.Account.limit(),
+		MaxJobsAccruePU:       l.Max.Accruing.Per.User.limit(),
+		MinPrioThresh:         l.Min.PriorityThreshold.limit(),
+		MaxSubmitJobsPA:       l.Max.Jobs.Per.Account.limit(),
+		MaxSubmitJobsPerUser:  l.Max.Jobs.Per.User.limit(),
+		MaxTresPA:             tresString(l.Max.Tres.Per.Account),
+		MaxTresPJ:             tresString(l.Max.Tres.Per.Job),
+		MaxTresPN:             tresString(l.Max.Tres.Per.Node),
+		MaxTresPU:             tresString(l.Max.Tres.Per.User),
+		MaxTresMinsPJ:         tresString(l.Max.Tres.Minutes.Per.Job),
+		MaxTresRunMinsPA:      tresString(l.Max.Tres.Minutes.Per.Account),
+		MaxTresRunMinsPU:      tresString(l.Max.Tres.Minutes.Per.User),
+		MinTresPJ:             tresString(l.Min.Tres.Per.Job),
+		MaxWallDurationPerJob: l.Max.WallClock.Per.Job.limit(),
+		GrpJobs:               l.Max.ActiveJobs.Count.limit(),
+		GrpJobsAccrue:         l.Max.ActiveJobs.Accruing.limit(),
+		GrpSubmitJobs:         l.Max.Jobs.Count.limit(),
+		GrpTres:               tresString(l.Max.Tres.Total),
+		GrpTresMins:           tresString(l.Max.Tres.Minutes.Total),
+		GrpTresRunMins:        tresString(l.Max.Tres.Minutes.Per.QoS),
+		GrpWall:               l.Max.WallClock.Per.QoS.limit(),
+		Preempt:               strings.Join(q.Preempt.List, ","),
+		PreemptMode:           int32(preemptMode),
+		PreemptExemptTime:     uint32(q.Preempt.ExemptTime.value()),
+		Priority:              uint32(q.Priority.value()),
+		UsageFactor:           q.UsageFactor.Number,
+		UsageThres:            q.UsageThres.Number,
+		LimitFactor:           l.Factor.Number,
+	}
+}
+
+type officialAccount struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Organization string `json:"organization"`
+}
+
+func (a officialAccount) toModel() model.Account {
+	return model.Account{Name: a.Name, Description: a.Description, Organization: a.Organization}
+}
+
+type officialAssociation struct {
+	ID            int64          `json:"id"`
+	Cluster       string         `json:"cluster"`
+	Account       string         `json:"account"`
+	User          string         `json:"user"`
+	Partition     string         `json:"partition"`
+	ParentAccount string         `json:"parent_account"`
+	Comment       string         `json:"comment"`
+	IsDefault     bool           `json:"is_default"`
+	SharesRaw     officialNumber `json:"shares_raw"`
+	Priority      officialNumber `json:"priority"`
+	QoS           []string       `json:"qos"`
+	Default       struct {
+		QoS string `json:"qos"`
+	} `json:"default"`
+	Max struct {
+		Jobs struct {
+			Per struct {
+				Count     officialNumber `json:"count"`
+				Accruing  officialNumber `json:"accruing"`
+				Submitted officialNumber `json:"submitted"`
+				WallClock officialNumber `json:"wall_clock"`
+			} `json:"per"`
+			Active   officialNumber `json:"active"`
+			Accruing officialNumber `json:"accruing"`
+			Total    officialNumber `json:"total"`
+		} `json:"jobs"`
+		Tres struct {
+			Total []officialTres `json:"total"`
+			Group struct {
+				Minutes []officialTres `json:"minutes"`
+				Active  []officialTres `json:"active"`
+			} `json:"group"`
+			Minutes struct {
+				Per struct {
+					Job []officialTres `json:"job"`
+				} `json:"per"`
+			} `json:"minutes"`
+			Per struct {
+				Job  []officialTres `json:"job"`
+				Node []officialTres `json:"node"`
+			} `json:"per"`
+		} `json:"tres"`
+		Per struct {
+			Account struct {
+				WallClock officialNumber `json:"wall_clock"`
+			} `json:"account"`
+		} `json:"per"`
+	} `json:"max"`
+	Min struct {
+		PriorityThreshold officialNumber `json:"priority_threshold"`
+	} `json:"min"`
+}
+
+// toModel 转换为 model.AssociationItem. qosIDs 为 QoS 名称到 ID 的映射, QOS 字段与数据库一致为 ",1,2," 格式.
+func (a officialAssociation) toModel(qosIDs map[string]int32) model.AssociationItem {
+	qos := ""
+	if len(a.QoS) > 0 {
+		ids := make([]string, 0, len(a.QoS))
+		for _, name := range a.QoS {
+			if id, ok := qosIDs[name]; ok {
+				ids = append(ids, strconv.Itoa(int(id)))
+			}
+		}
+		qos = "," + strings.Join(ids, ",") + ","
+	}
+	var isDef int8
+	if a.IsDefault {
+		isDef = 1
+	}
+	defQoS := int32(0)
+	if id, ok := qosIDs[a.Default.QoS]; ok {
+		defQoS = id
+	}
+	return model.AssociationItem{
+		Comment:        a.Comment,
+		IsDef:          isDef,
+		IDAssoc:        uint32(a.ID),
+		User:           a.User,
+		Acct:           a.Account,
+		Partition:      a.Partition,
+		ParentAcct:     a.ParentAccount,
+		Shares:         int32(a.SharesRaw.value()),
+		MaxJobs:        a.Max.Jobs.Per.Count.limit(),
+		MaxJobsAccrue:  a.Max.Jobs.Per.Accruing.limit(),
+		MinPrioThresh:  a.Min.PriorityThreshold.limit(),
+		MaxSubmitJobs:  a.Max.Jobs.Per.Submitted.limit(),
+		MaxTresPJ:      tresString(a.Max.Tres.Per.Job),
+		MaxTresPN:      tresString(a.Max.Tres.Per.Node),
+		MaxTresMinsPJ:  tresString(a.Max.Tres.Minutes.Per.Job),
+		MaxWallPJ:      a.Max.Jobs.Per.WallClock.limit(),
+		GrpJobs:        a.Max.Jobs.Active.limit(),
+		GrpJobsAccrue:  a.Max.Jobs.Accruing.limit(),
+		GrpSubmitJobs:  a.Max.Jobs.Total.limit(),
+		GrpTres:        tresString(a.Max.Tres.Total),
+		GrpTresMins:    tresString(a.Max.Tres.Group.Minutes),
+		GrpTresRunMins: tresString(a.Max.Tres.Group.Active),
+		GrpWall:        a.Max.Per.Account.WallClock.limit(),
+		Priority:       uint32(a.Priority.value()),
+		DefQosID:       defQoS,
+		QOS:            qos,
+	}
+}
+
+// slurmdbd 中用户管理级别, 与 user_table.admin_level 一致.
+var officialAdminLevels = map[string]int{
+	"None":          1,
+	"Operator":      2,
+	"Administrator": 3,
+}
+
+type officialUser struct {
+	Name               string          `json:"name"`
+	AdministratorLevel officialStrings `json:"administrator_level"`
+}
+
+func (u officialUser) adminLevel() int {
+	for _, l := range u.AdministratorLevel {
+		if v, ok := officialAdminLevels[l]; ok {
+			return v
+		}
+	}
+	return 0
+}
+
+// officialJobDesc 作业提交请求中的作业描述(job_desc_msg).
+type officialJobDesc struct {
+	Name             string          `json:"name,omitempty"`
+	Partition        string          `json:"partition,omitempty"`
+	QoS              string          `json:"qos,omitempty"`
+	Account          string          `json:"account,omitempty"`
+	Nodes            string          `json:"nodes,omitempty"`
+	Tasks            uint32          `json:"tasks,omitempty"`
+	CPUsPerTask      uint32          `json:"cpus_per_task,omitempty"`
+	TimeLimit        *officialNumber `json:"time_limit,omitempty"`
+	TresPerNode      string          `json:"tres_per_node,omitempty"`
+	Dependency       string          `json:"dependency,omitempty"`
+	Array            string          `json:"array,omitempty"`
+	CurrentDirectory string          `json:"current_directory,omitempty"`
+	Environment      []string        `json:"environment"`
+}
+
+func newOfficialJobDesc(job model.JobSubmission) officialJobDesc {
+	d := officialJobDesc{
+		Name:             job.Name,
+		Partition:        job.Partition,
+		QoS:              job.QoS,
+		Account:          job.Account,
+		Nodes:            job.Nodes,
+		Tasks:            job.Tasks,
+		CPUsPerTask:      job.CPUsPerTask,
+		Dependency:       job.Dependency,
+		Array:            job.Array,
+		CurrentDirectory: job.WorkDir,
+	}
+	if job.TimeLimit > 0 {
+		d.TimeLimit = &officialNumber{Set: true, Number: float64(job.TimeLimit)}
+	}
+	if job.Gres != "" {
+		d.TresPerNode = "gres/" + job.Gres
+	}
+	// 官方接口要求 environment 非空
+	d.Environment = make([]string, 0, len(job.Environment)+1)
+	for k, v := range job.Environment {
+		d.Environment = append(d.Environment, k+"="+v)
+	}
+	if len(d.Environment) == 0 {
+		d.Environment = append(d.Environment, "PATH=/bin:/usr/bin:/usr/local/bin")
+	}
+	slices.Sort(d.Environment)
+	return d
+}
+
+// RESERVATION_TIME_LAYOUT 预约开始/结束时间的输出格式, 与 scontrol show reservation 一致.
+const RESERVATION_TIME_LAYOUT = "2006-01-02T15:04:05"
+
+type officialReservation struct {
+	Name      string         `json:"name"`
+	Partition string         `json:"partition"`
+	StartTime officialNumber `json:"start_time"`
+	EndTime   officialNumber `json:"end_time"`
+	NodeList  string         `json:"node_list"`
+	NodeCount officialNumber `json:"node_count"`
+	Flags     []string       `json:"flags"`
+	Users     string         `json:"users"`
+	Accounts  string         `json:"accounts"`
+}
+
+func (r officialReservation) toModel() model.Reservation {
+	resv := model.Reservation{
+		Name:      r.Name,
+		Partition: r.Partition,
+		Nodes:     r.NodeList,
+		NodeCnt:   int(r.NodeCount.value()),
+		Flags:     strings.Join(r.Flags, ","),
+		Users:     r.Users,
+		Accounts:  r.Accounts,
+	}
+	start, end := r.StartTime.value(), r.EndTime.value()
+	if start > 0 {
+		resv.StartTime = time.Unix(start, 0).Format(RESERVATION_TIME_LAYOUT)
+	}
+	switch {
+	case r.EndTime.Infinite:
+		resv.Duration = "UNLIMITED"
+	case end > 0:
+		resv.EndTime = time.Unix(end, 0).Format(RESERVATION_TIME_LAYOUT)
+		if start > 0 && end >= start {
+			resv.Duration = strconv.FormatInt((end-start)/60, 10)
+		}
+	}
+	return resv
+}
+
+// officialReservationDesc 创建/更新预约请求中的预约描述(reservation_desc_msg, v0.0.43+).
+type officialReservationDesc struct {
+	Name      string          `json:"name"`
+	Partition string          `json:"partition,omitempty"`
+	StartTime *officialNumber `json:"start_time,omitempty"`
+	EndTime   *officialNumber `json:"end_time,omitempty"`
+	Duration  *officialNumber `json:"duration,omitempty"`
+	NodeList  []string        `json:"node_list,omitempty"`
+	NodeCount *officialNumber `json:"node_count,omitempty"`
+	Flags     []string        `json:"flags,omitempty"`
+	Users     []string        `json:"users,omitempty"`
+	Accounts  []string        `json:"accounts,omitempty"`
+}
+
+// newOfficialReservationDesc 将 scontrol 格式的时间及时长转换为官方接口使用的 Unix 时间戳及分钟数.
+func newOfficialReservationDesc(resv model.Reservation, now time.Time) (officialReservationDesc, error) {
+	d := officialReservationDesc{
+		Name:      resv.Name,
+		Partition: resv.Partition,
+		Flags:     splitList(resv.Flags),
+		Users:     splitList(resv.Users),
+		Accounts:  splitList(resv.Accounts),
+	}
+	if resv.StartTime != "" {
+		t, err := slurm.ParseTime(resv.StartTime, now)
+		if err != nil {
+			return d, apperror.Wrap(apperror.CODE_VALIDATION_FAILED, err, "invalid reservation start time")
+		}
+		d.StartTime = &officialNumber{Set: true, Number: float64(t.Unix())}
+	}
+	if resv.EndTime != "" {
+		t, err := slurm.ParseTime(resv.EndTime, now)
+		if err != nil {
+			return d, apperror.Wrap(apperror.CODE_VALIDATION_FAILED, err, "invalid reservation end time")
+		}
+		d.EndTime = &officialNumber{Set: true, Number: float64(t.Unix())}
+	}
+	if resv.Duration != "" {
+		dur, err := slurm.ParseDuration(resv.Duration)
+		if err != nil {
+			return d, apperror.Wrap(apperror.CODE_VALIDATION_FAILED, err, "invalid reservation duration")
+		}
+		if dur == slurm.UNLIMITED {
+			d.Duration = &officialNumber{Set: true, Infinite: true}
+		} else {
+			d.Duration = &officialNumber{Set: true, Number: float64(dur / time.Minute)}
+		}
+	}
+	if resv.Nodes != "" {
+		d.NodeList = []string{resv.Nodes}
+	}
+	if resv.NodeCnt > 0 {
+		d.NodeCount = &officialNumber{Set: true, Number: float64(resv.NodeCnt)}
+	}
+	return d, nil
+}
+
+// splitList 拆分逗号分隔的列表, 忽略空项.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// officialDesc 官方接口创建或修改 QoS, 关联等的请求项, 仅包含需要修改的属性, 结构与对应的响应类型(officialQoS 等)一致.
+type officialDesc map[string]any
+
+// set 设置 path(以 / 分隔, 如 limits/max/jobs/per/account)对应的属性.
+func (d officialDesc) set(path string, v any) {
+	m := map[string]any(d)
+	keys := strings.Split(path, "/")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = v
+}
+
+// officialLimit 将限制值转换为官方接口的数值, -1(清除限制)对应无穷大.
+func officialLimit(v float64) officialNumber {
+	if v == -1 {
+		return officialNumber{Set: true, Infinite: true}
+	}
+	return officialNumber{Set: true, Number: v}
+}
+
+// officialTresList 将 TRES 字符串(type[/name]=count 或 id=count, count 为整数)转换为官方接口的 TRES 列表.
+func officialTresList(s string) ([]officialTres, error) {
+	list := make([]officialTres, 0)
+	for _, kv := range splitList(s) {
+		k, v, ok := strings.Cut(kv, "=")
+		count, err := strconv.ParseInt(v, 10, 64)
+		if !ok || err != nil {
+			return nil, apperror.New(apperror.CODE_VALIDATION_FAILED, "invalid tres: "+kv)
+		}
+		t := officialTres{Count: count}
+		if id, err := strconv.ParseInt(k, 10, 64); err == nil {
+			t.ID = id
+		} else {
+			t.Type, t.Name, _ = strings.Cut(k, "/")
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+// newOfficialQoSDesc 将 model.QoSWrite 转换为官方接口的请求项, 标志及抢占模式转换为官方接口中的名称.
+func newOfficialQoSDesc(q model.QoSWrite) (officialDesc, error) {
+	d := officialDesc{"name": q.Name}
+	if q.Description != nil {
+		d.set("description", *q.Description)
+	}
+	if q.Flags != nil {
+		flags, err := slurm.ParseQoSFlags(*q.Flags)
+		if err != nil {
+			return nil, apperror.Wrap(apperror.CODE_VALIDATION_FAILED, err, "invalid qos flags")
+		}
+		d.set("flags", slurm.QoSFlagNames(flags, true))
+	}
+	if q.Priority != nil {
+		d.set("priority", officialLimit(float64(*q.Priority)))
+	}
+	if q.UsageFactor != nil {
+		d.set("usage_factor", officialLimit(*q.UsageFactor))
+	}
+	if q.UsageThres != nil {
+		d.set("usage_threshold", officialLimit(*q.UsageThres))
+	}
+	if q.Preempt != nil {
+		// 空列表表示清除
+		d.set("preempt/list", append([]string{}, splitList(*q.Preempt)...))
+	}
+	if q.PreemptMode != nil {
+		mode, err := slurm.ParsePreemptMode(*q.PreemptMode)
+		if err != nil {
+			return nil, apperror.Wrap(apperror.CODE_VALIDATION_FAILED, err, "invalid preempt mode")
+		}
+		names := []string{"DISABLED"}
+		if mode != slurm.PREEMPT_MODE_OFF {
+			names = strings.Split(slurm.PreemptModeString(mode), ",")
+		}
+		d.set("preempt/mode", names)
+	}
+
+	limits := []struct {
+		path string
+		v    *int32
+	}{
+		{"preempt/exempt_time", q.PreemptExemptTime},
+		{"limits/grace_time", q.GraceTime},
+		{"limits/max/jobs/active_jobs/per/account", q.MaxJobsPA},
+		{"limits/max/jobs/active_jobs/per/user", q.MaxJobsPerUser},
+		{"limits/max/jobs/per/account", q.MaxSubmitJobsPA},
+		{"limits/max/jobs/per/user", q.MaxSubmitJobsPerUser},
+		{"limits/max/jobs/count", q.GrpSubmitJobs},
+		{"limits/max/active_jobs/count", q.GrpJobs},
+		{"limits/max/wall_clock/per/job", q.MaxWallDurationPerJob},
+		{"limits/max/wall_clock/per/qos", q.GrpWall},
+	}
+	for _, l := range limits {
+		if l.v != nil {
+			d.set(l.path, officialLimit(float64(*l.v)))
+		}
+	}
+
+	tres := []struct {
+		path string
+		v    string
+	}{
+		{"limits/max/tres/total", q.GrpTres},
+		{"limits/max/tres/minutes/total", q.GrpTresMins},
+		{"limits/max/tres/minutes/per/qos", q.GrpTresRunMins},
+		{"limits/max/tres/minutes/per/job", q.MaxTresMinsPJ},
+		{"limits/max/tres/minutes/per/account", q.MaxTresRunMinsPA},
+		{"limits/max/tres/minutes/per/user", q.MaxTresRunMinsPU},
+		{"limits/max/tres/per/account", q.MaxTresPA},
+		{"limits/max/tres/per/job", q.MaxTresPJ},
+		{"limits/max/tres/per/node", q.MaxTresPN},
+		{"limits/max/tres/per/user", q.MaxTresPU},
+		{"limits/min/tres/per/job", q.MinTresPJ},
+	}
+	for _, t := range tres {
+		if t.v == "" {
+			continue
+		}
+		list, err := officialTresList(t.v)
+		if err != nil {
+			return nil, err
+		}
+		d.set(t.path, list)
+	}
+	return d, nil
+}
+
+// newOfficialAssociationDesc 将 model.AssociationWrite 转换为官方接口的请求项, cluster 为关联所属的集群(Slurm 中的集群名称).
+func newOfficialAssociationDesc(a model.AssociationWrite, cluster string) (officialDesc, error) {
+	d := officialDesc{"account": a.Account, "user": a.User, "partition": a.Partition, "cluster": cluster}
+	if a.GrpTres != "" {
+		list, err := officialTresList(a.GrpTres)
+		if err != nil {
+			return nil, err
+		}
+		d.set("max/tres/total", list)
+	}
+	if a.MaxJobs != nil {
+		d.set("max/jobs/per/count", officialLimit(float64(*a.MaxJobs)))
+	}
+	if a.MaxWallPJ != nil {
+		d.set("max/jobs/per/wall_clock", officialLimit(float64(*a.MaxWallPJ)))
+	}
+	if a.QOS != nil {
+		d.set("qos", append([]string{}, splitList(*a.QOS)...))
+	}
+	if a.DefQoS != nil {
+		d.set("default/qos", *a.DefQoS)
+	}
+	if a.Shares != nil {
+		// -1 恢复默认值, 与 sacctmgr 一致
+		shares := float64(*a.Shares)
+		if shares < 0 {
+			shares = 1
+		}
+		d.set("shares_raw", officialNumber{Set: true, Number: shares})
+	}
+	return d, nil
+}
@@ -0,0 +1,269 @@
+// Package transport 为访问上游服务(slurmrestd, KD Lustre, Alertmanager)的 HTTP 客户端提供统一的重试及熔断.
+// Transport 实现各客户端包中的 Doer 接口, 包装在 http.Client 之外:
+//   - 幂等请求(GET/HEAD)在网络错误或 502/503/504 时按指数退避(带随机抖动)重试;
+//   - 按上游地址(host:port)熔断: 连续失败达到阈值后熔断, 熔断期间请求直接返回 UPSTREAM_UNAVAILABLE,
+//     冷却时间结束后放行一个探测请求, 成功则恢复, 失败则继续熔断.
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+)
+
+// Doer 与各客户端包中的 Doer 接口一致.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Options 重试及熔断配置.
+type Options struct {
+	Retries          int           // 幂等请求失败后的最大重试次数, 0 表示不重试
+	Backoff          time.Duration // 首次重试前的等待时间, 之后每次翻倍
+	MaxBackoff       time.Duration // 重试等待时间上限
+	BreakerThreshold int           // 同一地址连续失败该次数后熔断, 0 表示不熔断
+	BreakerCooldown  time.Duration // 熔断持续时间
+}
+
+// State 熔断器状态.
+type State string
+
+const (
+	STATE_CLOSED    State = "closed"    // 正常
+	STATE_OPEN      State = "open"      // 熔断中, 请求直接失败
+	STATE_HALF_OPEN State = "half_open" // 冷却结束, 正在放行探测请求
+)
+
+// BreakerStatus 某上游地址的熔断器状态.
+type BreakerStatus struct {
+	Upstream  string    `json:"upstream"`   // 上游服务, 如 slurmrestd
+	Address   string    `json:"address"`    // 上游地址 host:port
+	State     State     `json:"state"`      // 熔断器状态
+	Failures  int       `json:"failures"`   // 连续失败次数
+	OpenedAt  time.Time `json:"opened_at"`  // 最近一次熔断时间, 未熔断过时为零值
+	LastError string    `json:"last_error"` // 最近一次失败原因
+}
+
+type breaker struct {
+	state     State
+	failures  int
+	openedAt  time.Time
+	probing   bool // 半开状态下探测请求是否在进行中
+	lastError string
+}
+
+// Transport 带重试及熔断的 Doer, 可被多个 goroutine 并发使用.
+type Transport struct {
+	upstream string
+	next     Doer
+	opts     Options
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+// New 创建 Transport. upstream 为上游服务名称, 用于错误信息及状态展示.
+func New(upstream string, next Doer, opts Options) *Transport {
+	return &Transport{upstream: upstream, next: next, opts: opts, breakers: make(map[string]*breaker)}
+}
+
+// outcome 一次请求的结果.
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota // 成功(包括 4xx 等上游正常处理的响应)
+	outcomeFailure                // 上游不可用, 计入熔断, 可重试
+	outcomeTimeout                // 请求超时, 计入熔断, 调用方已无剩余时间, 不重试
+	outcomeAborted                // 调用方主动取消, 不计入熔断, 不重试
+)
+
+// Do 发送请求. 熔断期间返回错误码为 UPSTREAM_UNAVAILABLE 的 *apperror.Error, 重试耗尽后返回最后一次的响应或错误.
+func (t *Transport) Do(req *http.Request) (*http.Response, error) {
+	addr := req.URL.Host
+	attempts := 1
+	if retryable(req) {
+		attempts += t.opts.Retries
+	}
+
+	for i := 0; ; i++ {
+		if !t.allow(addr) {
+			return nil, apperror.New(apperror.CODE_UPSTREAM_UNAVAILABLE,
+				fmt.Sprintf("%s(%s) is unavailable: circuit breaker open", t.upstream, addr))
+		}
+
+		resp, err := t.next.Do(req)
+		result, reason := classify(req.Context(), resp, err)
+		t.record(addr, result, reason)
+		if result != outcomeFailure || i+1 >= attempts {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), t.backoff(i)); err != nil {
+			return nil, fmt.Errorf("%s(%s) retry aborted after %s: %w", t.upstream, addr, reason, err)
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retryable 仅重试幂等且请求体可重放的请求.
+func retryable(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func classify(ctx context.Context, resp *http.Response, err error) (outcome, string) {
+	if err != nil {
+		if ctx.Err() != nil {
+			// 超时说明上游响应过慢, 计入熔断; 调用方主动取消与上游无关
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return outcomeTimeout, "timeout"
+			}
+			return outcomeAborted, "canceled"
+		}
+		return outcomeFailure, err.Error()
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return outcomeFailure, fmt.Sprintf("status code %d", resp.StatusCode)
+	}
+	return outcomeSuccess, ""
+}
+
+// backoff 返回第 i 次重试(从 0 开始)前的等待时间: Backoff * 2^i, 不超过 MaxBackoff, 并在 [d/2, d] 内随机抖动.
+func (t *Transport) backoff(i int) time.Duration {
+	d := t.opts.Backoff
+	if d <= 0 {
+		return 0
+	}
+	for ; i > 0 && (t.opts.MaxBackoff <= 0 || d < t.opts.MaxBackoff); i-- {
+		d *= 2
+	}
+	if t.opts.MaxBackoff > 0 && d > t.opts.MaxBackoff {
+		d = t.opts.MaxBackoff
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// allow 判断是否放行发往 addr 的请求. 熔断冷却结束后转为半开状态, 仅放行一个探测请求.
+func (t *Transport) allow(addr string) bool {
+	if t.opts.BreakerThreshold <= 0 {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breaker(addr)
+	switch b.state {
+	case STATE_OPEN:
+		if time.Since(b.openedAt) < t.opts.BreakerCooldown {
+			return false
+		}
+		b.state = STATE_HALF_OPEN
+		b.probing = true
+		return true
+	case STATE_HALF_OPEN:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (t *Transport) record(addr string, result outcome, reason string) {
+	if t.opts.BreakerThreshold <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b := t.breaker(addr)
+	switch result {
+	case outcomeSuccess:
+		b.state = STATE_CLOSED
+		b.failures = 0
+		b.probing = false
+	case outcomeFailure, outcomeTimeout:
+		b.failures++
+		b.lastError = reason
+		b.probing = false
+		if b.state == STATE_HALF_OPEN || b.failures >= t.opts.BreakerThreshold {
+			b.state = STATE_OPEN
+			b.openedAt = time.Now()
+		}
+	case outcomeAborted:
+		// 探测请求被取消, 允许下一个请求继续探测
+		b.probing = false
+	}
+}
+
+// breaker 返回 addr 的熔断器, 调用方需持有 t.mu.
+func (t *Transport) breaker(addr string) *breaker {
+	b, ok := t.breakers[addr]
+	if !ok {
+		b = &breaker{state: STATE_CLOSED}
+		t.breakers[addr] = b
+	}
+	return b
+}
+
+// Status 返回各上游地址的熔断器状态, 按地址排序.
+func (t *Transport) Status() []BreakerStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list := make([]BreakerStatus, 0, len(t.breakers))
+	for addr, b := range t.breakers {
+		state := b.state
+		if state == STATE_OPEN && time.Since(b.openedAt) >= t.opts.BreakerCooldown {
+			state = STATE_HALF_OPEN
+		}
+		list = append(list, BreakerStatus{
+			Upstream:  t.upstream,
+			Address:   addr,
+			State:     state,
+			Failures:  b.failures,
+			OpenedAt:  b.openedAt,
+			LastError: b.lastError,
+		})
+	}
+	slices.SortFunc(list, func(a, b BreakerStatus) int { return strings.Compare(a.Address, b.Address) })
+	return list
+}
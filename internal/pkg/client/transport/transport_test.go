@@ -0,0 +1,273 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"csjk-bk/internal/pkg/apperror"
+)
+
+// upstream 本地模拟的上游服务, status 根据请求序号(从 1 开始)返回状态码.
+type upstream struct {
+	*httptest.Server
+	hits atomic.Int64
+}
+
+func newUpstream(t *testing.T, status func(n int64) int) *upstream {
+	t.Helper()
+	u := &upstream{}
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status(u.hits.Add(1)))
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+func do(t *testing.T, tr *Transport, method, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), method, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tr.Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func statusOf(t *testing.T, tr *Transport, addr string) BreakerStatus {
+	t.Helper()
+	for _, s := range tr.Status() {
+		if s.Address == addr {
+			return s
+		}
+	}
+	t.Fatalf("no breaker status for %s", addr)
+	return BreakerStatus{}
+}
+
+func TestRetryGetWithBackoff(t *testing.T) {
+	u := newUpstream(t, func(n int64) int {
+		if n <= 2 {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	})
+	tr := New("test", u.Client(), Options{Retries: 3, Backoff: 20 * time.Millisecond, MaxBackoff: time.Second})
+
+	start := time.Now()
+	resp, err := do(t, tr, http.MethodGet, u.URL)
+	if err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := u.hits.Load(); got != 3 {
+		t.Errorf("hits = %d, want 3", got)
+	}
+	// 两次重试分别至少等待 Backoff/2 及 2*Backoff/2
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 30ms of backoff", elapsed)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	u := newUpstream(t, func(int64) int { return http.StatusBadGateway })
+	tr := New("test", u.Client(), Options{Retries: 2, Backoff: time.Millisecond})
+
+	resp, err := do(t, tr, http.MethodGet, u.URL)
+	if err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", resp.StatusCode)
+	}
+	if got := u.hits.Load(); got != 3 {
+		t.Errorf("hits = %d, want 3", got)
+	}
+}
+
+func TestNoRetryForClientErrors(t *testing.T) {
+	u := newUpstream(t, func(int64) int { return http.StatusNotFound })
+	tr := New("test", u.Client(), Options{Retries: 3, Backoff: time.Millisecond})
+
+	if _, err := do(t, tr, http.MethodGet, u.URL); err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if got := u.hits.Load(); got != 1 {
+		t.Errorf("hits = %d, want 1", got)
+	}
+}
+
+func TestNoRetryForNonIdempotent(t *testing.T) {
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		u := newUpstream(t, func(int64) int { return http.StatusServiceUnavailable })
+		tr := New("test", u.Client(), Options{Retries: 3, Backoff: time.Millisecond})
+
+		resp, err := do(t, tr, method, u.URL)
+		if err != nil {
+			t.Fatalf("%s: Do error: %v", method, err)
+		}
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("%s: status = %d, want 503", method, resp.StatusCode)
+		}
+		if got := u.hits.Load(); got != 1 {
+			t.Errorf("%s: hits = %d, want 1", method, got)
+		}
+	}
+}
+
+func TestBreakerOpens(t *testing.T) {
+	u := newUpstream(t, func(int64) int { return http.StatusServiceUnavailable })
+	tr := New("test", u.Client(), Options{BreakerThreshold: 3, BreakerCooldown: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		if _, err := do(t, tr, http.MethodGet, u.URL); err != nil {
+			t.Fatalf("request %d: Do error: %v", i, err)
+		}
+	}
+	_, err := do(t, tr, http.MethodGet, u.URL)
+	if err == nil {
+		t.Fatal("Do error = nil, want circuit breaker error")
+	}
+	if code := apperror.CodeOf(err); code != apperror.CODE_UPSTREAM_UNAVAILABLE {
+		t.Errorf("error code = %s, want %s", code, apperror.CODE_UPSTREAM_UNAVAILABLE)
+	}
+	if !strings.Contains(err.Error(), "unavailable") {
+		t.Errorf("error = %q, want upstream unavailable", err)
+	}
+	if got := u.hits.Load(); got != 3 {
+		t.Errorf("hits = %d, want 3 (open breaker must not reach upstream)", got)
+	}
+	if s := statusOf(t, tr, u.Listener.Addr().String()); s.State != STATE_OPEN || s.Failures != 3 {
+		t.Errorf("status = %+v, want open with 3 failures", s)
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	u := newUpstream(t, func(n int64) int {
+		if n%2 == 0 {
+			return http.StatusOK
+		}
+		return http.StatusServiceUnavailable
+	})
+	tr := New("test", u.Client(), Options{BreakerThreshold: 2, BreakerCooldown: time.Hour})
+
+	for i := 0; i < 6; i++ {
+		if _, err := do(t, tr, http.MethodGet, u.URL); err != nil {
+			t.Fatalf("request %d: Do error: %v", i, err)
+		}
+	}
+	if s := statusOf(t, tr, u.Listener.Addr().String()); s.State != STATE_CLOSED {
+		t.Errorf("state = %s, want closed", s.State)
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+	var healthy atomic.Bool
+	release := make(chan struct{})
+	probing := make(chan struct{}, 1)
+	u := newUpstream(t, func(n int64) int {
+		if !healthy.Load() {
+			return http.StatusServiceUnavailable
+		}
+		probing <- struct{}{}
+		<-release
+		return http.StatusOK
+	})
+	addr := u.Listener.Addr().String()
+	tr := New("test", u.Client(), Options{BreakerThreshold: 1, BreakerCooldown: cooldown})
+
+	// 失败一次后熔断
+	if _, err := do(t, tr, http.MethodGet, u.URL); err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if _, err := do(t, tr, http.MethodGet, u.URL); err == nil {
+		t.Fatal("Do error = nil while breaker open")
+	}
+
+	// 冷却结束后探测失败, 继续熔断
+	time.Sleep(cooldown + 10*time.Millisecond)
+	if s := statusOf(t, tr, addr); s.State != STATE_HALF_OPEN {
+		t.Errorf("state after cooldown = %s, want half_open", s.State)
+	}
+	if _, err := do(t, tr, http.MethodGet, u.URL); err != nil {
+		t.Fatalf("probe: Do error: %v", err)
+	}
+	if _, err := do(t, tr, http.MethodGet, u.URL); err == nil {
+		t.Fatal("Do error = nil after failed probe")
+	}
+	if got := u.hits.Load(); got != 2 {
+		t.Errorf("hits = %d, want 2", got)
+	}
+
+	// 冷却结束后仅放行一个探测请求, 探测成功后恢复
+	time.Sleep(cooldown + 10*time.Millisecond)
+	healthy.Store(true)
+	done := make(chan error, 1)
+	go func() {
+		_, err := do(t, tr, http.MethodGet, u.URL)
+		done <- err
+	}()
+	<-probing
+	if _, err := do(t, tr, http.MethodGet, u.URL); err == nil {
+		t.Error("Do error = nil while probe in flight, want only one probe")
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("probe: Do error: %v", err)
+	}
+	if s := statusOf(t, tr, addr); s.State != STATE_CLOSED || s.Failures != 0 {
+		t.Errorf("status after probe = %+v, want closed with 0 failures", s)
+	}
+	if _, err := do(t, tr, http.MethodGet, u.URL); err != nil {
+		t.Errorf("Do error after recovery: %v", err)
+	}
+}
+
+func TestBreakerPerAddress(t *testing.T) {
+	bad := newUpstream(t, func(int64) int { return http.StatusServiceUnavailable })
+	good := newUpstream(t, func(int64) int { return http.StatusOK })
+	tr := New("test", http.DefaultClient, Options{BreakerThreshold: 1, BreakerCooldown: time.Hour})
+
+	if _, err := do(t, tr, http.MethodGet, bad.URL); err != nil {
+		t.Fatalf("Do error: %v", err)
+	}
+	if _, err := do(t, tr, http.MethodGet, bad.URL); err == nil {
+		t.Error("Do error = nil for open address")
+	}
+	if _, err := do(t, tr, http.MethodGet, good.URL); err != nil {
+		t.Errorf("Do error for other address: %v", err)
+	}
+
+	status := tr.Status()
+	if len(status) != 2 {
+		t.Fatalf("len(Status()) = %d, want 2", len(status))
+	}
+	if s := statusOf(t, tr, bad.Listener.Addr().String()); s.State != STATE_OPEN {
+		t.Errorf("bad state = %s, want open", s.State)
+	}
+	if s := statusOf(t, tr, good.Listener.Addr().String()); s.State != STATE_CLOSED {
+		t.Errorf("good state = %s, want closed", s.State)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tr := New("test", nil, Options{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond})
+	for i, want := range []time.Duration{100, 200, 300, 300} {
+		want *= time.Millisecond
+		for j := 0; j < 20; j++ {
+			if d := tr.backoff(i); d < want/2 || d > want {
+				t.Errorf("backoff(%d) = %v, want in [%v, %v]", i, d, want/2, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Package hostlist 解析及压缩 Slurm 主机列表表达式, 如 cn[001-004,010],gpu01, 并提供主机集合的并集, 交集及差集运算.
+package hostlist
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MAX_HOSTS 单个表达式展开后的最大主机数, 防止 cn[1-100000000] 之类的表达式耗尽内存.
+const MAX_HOSTS = 65536
+
+// MAX_RANGE_DIGITS 方括号中数字的最大位数, 避免数值溢出.
+const MAX_RANGE_DIGITS = 9
+
+// Expand 展开主机列表表达式, 按表达式中的顺序返回主机名(不去重). 支持以逗号分隔的多个表达式,
+// 数字范围(1-4), 补零(001-004)及同一主机名中的多个方括号(rack[1-2]-cn[01-02]).
+func Expand(expr string) ([]string, error) {
+	var hosts []string
+	for _, part := range splitTop(expr) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		expanded, err := expandOne(part, MAX_HOSTS-len(hosts))
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, expanded...)
+	}
+	return hosts, nil
+}
+
+// splitTop 按方括号外的逗号拆分.
+func splitTop(expr string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, r := range expr {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, expr[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, expr[start:])
+}
+
+// expandOne 展开不含顶层逗号的单个表达式, 最多 limit 个主机.
+func expandOne(expr string, limit int) ([]string, error) {
+	open := strings.IndexByte(expr, '[')
+	if open < 0 {
+		if strings.ContainsRune(expr, ']') {
+			return nil, fmt.Errorf("invalid hostlist %q: unmatched ']'", expr)
+		}
+		if limit < 1 {
+			return nil, fmt.Errorf("hostlist expands to more than %d hosts", MAX_HOSTS)
+		}
+		return []string{expr}, nil
+	}
+	end := strings.IndexByte(expr[open:], ']')
+	if end < 0 {
+		return nil, fmt.Errorf("invalid hostlist %q: unmatched '['", expr)
+	}
+	end += open
+	prefix, ranges, rest := expr[:open], expr[open+1:end], expr[end+1:]
+
+	// 先展开剩余部分(可能包含更多方括号), 再与当前方括号中的各值组合
+	suffixes, err := expandOne(rest, limit)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	for _, r := range strings.Split(ranges, ",") {
+		lo, hi, width, err := parseRange(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hostlist %q: %w", expr, err)
+		}
+		// 先比较范围大小再相乘, 避免溢出
+		if left := limit - len(hosts); hi-lo >= left || hi-lo+1 > left/len(suffixes) {
+			return nil, fmt.Errorf("hostlist expands to more than %d hosts", MAX_HOSTS)
+		}
+		for n := lo; n <= hi; n++ {
+			for _, s := range suffixes {
+				hosts = append(hosts, fmt.Sprintf("%s%0*d%s", prefix, width, n, s))
+			}
+		}
+	}
+	return hosts, nil
+}
+
+// parseRange 解析 5 或 001-004, 返回上下界及补零宽度.
+func parseRange(r string) (lo, hi, width int, err error) {
+	r = strings.TrimSpace(r)
+	loStr, hiStr, isRange := strings.Cut(r, "-")
+	if !isRange {
+		hiStr = loStr
+	}
+	if loStr == "" || hiStr == "" {
+		return 0, 0, 0, fmt.Errorf("invalid range %q", r)
+	}
+	if strings.Trim(loStr+hiStr, "0123456789") != "" {
+		return 0, 0, 0, fmt.Errorf("invalid range %q", r)
+	}
+	if len(loStr) > MAX_RANGE_DIGITS || len(hiStr) > MAX_RANGE_DIGITS {
+		return 0, 0, 0, fmt.Errorf("invalid range %q: more than %d digits", r, MAX_RANGE_DIGITS)
+	}
+	if lo, err = strconv.Atoi(loStr); err != nil || lo < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid range %q", r)
+	}
+	if hi, err = strconv.Atoi(hiStr); err != nil || hi < lo {
+		return 0, 0, 0, fmt.Errorf("invalid range %q", r)
+	}
+	if len(loStr) > 1 && loStr[0] == '0' {
+		width = len(loStr)
+	}
+	return lo, hi, width, nil
+}
+
+// segment 主机名中连续的数字或非数字部分. 数字段的 nums 为该位置的取值, 压缩前仅有一个.
+type segment struct {
+	text string
+	nums []number
+}
+
+// number 数字段的值及原始字符串(保留补零).
+type number struct {
+	v uint64
+	s string
+}
+
+// split 将主机名拆分为数字段及非数字段, 如 rack1-cn01 拆分为 rack, 1, -cn, 01. 超出 uint64 的数字作为非数字段.
+func split(host string) []segment {
+	var segs []segment
+	for i := 0; i < len(host); {
+		j, digit := i, isDigit(host[i])
+		for j < len(host) && isDigit(host[j]) == digit {
+			j++
+		}
+		part := host[i:j]
+		if v, err := strconv.ParseUint(part, 10, 64); digit && err == nil {
+			segs = append(segs, segment{nums: []number{{v, part}}})
+		} else {
+			segs = append(segs, segment{text: part})
+		}
+		i = j
+	}
+	return segs
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// compare 按自然顺序比较主机名, 数字段按数值比较, 如 cn2 排在 cn10 之前.
+func compare(a, b string) int {
+	sa, sb := split(a), split(b)
+	for i := 0; i < len(sa) && i < len(sb); i++ {
+		x, y := sa[i], sb[i]
+		var c int
+		if len(x.nums) > 0 && len(y.nums) > 0 {
+			c = cmp.Or(cmp.Compare(x.nums[0].v, y.nums[0].v), cmp.Compare(len(x.nums[0].s), len(y.nums[0].s)))
+		} else {
+			c = strings.Compare(x.String(), y.String())
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Or(cmp.Compare(len(sa), len(sb)), strings.Compare(a, b))
+}
+
+// String 返回段的表达式, 数字段有多个取值时以方括号表示, 连续的值合并为范围.
+func (s segment) String() string {
+	switch len(s.nums) {
+	case 0:
+		return s.text
+	case 1:
+		return s.nums[0].s
+	}
+	var ranges []string
+	for i := 0; i < len(s.nums); {
+		lo, width := s.nums[i], 0
+		if len(lo.s) > 1 && lo.s[0] == '0' {
+			width = len(lo.s)
+		}
+		// 值连续且补零宽度一致时合并为范围
+		j := i + 1
+		for j < len(s.nums) && s.nums[j].v == s.nums[j-1].v+1 && fmt.Sprintf("%0*d", width, s.nums[j].v) == s.nums[j].s {
+			j++
+		}
+		r := lo.s
+		if j-i > 1 {
+			r += "-" + fmt.Sprintf("%0*d", width, s.nums[j-1].v)
+		}
+		ranges = append(ranges, r)
+		i = j
+	}
+	return "[" + strings.Join(ranges, ",") + "]"
+}
+
+// key 返回除第 skip 段外各段的表达式, 以 \x00 分隔, 用于判断主机能否在第 skip 段合并.
+func key(segs []segment, skip int) string {
+	parts := make([]string, len(segs))
+	for i, s := range segs {
+		if i != skip {
+			parts[i] = s.String()
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// Sort 按自然顺序排序主机名, 如 cn2 排在 cn10 之前.
+func Sort(hosts []string) {
+	slices.SortFunc(hosts, compare)
+}
+
+// Uniq 返回排序并去重后的主机名, 不修改 hosts.
+func Uniq(hosts []string) []string {
+	out := slices.Clone(hosts)
+	Sort(out)
+	return slices.Compact(out)
+}
+
+// Compress 将主机名压缩为主机列表表达式, 与 scontrol show hostlist 类似, 如 cn01,cn02,cn03,gpu1 压缩为 cn[01-03],gpu1.
+// 主机名按自然顺序排序并去重; 包含多个数字段时从最后一段开始逐段合并, 如 rack[1-2]-cn[01-02].
+func Compress(hosts []string) string {
+	hosts = Uniq(hosts)
+	items := make([][]segment, 0, len(hosts))
+	maxSegs := 0
+	for _, h := range hosts {
+		if h == "" {
+			continue
+		}
+		segs := split(h)
+		items = append(items, segs)
+		maxSegs = max(maxSegs, len(segs))
+	}
+	for d := maxSegs - 1; d >= 0; d-- {
+		items = mergeAt(items, d)
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, segs := range items {
+		var b strings.Builder
+		for _, s := range segs {
+			b.WriteString(s.String())
+		}
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+// mergeAt 合并除第 d 段(数字段)外其余各段均相同的主机, 保持首次出现的顺序.
+func mergeAt(items [][]segment, d int) [][]segment {
+	out := make([][]segment, 0, len(items))
+	index := make(map[string]int)
+	for _, segs := range items {
+		if d >= len(segs) || len(segs[d].nums) == 0 {
+			out = append(out, segs)
+			continue
+		}
+		k := key(segs, d)
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(out)
+			out = append(out, segs)
+			continue
+		}
+		merged := slices.Clone(out[i])
+		nums := append(slices.Clone(merged[d].nums), segs[d].nums...)
+		slices.SortFunc(nums, func(a, b number) int { return cmp.Or(cmp.Compare(a.v, b.v), cmp.Compare(len(a.s), len(b.s))) })
+		merged[d].nums = slices.CompactFunc(nums, func(a, b number) bool { return a.s == b.s })
+		out[i] = merged
+	}
+	return out
+}
+
+// Union 返回 a 与 b 的并集, 按自然顺序排序并去重.
+func Union(a, b []string) []string {
+	return Uniq(append(slices.Clone(a), b...))
+}
+
+// Intersect 返回同时属于 a 与 b 的主机, 按自然顺序排序并去重.
+func Intersect(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, h := range b {
+		set[h] = true
+	}
+	return Uniq(slices.DeleteFunc(slices.Clone(a), func(h string) bool { return !set[h] }))
+}
+
+// Difference 返回属于 a 但不属于 b 的主机, 按自然顺序排序并去重.
+func Difference(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, h := range b {
+		set[h] = true
+	}
+	return Uniq(slices.DeleteFunc(slices.Clone(a), func(h string) bool { return set[h] }))
+}
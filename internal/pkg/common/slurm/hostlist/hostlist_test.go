@@ -0,0 +1,149 @@
+package hostlist
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{"", nil},
+		{"cn01", []string{"cn01"}},
+		{"cn[1-3]", []string{"cn1", "cn2", "cn3"}},
+		{"cn[001-003,010]", []string{"cn001", "cn002", "cn003", "cn010"}},
+		{"cn[9-10]", []string{"cn9", "cn10"}},
+		{"cn[09-10]", []string{"cn09", "cn10"}},
+		{"cn[1-2],gpu01", []string{"cn1", "cn2", "gpu01"}},
+		{"rack[1-2]-cn[01-02]", []string{"rack1-cn01", "rack1-cn02", "rack2-cn01", "rack2-cn02"}},
+		{"a[1-2]b[3]c", []string{"a1b3c", "a2b3c"}},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.expr)
+		if err != nil {
+			t.Errorf("Expand(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Expand(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestExpandInvalid(t *testing.T) {
+	tests := []string{
+		"cn[1-2",
+		"cn1-2]",
+		"cn[]",
+		"cn[2-1]",
+		"cn[a-b]",
+		"cn[+1-2]",
+		"cn[1-]",
+		"cn[0-9223372036854775807]", // 超出位数限制, 曾导致溢出后死循环
+		"cn[0-999999999]",           // 超出 MAX_HOSTS
+		fmt.Sprintf("cn[1-%d]", MAX_HOSTS+1),
+		"a[1-300]b[1-300]",                // 多个方括号组合后超出 MAX_HOSTS
+		strings.Repeat("cn[1-40000],", 2), // 多个表达式合计超出 MAX_HOSTS
+	}
+	for _, expr := range tests {
+		if got, err := Expand(expr); err == nil {
+			t.Errorf("Expand(%q) = %d hosts, want error", expr, len(got))
+		}
+	}
+}
+
+func TestExpandLimit(t *testing.T) {
+	got, err := Expand(fmt.Sprintf("cn[1-%d]", MAX_HOSTS))
+	if err != nil || len(got) != MAX_HOSTS {
+		t.Errorf("Expand(cn[1-MAX_HOSTS]) = %d hosts, %v, want %d hosts", len(got), err, MAX_HOSTS)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	tests := []struct {
+		hosts []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"cn01"}, "cn01"},
+		{[]string{"cn03", "cn01", "cn02", "cn01"}, "cn[01-03]"},
+		{[]string{"cn1", "cn2", "cn10", "cn4"}, "cn[1-2,4,10]"},
+		{[]string{"cn9", "cn10", "cn11"}, "cn[9-11]"},
+		{[]string{"cn09", "cn10"}, "cn[09-10]"},
+		{[]string{"cn099", "cn100"}, "cn[099-100]"},
+		{[]string{"cn1", "cn01"}, "cn[1,01]"},
+		{[]string{"gpu1", "cn1", "login"}, "cn1,gpu1,login"},
+		{[]string{"rack1-cn01", "rack1-cn02", "rack2-cn01", "rack2-cn02"}, "rack[1-2]-cn[01-02]"},
+		{[]string{"rack1-cn01", "rack1-cn02", "rack2-cn01"}, "rack1-cn[01-02],rack2-cn01"},
+	}
+	for _, tt := range tests {
+		if got := Compress(tt.hosts); got != tt.want {
+			t.Errorf("Compress(%v) = %q, want %q", tt.hosts, got, tt.want)
+		}
+	}
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	tests := []string{
+		"cn[001-004,010]",
+		"cn[1-20],gpu[01-04],login",
+		"cn[8-12]",
+		"cn[08-12]",
+		"rack[1-3]-cn[01-16]",
+		"a1b2,a1b3,a2b2,x0,x00,x1",
+	}
+	for _, expr := range tests {
+		hosts, err := Expand(expr)
+		if err != nil {
+			t.Fatalf("Expand(%q) error: %v", expr, err)
+		}
+		again, err := Expand(Compress(hosts))
+		if err != nil {
+			t.Fatalf("Expand(Compress(%q)) error: %v", expr, err)
+		}
+		if want := Uniq(hosts); !slices.Equal(Uniq(again), want) {
+			t.Errorf("round trip of %q = %v, want %v", expr, Uniq(again), want)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	hosts := []string{"cn10", "gpu1", "cn2", "cn1", "rack2-cn1", "rack10-cn1"}
+	Sort(hosts)
+	want := []string{"cn1", "cn2", "cn10", "gpu1", "rack2-cn1", "rack10-cn1"}
+	if !slices.Equal(hosts, want) {
+		t.Errorf("Sort = %v, want %v", hosts, want)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := []string{"cn3", "cn1", "cn2", "cn1"}
+	b := []string{"cn2", "cn3", "cn4"}
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"union", Union(a, b), []string{"cn1", "cn2", "cn3", "cn4"}},
+		{"intersect", Intersect(a, b), []string{"cn2", "cn3"}},
+		{"difference", Difference(a, b), []string{"cn1"}},
+		{"union empty", Union(nil, nil), []string{}},
+		{"union with empty", Union(a, nil), []string{"cn1", "cn2", "cn3"}},
+		{"intersect empty", Intersect(a, nil), []string{}},
+		{"intersect of empty", Intersect(nil, b), []string{}},
+		{"difference empty", Difference(a, nil), []string{"cn1", "cn2", "cn3"}},
+		{"difference of empty", Difference(nil, b), []string{}},
+	}
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if !slices.Equal(a, []string{"cn3", "cn1", "cn2", "cn1"}) {
+		t.Errorf("set operations modified input: %v", a)
+	}
+}
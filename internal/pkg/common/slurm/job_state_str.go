@@ -115,3 +115,54 @@ func PrintJobStateString(istate uint64) string {
 		return "?"
 	}
 }
+
+var jobStateBaseNames = map[string]uint64{
+	"PENDING":       JOB_PENDING,
+	"RUNNING":       JOB_RUNNING,
+	"SUSPENDED":     JOB_SUSPENDED,
+	"COMPLETED":     JOB_COMPLETE,
+	"CANCELLED":     JOB_CANCELLED,
+	"FAILED":        JOB_FAILED,
+	"TIMEOUT":       JOB_TIMEOUT,
+	"NODE_FAIL":     JOB_NODE_FAIL,
+	"PREEMPTED":     JOB_PREEMPTED,
+	"BOOT_FAIL":     JOB_BOOT_FAIL,
+	"DEADLINE":      JOB_DEADLINE,
+	"OUT_OF_MEMORY": JOB_OOM,
+}
+
+var jobStateFlagNames = map[string]uint64{
+	"COMPLETING":    JOB_COMPLETING,
+	"STAGE_OUT":     JOB_STAGE_OUT,
+	"CONFIGURING":   JOB_CONFIGURING,
+	"RESIZING":      JOB_RESIZING,
+	"REQUEUED":      JOB_REQUEUE,
+	"REQUEUE_FED":   JOB_REQUEUE_FED,
+	"REQUEUE_HOLD":  JOB_REQUEUE_HOLD,
+	"SPECIAL_EXIT":  JOB_SPECIAL_EXIT,
+	"STOPPED":       JOB_STOPPED,
+	"REVOKED":       JOB_REVOKED,
+	"RESV_DEL_HOLD": JOB_RESV_DEL_HOLD,
+	"SIGNALING":     JOB_SIGNALING,
+}
+
+// ParseJobState 将官方 slurmrestd 返回的作业状态名称列表(如 ["RUNNING", "COMPLETING"])转换为
+// PrintJobStateString 使用的状态值. 未知名称忽略.
+func ParseJobState(names []string) uint64 {
+	var state uint64
+	for _, n := range names {
+		if v, ok := jobStateBaseNames[n]; ok {
+			state = state&^JOB_STATE_BASE | v
+			continue
+		}
+		state |= jobStateFlagNames[n]
+	}
+	return state
+}
+
+// IsJobStateName 判断 name 是否为 PrintJobStateString 返回的状态名称(如 RUNNING, COMPLETING).
+func IsJobStateName(name string) bool {
+	_, base := jobStateBaseNames[name]
+	_, flag := jobStateFlagNames[name]
+	return base || flag
+}
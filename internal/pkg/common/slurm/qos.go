@@ -0,0 +1,157 @@
+package slurm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QoS 标志(slurmdb_qos_rec_t.flags)
+const (
+	QOS_FLAG_PART_MIN_NODE       uint32 = 1 << iota // 作业节点数下限使用分区的 MinNodes
+	QOS_FLAG_PART_MAX_NODE                          // 作业节点数上限使用分区的 MaxNodes
+	QOS_FLAG_PART_TIME_LIMIT                        // 作业时间上限使用分区的 MaxTime
+	QOS_FLAG_ENFORCE_USAGE_THRES                    // 强制使用量阈值 UsageThreshold
+	QOS_FLAG_NO_RESERVE                             // 回填调度时不为该 QoS 的作业预留资源
+	QOS_FLAG_REQ_RESV                               // 作业必须在预约中运行
+	QOS_FLAG_DENY_LIMIT                             // 超出限制时拒绝提交, 而非排队等待
+	QOS_FLAG_OVER_PART_QOS                          // 限制优先于分区 QoS
+	QOS_FLAG_NO_DECAY                               // 使用量不随时间衰减
+	QOS_FLAG_USAGE_FACTOR_SAFE                      // 仅在作业运行完成时应用 UsageFactor
+	QOS_FLAG_RELATIVE                               // 限制为相对集群资源的百分比
+)
+
+// QoSFlag QoS 标志及其名称. Name 为 sacctmgr 中的名称, Official 为官方 slurmrestd 中的名称.
+type QoSFlag struct {
+	Flag     uint32
+	Name     string
+	Official string
+}
+
+var QoSFlags = []QoSFlag{
+	{QOS_FLAG_PART_MIN_NODE, "PartitionMinNodes", "PARTITION_MINIMUM_NODE"},
+	{QOS_FLAG_PART_MAX_NODE, "PartitionMaxNodes", "PARTITION_MAXIMUM_NODE"},
+	{QOS_FLAG_PART_TIME_LIMIT, "PartitionTimeLimit", "PARTITION_TIME_LIMIT"},
+	{QOS_FLAG_ENFORCE_USAGE_THRES, "EnforceUsageThreshold", "ENFORCE_USAGE_THRESHOLD"},
+	{QOS_FLAG_NO_RESERVE, "NoReserve", "NO_RESERVE"},
+	{QOS_FLAG_REQ_RESV, "RequiresReservation", "REQUIRED_RESERVATION"},
+	{QOS_FLAG_DENY_LIMIT, "DenyOnLimit", "DENY_LIMIT"},
+	{QOS_FLAG_OVER_PART_QOS, "OverPartQOS", "OVERRIDE_PARTITION_QOS"},
+	{QOS_FLAG_NO_DECAY, "NoDecay", "NO_DECAY"},
+	{QOS_FLAG_USAGE_FACTOR_SAFE, "UsageFactorSafe", "USAGE_FACTOR_SAFE"},
+	{QOS_FLAG_RELATIVE, "Relative", "RELATIVE"},
+}
+
+// ParseQoSFlags 解析逗号分隔的 QoS 标志, 大小写不敏感, 同时接受 sacctmgr 及官方 slurmrestd 中的名称.
+func ParseQoSFlags(s string) (uint32, error) {
+	var flags uint32
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, f := range QoSFlags {
+			if strings.EqualFold(name, f.Name) || strings.EqualFold(name, f.Official) {
+				flags |= f.Flag
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown qos flag: %q", name)
+		}
+	}
+	return flags, nil
+}
+
+// QoSFlagNames 返回 flags 中各标志在 sacctmgr 中的名称; official 为 true 时返回官方 slurmrestd 中的名称.
+func QoSFlagNames(flags uint32, official bool) []string {
+	names := make([]string, 0)
+	for _, f := range QoSFlags {
+		if flags&f.Flag == 0 {
+			continue
+		}
+		if official {
+			names = append(names, f.Official)
+		} else {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+// 抢占模式(slurmdb_qos_rec_t.preempt_mode)
+const (
+	PREEMPT_MODE_OFF     uint16 = 0x0000 // 不抢占
+	PREEMPT_MODE_SUSPEND uint16 = 0x0001 // 挂起被抢占的作业
+	PREEMPT_MODE_REQUEUE uint16 = 0x0002 // 重新排队被抢占的作业
+	PREEMPT_MODE_CANCEL  uint16 = 0x0008 // 取消被抢占的作业
+	PREEMPT_MODE_WITHIN  uint16 = 0x4000 // 允许同一 QoS 内的作业相互抢占
+	PREEMPT_MODE_GANG    uint16 = 0x8000 // 与 SUSPEND 配合使用的分时调度
+)
+
+var preemptModeNames = []struct {
+	mode uint16
+	name string
+}{
+	{PREEMPT_MODE_SUSPEND, "SUSPEND"},
+	{PREEMPT_MODE_REQUEUE, "REQUEUE"},
+	{PREEMPT_MODE_CANCEL, "CANCEL"},
+	{PREEMPT_MODE_WITHIN, "WITHIN"},
+	{PREEMPT_MODE_GANG, "GANG"},
+}
+
+// ParsePreemptMode 解析逗号分隔的抢占模式, 如 SUSPEND,GANG; OFF(官方 slurmrestd 中为 DISABLED)不可与其他模式组合.
+// QoS 中 SUSPEND, REQUEUE, CANCEL 至多指定一个.
+func ParsePreemptMode(s string) (uint16, error) {
+	var mode uint16
+	off := false
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		switch name {
+		case "":
+			continue
+		case "OFF", "DISABLED":
+			off = true
+			continue
+		}
+		found := false
+		for _, m := range preemptModeNames {
+			if name == m.name {
+				mode |= m.mode
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown preempt mode: %q", name)
+		}
+	}
+	if off && mode != PREEMPT_MODE_OFF {
+		return 0, fmt.Errorf("preempt mode OFF can not be combined with other modes: %q", s)
+	}
+	n := 0
+	for _, m := range []uint16{PREEMPT_MODE_SUSPEND, PREEMPT_MODE_REQUEUE, PREEMPT_MODE_CANCEL} {
+		if mode&m != 0 {
+			n++
+		}
+	}
+	if n > 1 {
+		return 0, fmt.Errorf("only one of SUSPEND, REQUEUE and CANCEL can be set: %q", s)
+	}
+	return mode, nil
+}
+
+// PreemptModeString 返回抢占模式的名称, 如 SUSPEND,GANG; 不抢占时为 OFF.
+func PreemptModeString(mode uint16) string {
+	names := make([]string, 0, 2)
+	for _, m := range preemptModeNames {
+		if mode&m.mode != 0 {
+			names = append(names, m.name)
+		}
+	}
+	if len(names) == 0 {
+		return "OFF"
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,111 @@
+package slurm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UNLIMITED ParseDuration 对 UNLIMITED/INFINITE 的返回值, 表示不限时长.
+const UNLIMITED time.Duration = -1
+
+// 开始时间支持的绝对时间格式, 按本地时区解析.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseTime 解析 scontrol/sbatch 的时间参数(如预约的 StartTime), 支持 YYYY-MM-DD[THH:MM[:SS]],
+// now 及 now+count[seconds|minutes|hours|days|weeks](缺省单位为秒). now 为相对时间的基准.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	if lower == "now" {
+		return now, nil
+	}
+	if rest, ok := strings.CutPrefix(lower, "now+"); ok {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time: %q", s)
+		}
+		unit := time.Second
+		switch rest[i:] {
+		case "", "s", "sec", "second", "seconds":
+		case "m", "min", "minute", "minutes":
+			unit = time.Minute
+		case "h", "hour", "hours":
+			unit = time.Hour
+		case "d", "day", "days":
+			unit = 24 * time.Hour
+		case "w", "week", "weeks":
+			unit = 7 * 24 * time.Hour
+		default:
+			return time.Time{}, fmt.Errorf("invalid time: %q", s)
+		}
+		return now.Add(time.Duration(n) * unit), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %q", s)
+}
+
+// ParseDuration 解析 Slurm 的时长参数(如预约的 Duration, 作业的 --time), 支持 minutes, minutes:seconds,
+// hours:minutes:seconds, days-hours, days-hours:minutes, days-hours:minutes:seconds 及 UNLIMITED/INFINITE.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToUpper(s) {
+	case "UNLIMITED", "INFINITE":
+		return UNLIMITED, nil
+	}
+
+	var days int
+	rest := s
+	hasDays := false
+	if d, r, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.Atoi(d)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %q", s)
+		}
+		days, rest, hasDays = n, r, true
+	}
+
+	parts := strings.Split(rest, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration: %q", s)
+	}
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration: %q", s)
+		}
+		nums[i] = n
+	}
+
+	var h, m, sec int
+	switch {
+	case hasDays && len(nums) == 1: // days-hours
+		h = nums[0]
+	case hasDays && len(nums) == 2: // days-hours:minutes
+		h, m = nums[0], nums[1]
+	case len(nums) == 3: // [days-]hours:minutes:seconds
+		h, m, sec = nums[0], nums[1], nums[2]
+	case len(nums) == 2: // minutes:seconds
+		m, sec = nums[0], nums[1]
+	default: // minutes
+		m = nums[0]
+	}
+	return time.Duration(days)*24*time.Hour + time.Duration(h)*time.Hour +
+		time.Duration(m)*time.Minute + time.Duration(sec)*time.Second, nil
+}
@@ -0,0 +1,332 @@
+// Package tres 解析及格式化 Slurm 的 TRES(可跟踪资源)字符串.
+// slurmdbd 中以 ID 表示类型, 如 1=4,2=8000,1001=2; sacctmgr 及 slurmrestd 中以名称表示, 如 cpu=4,mem=8G,gres/gpu=2.
+package tres
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TRES 中固定 ID 的类型, 与 slurmdbd 中的定义一致; 其他类型(如 gres/gpu)的 ID 由 slurmdbd 分配, 见 Table.
+const (
+	TRES_CPU     int64 = 1
+	TRES_MEM     int64 = 2
+	TRES_ENERGY  int64 = 3
+	TRES_NODE    int64 = 4
+	TRES_BILLING int64 = 5
+	TRES_FS_DISK int64 = 6
+	TRES_VMEM    int64 = 7
+	TRES_PAGES   int64 = 8
+)
+
+var (
+	plainTypes = []string{"cpu", "mem", "energy", "node", "billing", "vmem", "pages"} // 不带名称的类型
+	namedTypes = []string{"gres", "license", "bb", "fs", "ic"}                        // 需带名称的类型, 如 gres/gpu
+	sizeTypes  = []string{"mem", "vmem", "bb", "fs"}                                  // 容量类型, 基本单位为 MB
+)
+
+// Def TRES 类型定义, 对应 slurmdbd 的 tres_table.
+type Def struct {
+	ID   int64
+	Type string // 类型, 如 cpu, mem, gres
+	Name string // 带名称类型的名称, 如 gres/gpu 中的 gpu
+}
+
+// Key 返回 type[/name], 如 cpu, gres/gpu.
+func (d Def) Key() string {
+	if d.Name == "" {
+		return d.Type
+	}
+	return d.Type + "/" + d.Name
+}
+
+var static = Table{
+	TRES_CPU:     {TRES_CPU, "cpu", ""},
+	TRES_MEM:     {TRES_MEM, "mem", ""},
+	TRES_ENERGY:  {TRES_ENERGY, "energy", ""},
+	TRES_NODE:    {TRES_NODE, "node", ""},
+	TRES_BILLING: {TRES_BILLING, "billing", ""},
+	TRES_FS_DISK: {TRES_FS_DISK, "fs", "disk"},
+	TRES_VMEM:    {TRES_VMEM, "vmem", ""},
+	TRES_PAGES:   {TRES_PAGES, "pages", ""},
+}
+
+// Table TRES ID 到类型定义的映射, 用于解析 ID 格式的 TRES. nil 时仅识别固定 ID.
+type Table map[int64]Def
+
+// NewTable 由集群的 TRES 列表(如 slurmrest 的 GetTres)创建 Table, 固定 ID 始终可用.
+func NewTable(defs []Def) Table {
+	t := make(Table, len(static)+len(defs))
+	for id, d := range static {
+		t[id] = d
+	}
+	for _, d := range defs {
+		d.Type, d.Name = strings.ToLower(d.Type), strings.ToLower(d.Name)
+		t[d.ID] = d
+	}
+	return t
+}
+
+func (t Table) lookup(id int64) (Def, bool) {
+	if d, ok := t[id]; ok {
+		return d, true
+	}
+	d, ok := static[id]
+	return d, ok
+}
+
+// Entry TRES 条目.
+type Entry struct {
+	Key   string // type[/name], 如 cpu, gres/gpu; 无法解析的 ID 保留为 ID
+	Count int64  // 数量, 容量类型单位为 MB; -1 表示清除(仅用于修改限制)
+}
+
+// List 按原顺序排列的 TRES 条目.
+type List []Entry
+
+// Parse 解析并校验 TRES 字符串(type[/name]=count 或 id=count, 逗号分隔), 返回按输入顺序排列的条目.
+// 类型转换为小写, ID 通过 table 转换为类型名称, table 中不存在的 ID 原样保留;
+// 容量类型的 count 可使用 K, M, G, T, P 后缀, 转换为 MB; count 为 -1 表示清除;
+// count 也可为 sacct 显示 TRES 用量时使用的时长格式 [days-][hours:]minutes:seconds[.fraction], 如 cpu=00:01:02, 转换为秒.
+func Parse(s string, table Table) (List, error) {
+	list := make(List, 0)
+	seen := make(map[string]bool)
+	for _, kv := range strings.Split(s, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tres %q: expect type[/name]=count", kv)
+		}
+		k = strings.ToLower(strings.TrimSpace(k))
+		typ, name, named := strings.Cut(k, "/")
+		if id, err := strconv.ParseInt(k, 10, 64); err == nil {
+			if d, ok := table.lookup(id); ok {
+				k = d.Key()
+				typ = d.Type
+			}
+		} else if !(named && slices.Contains(namedTypes, typ) && name != "") && !(!named && slices.Contains(plainTypes, typ)) {
+			return nil, fmt.Errorf("invalid tres %q: unknown type %q", kv, k)
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("invalid tres %q: duplicate type %q", s, k)
+		}
+		seen[k] = true
+
+		count, err := parseCount(strings.TrimSpace(v), slices.Contains(sizeTypes, typ))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tres %q: %w", kv, err)
+		}
+		list = append(list, Entry{Key: k, Count: count})
+	}
+	return list, nil
+}
+
+func parseCount(v string, size bool) (int64, error) {
+	if v == "-1" {
+		return -1, nil
+	}
+	if strings.Contains(v, ":") {
+		return parseDuration(v)
+	}
+	suffix := ""
+	if size && v != "" && strings.ContainsAny(strings.ToUpper(v[len(v)-1:]), "KMGTP") {
+		suffix, v = strings.ToUpper(v[len(v)-1:]), v[:len(v)-1]
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("count must be a non-negative integer or -1")
+	}
+	if suffix == "K" {
+		return n/1024 + (n%1024+1023)/1024, nil // 不足 1M 的部分向上取整, 不使用 (n+1023)/1024 以免溢出
+	}
+	shift := map[string]uint{"G": 10, "T": 20, "P": 30}[suffix]
+	if n > math.MaxInt64>>shift {
+		return 0, fmt.Errorf("count out of range")
+	}
+	return n << shift, nil
+}
+
+// ParseUsage 解析作业步的 TRES 用量(如 slurmdbd 的 tres_usage_in_max), 返回的 cpu 为 CPU 时间(秒), 容量类型为 MB.
+// slurmdbd 中存储的原始值 cpu 单位为毫秒, 容量类型单位为字节, 分别转换为秒及 MB(向上取整);
+// sacct 显示的格式(cpu 为时长, 如 cpu=00:01:02; 容量带单位)按 Parse 解析.
+func ParseUsage(s string, table Table) (List, error) {
+	list, err := Parse(s, table)
+	if err != nil {
+		return nil, err
+	}
+	i := 0
+	for _, kv := range strings.Split(s, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		_, v, _ := strings.Cut(kv, "=")
+		e := &list[i]
+		i++
+		if _, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64); err != nil || e.Count < 0 {
+			continue // 非原始值
+		}
+		switch {
+		case e.Key == "cpu":
+			e.Count /= 1000
+		case isSize(e.Key):
+			const mb = 1 << 20
+			e.Count = e.Count/mb + (e.Count%mb+mb-1)/mb
+		}
+	}
+	return list, nil
+}
+
+// parseDuration 解析 [days-][hours:]minutes:seconds[.fraction] 格式的时长, 返回秒数, 不足 1 秒的部分舍去.
+func parseDuration(v string) (int64, error) {
+	errFormat := fmt.Errorf("duration must be in [days-][hours:]minutes:seconds format")
+	var days int64
+	if d, rest, ok := strings.Cut(v, "-"); ok {
+		n, err := strconv.ParseInt(d, 10, 64)
+		if err != nil || n < 0 || n > math.MaxInt64/(24*3600)-1 {
+			return 0, errFormat
+		}
+		days, v = n, rest
+	}
+	v, _, _ = strings.Cut(v, ".")
+	parts := strings.Split(v, ":")
+	if len(parts) == 2 {
+		parts = append([]string{"0"}, parts...)
+	}
+	if len(parts) != 3 {
+		return 0, errFormat
+	}
+	hms := make([]int64, 3)
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 6)
+		if err != nil || (i == 0 && n >= 24) || n >= 60 {
+			return 0, errFormat
+		}
+		hms[i] = int64(n)
+	}
+	return days*24*3600 + hms[0]*3600 + hms[1]*60 + hms[2], nil
+}
+
+// String 返回 type[/name]=count 格式的字符串, 容量类型的单位为 MB, 可直接用于 slurmrestd 请求.
+func (l List) String() string {
+	parts := make([]string, 0, len(l))
+	for _, e := range l {
+		parts = append(parts, e.Key+"="+strconv.FormatInt(e.Count, 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Get 返回类型为 key(如 cpu, gres/gpu)的数量.
+func (l List) Get(key string) (int64, bool) {
+	i := slices.IndexFunc(l, func(e Entry) bool { return e.Key == key })
+	if i < 0 {
+		return 0, false
+	}
+	return l[i].Count, true
+}
+
+// Merge 返回将 set 中的 TRES 应用到 cur 之后的结果(与 sacctmgr 一致, 仅修改列出的 TRES), count 为 -1 的条目被删除.
+func Merge(cur, set List) List {
+	list := slices.Clone(cur)
+	for _, u := range set {
+		i := slices.IndexFunc(list, func(e Entry) bool { return e.Key == u.Key })
+		switch {
+		case i < 0 && u.Count >= 0:
+			list = append(list, u)
+		case i >= 0 && u.Count < 0:
+			list = slices.Delete(list, i, i+1)
+		case i >= 0:
+			list[i] = u
+		}
+	}
+	return list
+}
+
+// isSize 判断 key 是否为容量类型.
+func isSize(key string) bool {
+	typ, _, _ := strings.Cut(key, "/")
+	return slices.Contains(sizeTypes, typ)
+}
+
+// FormatSize 将以 MB 为单位的容量格式化为带单位的字符串, 如 512M, 8G, 1.5T, 与 Slurm 的显示一致保留至多两位小数.
+func FormatSize(mb int64) string {
+	units := []string{"M", "G", "T", "P"}
+	v, i := float64(mb), 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64) + units[i]
+}
+
+// FormatMinutes 将分钟数格式化为 Slurm 的时长格式 [days-]hours:minutes:seconds, 如 1-01:00:00.
+func FormatMinutes(minutes int64) string {
+	days, rest := minutes/(24*60), minutes%(24*60)
+	s := fmt.Sprintf("%02d:%02d:00", rest/60, rest%60)
+	if days > 0 {
+		s = fmt.Sprintf("%d-%s", days, s)
+	}
+	return s
+}
+
+// Format 返回便于阅读的字符串, 容量类型带单位, 如 cpu=4,mem=8G,gres/gpu=2.
+func (l List) Format() string {
+	parts := make([]string, 0, len(l))
+	for _, e := range l {
+		v := strconv.FormatInt(e.Count, 10)
+		if e.Count >= 0 && isSize(e.Key) {
+			v = FormatSize(e.Count)
+		}
+		parts = append(parts, e.Key+"="+v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// FormatMinutes 将 TRES 时间(如 GrpTRESMins, 数量乘以分钟数)格式化为时长, 如 cpu=1-01:00:00.
+func (l List) FormatMinutes() string {
+	parts := make([]string, 0, len(l))
+	for _, e := range l {
+		v := strconv.FormatInt(e.Count, 10)
+		if e.Count >= 0 {
+			v = FormatMinutes(e.Count)
+		}
+		parts = append(parts, e.Key+"="+v)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Resources 常用 TRES 的结构化表示, 字段为 null 表示 TRES 字符串中不包含该类型(如未设置限制).
+type Resources struct {
+	CPU     *int64 `json:"cpu"`     // CPU 数
+	MemMB   *int64 `json:"mem_mb"`  // 内存(MB)
+	GPUs    *int64 `json:"gpus"`    // GPU 数, 即 gres/gpu, 不存在时为各型号(gres/gpu:<type>)之和
+	Nodes   *int64 `json:"nodes"`   // 节点数
+	Billing *int64 `json:"billing"` // 计费值
+}
+
+// Resources 返回常用 TRES 的结构化表示, 忽略 count 为 -1 的条目.
+func (l List) Resources() Resources {
+	get := func(key string) *int64 {
+		if v, ok := l.Get(key); ok && v >= 0 {
+			return &v
+		}
+		return nil
+	}
+	r := Resources{CPU: get("cpu"), MemMB: get("mem"), GPUs: get("gres/gpu"), Nodes: get("node"), Billing: get("billing")}
+	if r.GPUs == nil {
+		for _, e := range l {
+			if strings.HasPrefix(e.Key, "gres/gpu:") && e.Count >= 0 {
+				sum := e.Count
+				if r.GPUs != nil {
+					sum += *r.GPUs
+				}
+				r.GPUs = &sum
+			}
+		}
+	}
+	return r
+}
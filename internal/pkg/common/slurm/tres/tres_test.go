@@ -0,0 +1,208 @@
+package tres
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	table := NewTable([]Def{{ID: 1001, Type: "gres", Name: "gpu"}, {ID: 1002, Type: "GRES", Name: "gpu:A100"}})
+	tests := []struct {
+		in   string
+		want List
+	}{
+		{"", List{}},
+		{" , ", List{}},
+		{"cpu=4,mem=16G,gres/gpu:a100=2", List{{"cpu", 4}, {"mem", 16 * 1024}, {"gres/gpu:a100", 2}}},
+		{"CPU=4, Mem=512", List{{"cpu", 4}, {"mem", 512}}},
+		{"node=2,cpu=-1", List{{"node", 2}, {"cpu", -1}}},
+		{"license/matlab=3,billing=0", List{{"license/matlab", 3}, {"billing", 0}}},
+		// ID 格式, 不存在的 ID 原样保留
+		{"1=4,2=8000,1001=2,1002=1,9999=5", List{{"cpu", 4}, {"mem", 8000}, {"gres/gpu", 2}, {"gres/gpu:a100", 1}, {"9999", 5}}},
+		// 容量单位
+		{"mem=1K", List{{"mem", 1}}},
+		{"mem=1025k", List{{"mem", 2}}},
+		{"mem=2M", List{{"mem", 2}}},
+		{"mem=1g", List{{"mem", 1024}}},
+		{"vmem=1T", List{{"vmem", 1 << 20}}},
+		{"bb/datawarp=1P", List{{"bb/datawarp", 1 << 30}}},
+		{fmt.Sprintf("mem=%dK", int64(math.MaxInt64)), List{{"mem", math.MaxInt64/1024 + 1}}},
+		{fmt.Sprintf("mem=%dP", int64(math.MaxInt64>>30)), List{{"mem", math.MaxInt64 >> 30 << 30}}},
+		// 时长, 转换为秒
+		{"cpu=00:01:02", List{{"cpu", 62}}},
+		{"cpu=01:02", List{{"cpu", 62}}},
+		{"cpu=01:02.345", List{{"cpu", 62}}},
+		{"cpu=1-02:03:04,mem=1G", List{{"cpu", 93784}, {"mem", 1024}}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, table)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"cpu",                     // 缺少 =
+		"cpu4,mem=1G",             // 缺少 =
+		"=4",                      // 缺少类型
+		"gpu=2",                   // 未知类型
+		"gres=2",                  // 带名称类型缺少名称
+		"gres/=2",                 // 带名称类型缺少名称
+		"cpu/x=2",                 // 不带名称类型有名称
+		"cpu=4,CPU=8",             // 重复类型
+		"1=4,cpu=8",               // ID 与名称重复
+		"cpu=",                    // 缺少数量
+		"cpu=abc",                 // 非整数
+		"cpu=-2",                  // 负数
+		"cpu=1.5",                 // 非整数
+		"cpu=4G",                  // 非容量类型不允许单位
+		"mem=1X",                  // 未知单位
+		"mem=G",                   // 仅有单位
+		"cpu=9223372036854775808", // 超出 int64
+		fmt.Sprintf("mem=%dG", int64(math.MaxInt64>>10)+1), // 转换为 MB 后溢出
+		fmt.Sprintf("mem=%dP", int64(math.MaxInt64>>30)+1), // 转换为 MB 后溢出
+		"cpu=:01",         // 时长缺少分钟
+		"cpu=1:02:03:04",  // 时长字段过多
+		"cpu=00:60",       // 秒数超出范围
+		"cpu=24:00:00",    // 小时数超出范围
+		"cpu=-1-00:00:00", // 负天数
+		"cpu=x-00:00:00",  // 天数非整数
+	}
+	for _, in := range tests {
+		if got, err := Parse(in, nil); err == nil {
+			t.Errorf("Parse(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestParseUsage(t *testing.T) {
+	tests := []struct {
+		in   string
+		want List
+	}{
+		// slurmdbd 原始值: cpu 为毫秒, 容量为字节
+		{"1=62500,2=1048576,4=1", List{{"cpu", 62}, {"mem", 1}, {"node", 1}}},
+		{"cpu=999,mem=1048577,vmem=0", List{{"cpu", 0}, {"mem", 2}, {"vmem", 0}}},
+		// sacct 格式
+		{"cpu=00:01:02,mem=1536K,energy=10", List{{"cpu", 62}, {"mem", 2}, {"energy", 10}}},
+		{"", List{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseUsage(tt.in, nil)
+		if err != nil {
+			t.Errorf("ParseUsage(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseUsage(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if got, err := ParseUsage("cpu=1:2:3:4", nil); err == nil {
+		t.Errorf("ParseUsage(invalid) = %v, want error", got)
+	}
+}
+
+func TestListString(t *testing.T) {
+	l, err := Parse("cpu=4,mem=16G,gres/gpu:a100=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := l.String(), "cpu=4,mem=16384,gres/gpu:a100=2"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := l.Format(), "cpu=4,mem=16G,gres/gpu:a100=2"; got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cur := List{{"cpu", 4}, {"mem", 1024}, {"gres/gpu", 2}}
+	tests := []struct {
+		name string
+		set  List
+		want List
+	}{
+		{"empty", nil, List{{"cpu", 4}, {"mem", 1024}, {"gres/gpu", 2}}},
+		{"update", List{{"cpu", 8}}, List{{"cpu", 8}, {"mem", 1024}, {"gres/gpu", 2}}},
+		{"add", List{{"node", 1}}, List{{"cpu", 4}, {"mem", 1024}, {"gres/gpu", 2}, {"node", 1}}},
+		{"clear", List{{"mem", -1}}, List{{"cpu", 4}, {"gres/gpu", 2}}},
+		{"clear missing", List{{"node", -1}}, List{{"cpu", 4}, {"mem", 1024}, {"gres/gpu", 2}}},
+		{"mixed", List{{"gres/gpu", -1}, {"cpu", 0}, {"billing", 10}}, List{{"cpu", 0}, {"mem", 1024}, {"billing", 10}}},
+		{"clear all", List{{"cpu", -1}, {"mem", -1}, {"gres/gpu", -1}}, List{}},
+	}
+	for _, tt := range tests {
+		if got := Merge(cur, tt.set); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Merge = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !slices.Equal(cur, List{{"cpu", 4}, {"mem", 1024}, {"gres/gpu", 2}}) {
+		t.Errorf("Merge modified cur: %v", cur)
+	}
+	if got := Merge(nil, List{{"cpu", 1}, {"mem", -1}}); !slices.Equal(got, List{{"cpu", 1}}) {
+		t.Errorf("Merge(nil) = %v, want cpu=1", got)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		mb   int64
+		want string
+	}{
+		{0, "0M"},
+		{512, "512M"},
+		{1023, "1023M"},
+		{1024, "1G"},
+		{1536, "1.5G"},
+		{8 * 1024, "8G"},
+		{1000 * 1024, "1000G"},
+		{1 << 20, "1T"},
+		{1<<20 + 1<<19, "1.5T"},
+		{1<<20 + 10, "1T"}, // 保留两位小数
+		{1 << 30, "1P"},
+		{2048 << 30, "2048P"}, // P 为最大单位
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.mb); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.mb, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		minutes int64
+		want    string
+	}{
+		{0, "00:00:00"},
+		{90, "01:30:00"},
+		{24 * 60, "1-00:00:00"},
+		{25 * 60, "1-01:00:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatMinutes(tt.minutes); got != tt.want {
+			t.Errorf("FormatMinutes(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestResources(t *testing.T) {
+	l := List{{"cpu", 4}, {"mem", -1}, {"gres/gpu:a100", 2}, {"gres/gpu:v100", 1}}
+	r := l.Resources()
+	if r.CPU == nil || *r.CPU != 4 {
+		t.Errorf("CPU = %v, want 4", r.CPU)
+	}
+	if r.MemMB != nil || r.Nodes != nil {
+		t.Errorf("MemMB, Nodes = %v, %v, want nil", r.MemMB, r.Nodes)
+	}
+	if r.GPUs == nil || *r.GPUs != 3 {
+		t.Errorf("GPUs = %v, want 3", r.GPUs)
+	}
+}
@@ -0,0 +1,451 @@
+// Package config 加载服务配置. 配置来源优先级由高到低为: 命令行参数 > 环境变量 > 配置文件 > 默认值.
+// 敏感信息(数据库 DSN, IPMI 密码)可通过 *_file 指定从文件中读取, 以便配合 docker/k8s secret 使用.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix 环境变量前缀, 如 CSJK_DATABASE_DSN.
+const EnvPrefix = "CSJK_"
+
+type Config struct {
+	Log          LogConfig          `yaml:"log"`
+	Server       ServerConfig       `yaml:"server"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Slurmrest    SlurmrestConfig    `yaml:"slurmrest"`
+	Upstream     UpstreamConfig     `yaml:"upstream"`
+	Cache        CacheConfig        `yaml:"cache"`
+	Alertmanager AlertmanagerConfig `yaml:"alertmanager"`
+	Reservation  ReservationConfig  `yaml:"reservation"`
+	IPMI         IPMIConfig         `yaml:"ipmi"`
+	Auth         AuthConfig         `yaml:"auth"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn, error
+	Output string `yaml:"output"` // stdout, stderr, file
+	Format string `yaml:"format"` // json, text
+	File   string `yaml:"file"`   // output 为 file 时的日志文件路径
+}
+
+type ServerConfig struct {
+	ListenAddr       string        `yaml:"listen_addr"`
+	ShutdownTimeout  time.Duration `yaml:"shutdown_timeout"`
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"` // /readyz 中单个依赖的探测超时时间
+}
+
+type DatabaseConfig struct {
+	DSN         string `yaml:"dsn"`          // postgres 连接串
+	DSNFile     string `yaml:"dsn_file"`     // 从文件读取 DSN, 优先于 dsn
+	AutoMigrate bool   `yaml:"auto_migrate"` // 启动时自动将表结构迁移到最新版本
+}
+
+type SlurmrestConfig struct {
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// UpstreamConfig 访问上游服务(slurmrestd, KD Lustre, Alertmanager)时的重试及熔断配置.
+type UpstreamConfig struct {
+	Retries          int           `yaml:"retries"`           // GET 请求在网络错误或 502/503/504 时的最大重试次数, 0 表示不重试
+	RetryBackoff     time.Duration `yaml:"retry_backoff"`     // 首次重试前的等待时间, 之后每次翻倍
+	RetryMaxBackoff  time.Duration `yaml:"retry_max_backoff"` // 重试等待时间上限
+	BreakerThreshold int           `yaml:"breaker_threshold"` // 同一上游地址连续失败该次数后熔断, 0 表示不熔断
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`  // 熔断持续时间, 之后放行一个探测请求
+}
+
+// CacheConfig slurmrestd 响应缓存配置.
+type CacheConfig struct {
+	Enabled  bool                `yaml:"enabled"`  // 是否启用缓存
+	TTL      CacheTTL            `yaml:"ttl"`      // 各资源类型的缓存时间
+	Clusters map[string]CacheTTL `yaml:"clusters"` // 按集群名称覆盖缓存时间, 未设置(0)的资源类型使用 ttl 中的配置
+}
+
+// CacheTTL 各资源类型的缓存时间, 0 表示不缓存(按集群覆盖时表示使用全局配置), 负数表示不缓存.
+type CacheTTL struct {
+	Nodes      time.Duration `yaml:"nodes"`
+	Partitions time.Duration `yaml:"partitions"`
+	QoS        time.Duration `yaml:"qos"`
+	Accounts   time.Duration `yaml:"accounts"`
+	Tres       time.Duration `yaml:"tres"`
+	Jobs       time.Duration `yaml:"jobs"`
+}
+
+func (t CacheTTL) get(resource string) time.Duration {
+	switch resource {
+	case "nodes":
+		return t.Nodes
+	case "partitions":
+		return t.Partitions
+	case "qos":
+		return t.QoS
+	case "accounts":
+		return t.Accounts
+	case "tres":
+		return t.Tres
+	case "jobs":
+		return t.Jobs
+	}
+	return 0
+}
+
+// TTLFor 返回集群(cluster)中资源(resource: nodes, partitions, qos, accounts, tres, jobs)的缓存时间, 未启用缓存时返回 0.
+func (c CacheConfig) TTLFor(cluster, resource string) time.Duration {
+	if !c.Enabled {
+		return 0
+	}
+	if override, ok := c.Clusters[cluster]; ok {
+		if d := override.get(resource); d != 0 {
+			return d
+		}
+	}
+	return c.TTL.get(resource)
+}
+
+type AlertmanagerConfig struct {
+	URL string `yaml:"url"` // 集群未配置 Alertmanager 地址时使用的默认地址
+}
+
+// ReservationConfig 已通过的资源预约申请的生命周期管理: 生效, 到期释放及到期提醒.
+type ReservationConfig struct {
+	ScanInterval time.Duration `yaml:"scan_interval"` // 扫描已通过申请的间隔, 0 表示不启用
+	NotifyBefore time.Duration `yaml:"notify_before"` // 到期前该时间通过 Alertmanager 提醒申请人, 0 表示不提醒
+}
+
+type IPMIConfig struct {
+	User         string `yaml:"user"`          // 集群未配置 BMC 账号时使用的默认用户名
+	Password     string `yaml:"password"`      // 默认密码
+	PasswordFile string `yaml:"password_file"` // 从文件读取默认密码, 优先于 password
+}
+
+type AuthConfig struct {
+	Enabled         bool          `yaml:"enabled"`           // 是否启用认证, 关闭后所有接口无需登录(仅用于测试)
+	Secret          string        `yaml:"secret"`            // 令牌签名密钥, 至少 32 字节
+	SecretFile      string        `yaml:"secret_file"`       // 从文件读取签名密钥, 优先于 secret
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`  // 访问令牌有效期
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"` // 刷新令牌有效期
+	AdminGroups     []string      `yaml:"admin_groups"`      // 该 Ldap 组的成员在所有集群下均为管理员, 其余角色按集群在数据库中配置
+
+	TrustedProxy TrustedProxyConfig `yaml:"trusted_proxy"`
+}
+
+// TrustedProxyConfig 受信任的反向代理. 来自 CIDRs 的请求可通过请求头传递已认证的用户名及 Ldap 组.
+type TrustedProxyConfig struct {
+	CIDRs        []string `yaml:"cidrs"`         // 受信任代理网段, 为空时不启用
+	UserHeader   string   `yaml:"user_header"`   // 用户名请求头
+	GroupsHeader string   `yaml:"groups_header"` // Ldap 组请求头, 多个组以逗号分隔
+}
+
+// Default 返回默认配置.
+func Default() *Config {
+	return &Config{
+		Log: LogConfig{
+			Level:  "info",
+			Output: "stderr",
+			Format: "text",
+		},
+		Server: ServerConfig{
+			ListenAddr:       ":8081",
+			ShutdownTimeout:  10 * time.Second,
+			ReadinessTimeout: 2 * time.Second,
+		},
+		Database: DatabaseConfig{
+			AutoMigrate: true,
+		},
+		Slurmrest: SlurmrestConfig{
+			Timeout: 5 * time.Second,
+		},
+		Upstream: UpstreamConfig{
+			Retries:          2,
+			RetryBackoff:     200 * time.Millisecond,
+			RetryMaxBackoff:  2 * time.Second,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+		},
+		Cache: CacheConfig{
+			Enabled: true,
+			TTL: CacheTTL{
+				Nodes:      10 * time.Second,
+				Partitions: time.Minute,
+				QoS:        5 * time.Minute,
+				Accounts:   time.Minute,
+				Tres:       10 * time.Minute,
+				Jobs:       5 * time.Second,
+			},
+		},
+		Reservation: ReservationConfig{
+			ScanInterval: time.Minute,
+			NotifyBefore: time.Hour,
+		},
+		Auth: AuthConfig{
+			Enabled:         true,
+			AccessTokenTTL:  time.Hour,
+			RefreshTokenTTL: 24 * time.Hour,
+			TrustedProxy: TrustedProxyConfig{
+				UserHeader:   "X-Forwarded-User",
+				GroupsHeader: "X-Forwarded-Groups",
+			},
+		},
+	}
+}
+
+// Load 以默认配置为基础, 依次应用配置文件(path 为空时跳过), 环境变量及 *_file 中的敏感信息.
+// Load 不做校验, 调用方在合并命令行参数后应调用 Validate.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read config file: %w", err)
+		}
+		if err := decode(path, b, cfg); err != nil {
+			return nil, fmt.Errorf("unable to parse config file(%s): %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.loadSecrets(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decode 按扩展名解析配置文件(path)的内容: .toml 为 TOML, 其余为 YAML.
+// TOML 先转换为 YAML 再解码, 因此两种格式的键名(yaml 标签), 时长格式(如 10s)及未知键检查均一致.
+func decode(path string, b []byte, cfg *Config) error {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		var m map[string]any
+		if err := toml.Unmarshal(b, &m); err != nil {
+			return err
+		}
+		var err error
+		if b, err = yaml.Marshal(m); err != nil {
+			return err
+		}
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// applyEnv 使用环境变量覆盖配置.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	strs := map[string]*string{
+		"LOG_LEVEL":          &c.Log.Level,
+		"LOG_OUTPUT":         &c.Log.Output,
+		"LOG_FORMAT":         &c.Log.Format,
+		"LOG_FILE":           &c.Log.File,
+		"SERVER_LISTEN_ADDR": &c.Server.ListenAddr,
+		"DATABASE_DSN":       &c.Database.DSN,
+		"DATABASE_DSN_FILE":  &c.Database.DSNFile,
+		"ALERTMANAGER_URL":   &c.Alertmanager.URL,
+		"IPMI_USER":          &c.IPMI.User,
+		"IPMI_PASSWORD":      &c.IPMI.Password,
+		"IPMI_PASSWORD_FILE": &c.IPMI.PasswordFile,
+		"AUTH_SECRET":        &c.Auth.Secret,
+		"AUTH_SECRET_FILE":   &c.Auth.SecretFile,
+
+		"AUTH_TRUSTED_PROXY_USER_HEADER":   &c.Auth.TrustedProxy.UserHeader,
+		"AUTH_TRUSTED_PROXY_GROUPS_HEADER": &c.Auth.TrustedProxy.GroupsHeader,
+	}
+	for k, p := range strs {
+		if v, ok := lookup(EnvPrefix + k); ok {
+			*p = v
+		}
+	}
+
+	bools := map[string]*bool{
+		"DATABASE_AUTO_MIGRATE": &c.Database.AutoMigrate,
+		"CACHE_ENABLED":         &c.Cache.Enabled,
+		"AUTH_ENABLED":          &c.Auth.Enabled,
+	}
+	for k, p := range bools {
+		if v, ok := lookup(EnvPrefix + k); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %w", EnvPrefix, k, err)
+			}
+			*p = b
+		}
+	}
+
+	ints := map[string]*int{
+		"UPSTREAM_RETRIES":           &c.Upstream.Retries,
+		"UPSTREAM_BREAKER_THRESHOLD": &c.Upstream.BreakerThreshold,
+	}
+	for k, p := range ints {
+		if v, ok := lookup(EnvPrefix + k); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %w", EnvPrefix, k, err)
+			}
+			*p = n
+		}
+	}
+
+	// 列表以逗号分隔
+	lists := map[string]*[]string{
+		"AUTH_ADMIN_GROUPS":        &c.Auth.AdminGroups,
+		"AUTH_TRUSTED_PROXY_CIDRS": &c.Auth.TrustedProxy.CIDRs,
+	}
+	for k, p := range lists {
+		if v, ok := lookup(EnvPrefix + k); ok {
+			*p = nil
+			for _, item := range strings.Split(v, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					*p = append(*p, item)
+				}
+			}
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"SERVER_SHUTDOWN_TIMEOUT":    &c.Server.ShutdownTimeout,
+		"SERVER_READINESS_TIMEOUT":   &c.Server.ReadinessTimeout,
+		"AUTH_ACCESS_TOKEN_TTL":      &c.Auth.AccessTokenTTL,
+		"AUTH_REFRESH_TOKEN_TTL":     &c.Auth.RefreshTokenTTL,
+		"SLURMREST_TIMEOUT":          &c.Slurmrest.Timeout,
+		"UPSTREAM_RETRY_BACKOFF":     &c.Upstream.RetryBackoff,
+		"UPSTREAM_RETRY_MAX_BACKOFF": &c.Upstream.RetryMaxBackoff,
+		"UPSTREAM_BREAKER_COOLDOWN":  &c.Upstream.BreakerCooldown,
+		"CACHE_TTL_NODES":            &c.Cache.TTL.Nodes,
+		"CACHE_TTL_PARTITIONS":       &c.Cache.TTL.Partitions,
+		"CACHE_TTL_QOS":              &c.Cache.TTL.QoS,
+		"CACHE_TTL_ACCOUNTS":         &c.Cache.TTL.Accounts,
+		"CACHE_TTL_TRES":             &c.Cache.TTL.Tres,
+		"CACHE_TTL_JOBS":             &c.Cache.TTL.Jobs,
+		"RESERVATION_SCAN_INTERVAL":  &c.Reservation.ScanInterval,
+		"RESERVATION_NOTIFY_BEFORE":  &c.Reservation.NotifyBefore,
+	}
+	for k, p := range durations {
+		if v, ok := lookup(EnvPrefix + k); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %w", EnvPrefix, k, err)
+			}
+			*p = d
+		}
+	}
+	return nil
+}
+
+// loadSecrets 从 *_file 指定的文件中读取敏感信息.
+func (c *Config) loadSecrets() error {
+	read := func(path string, dst *string) error {
+		if path == "" {
+			return nil
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("unable to read secret file: %w", err)
+		}
+		*dst = strings.TrimSpace(string(b))
+		return nil
+	}
+	if err := read(c.Database.DSNFile, &c.Database.DSN); err != nil {
+		return err
+	}
+	if err := read(c.IPMI.PasswordFile, &c.IPMI.Password); err != nil {
+		return err
+	}
+	return read(c.Auth.SecretFile, &c.Auth.Secret)
+}
+
+// Validate 校验配置.
+func (c *Config) Validate() error {
+	var errs []error
+	oneOf := func(name, v string, allowed ...string) {
+		for _, a := range allowed {
+			if v == a {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s must be one of [%s], got %q", name, strings.Join(allowed, ", "), v))
+	}
+	oneOf("log.level", c.Log.Level, "debug", "info", "warn", "error")
+	oneOf("log.output", c.Log.Output, "stdout", "stderr", "file")
+	oneOf("log.format", c.Log.Format, "json", "text")
+	if c.Log.Output == "file" && strings.TrimSpace(c.Log.File) == "" {
+		errs = append(errs, errors.New("log.file is required when log.output is file"))
+	}
+	if strings.TrimSpace(c.Server.ListenAddr) == "" {
+		errs = append(errs, errors.New("server.listen_addr is required"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout must be positive"))
+	}
+	if c.Server.ReadinessTimeout <= 0 {
+		errs = append(errs, errors.New("server.readiness_timeout must be positive"))
+	}
+	if strings.TrimSpace(c.Database.DSN) == "" {
+		errs = append(errs, errors.New("database.dsn (or database.dsn_file) is required"))
+	}
+	if c.Slurmrest.Timeout <= 0 {
+		errs = append(errs, errors.New("slurmrest.timeout must be positive"))
+	}
+	if c.Upstream.Retries < 0 {
+		errs = append(errs, errors.New("upstream.retries must not be negative"))
+	}
+	if c.Upstream.Retries > 0 && (c.Upstream.RetryBackoff <= 0 || c.Upstream.RetryMaxBackoff < c.Upstream.RetryBackoff) {
+		errs = append(errs, errors.New("upstream.retry_backoff must be positive and not greater than upstream.retry_max_backoff"))
+	}
+	if c.Upstream.BreakerThreshold < 0 {
+		errs = append(errs, errors.New("upstream.breaker_threshold must not be negative"))
+	}
+	if c.Upstream.BreakerThreshold > 0 && c.Upstream.BreakerCooldown <= 0 {
+		errs = append(errs, errors.New("upstream.breaker_cooldown must be positive when circuit breaking is enabled"))
+	}
+	for name := range c.Cache.Clusters {
+		if strings.TrimSpace(name) == "" {
+			errs = append(errs, errors.New("cache.clusters contains empty cluster name"))
+		}
+	}
+	if c.Alertmanager.URL != "" {
+		if u, err := url.Parse(c.Alertmanager.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("alertmanager.url is not a valid url: %q", c.Alertmanager.URL))
+		}
+	}
+	if c.Reservation.ScanInterval < 0 || c.Reservation.NotifyBefore < 0 {
+		errs = append(errs, errors.New("reservation.scan_interval and reservation.notify_before must not be negative"))
+	}
+	if c.Auth.Enabled {
+		if len(c.Auth.Secret) < 32 {
+			errs = append(errs, errors.New("auth.secret (or auth.secret_file) must be at least 32 bytes when auth is enabled"))
+		}
+		if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+			errs = append(errs, errors.New("auth.access_token_ttl and auth.refresh_token_ttl must be positive"))
+		}
+		if c.Auth.RefreshTokenTTL < c.Auth.AccessTokenTTL {
+			errs = append(errs, errors.New("auth.refresh_token_ttl must not be shorter than auth.access_token_ttl"))
+		}
+		if len(c.Auth.TrustedProxy.CIDRs) > 0 && strings.TrimSpace(c.Auth.TrustedProxy.UserHeader) == "" {
+			errs = append(errs, errors.New("auth.trusted_proxy.user_header is required when auth.trusted_proxy.cidrs is set"))
+		}
+		for _, cidr := range c.Auth.TrustedProxy.CIDRs {
+			if _, err := netip.ParsePrefix(cidr); err != nil {
+				if _, err := netip.ParseAddr(cidr); err != nil {
+					errs = append(errs, fmt.Errorf("auth.trusted_proxy.cidrs contains invalid cidr or address: %q", cidr))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
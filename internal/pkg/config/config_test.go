@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFormats(t *testing.T) {
+	yamlPath := writeFile(t, "config.yml", `
+server:
+  listen_addr: ":9000"
+  shutdown_timeout: 30s
+database:
+  dsn: postgres://localhost/csjk
+  auto_migrate: false
+cache:
+  clusters:
+    hpc1:
+      nodes: 1m
+auth:
+  admin_groups: [hpcadmin, ops]
+`)
+	tomlPath := writeFile(t, "config.toml", `
+[server]
+listen_addr = ":9000"
+shutdown_timeout = "30s"
+
+[database]
+dsn = "postgres://localhost/csjk"
+auto_migrate = false
+
+[cache.clusters.hpc1]
+nodes = "1m"
+
+[auth]
+admin_groups = ["hpcadmin", "ops"]
+`)
+
+	fromYAML, err := Load(yamlPath)
+	if err != nil {
+		t.Fatalf("Load(yaml) error: %v", err)
+	}
+	fromTOML, err := Load(tomlPath)
+	if err != nil {
+		t.Fatalf("Load(toml) error: %v", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromTOML) {
+		t.Errorf("Load(toml) = %+v, want %+v", fromTOML, fromYAML)
+	}
+	if fromTOML.Server.ListenAddr != ":9000" || fromTOML.Server.ShutdownTimeout != 30*time.Second || fromTOML.Database.AutoMigrate {
+		t.Errorf("Load(toml) server/database = %+v, %+v", fromTOML.Server, fromTOML.Database)
+	}
+	if got := fromTOML.Cache.TTLFor("hpc1", "nodes"); got != time.Minute {
+		t.Errorf("TTLFor(hpc1, nodes) = %v, want 1m", got)
+	}
+	if fromTOML.Slurmrest.Timeout != Default().Slurmrest.Timeout {
+		t.Errorf("unset keys must keep defaults, got slurmrest timeout %v", fromTOML.Slurmrest.Timeout)
+	}
+}
+
+func TestLoadEmptyFile(t *testing.T) {
+	for _, name := range []string{"config.yml", "config.toml"} {
+		cfg, err := Load(writeFile(t, name, ""))
+		if err != nil {
+			t.Errorf("Load(empty %s) error: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(cfg, Default()) {
+			t.Errorf("Load(empty %s) = %+v, want defaults", name, cfg)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown.yml":   "server:\n  listen: \":9000\"\n",
+		"unknown.toml":  "[server]\nlisten = \":9000\"\n",
+		"syntax.toml":   "[server\n",
+		"duration.toml": "[server]\nshutdown_timeout = 30\n",
+	}
+	for name, content := range tests {
+		if _, err := Load(writeFile(t, name, content)); err == nil {
+			t.Errorf("Load(%s) error = nil, want error", name)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package metrics
+
+// ObserveCache 记录集群(cluster)资源(resource)的一次缓存查询, result 为 hit, miss 或 shared(见 cache.RESULT_HIT 等).
+func ObserveCache(resource, cluster, result string) {
+	cacheRequestsTotal.WithLabelValues(resource, cluster, result).Inc()
+}
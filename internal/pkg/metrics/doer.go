@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"csjk-bk/internal/pkg/middleware"
+)
+
+// Doer 与各客户端包中的 Doer 接口一致.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type instrumentedDoer struct {
+	upstream string
+	next     Doer
+}
+
+// InstrumentDoer 包装 next, 统计发往 upstream 的请求数量及耗时. 集群名称取自请求 context
+// (见 middleware.ResolveCluster), 不存在时为空.
+func InstrumentDoer(upstream string, next Doer) Doer {
+	return &instrumentedDoer{upstream: upstream, next: next}
+}
+
+func (d *instrumentedDoer) Do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := d.next.Do(req)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	cluster := middleware.ClusterName(req.Context())
+	upstreamRequestsTotal.WithLabelValues(d.upstream, cluster, req.Method, status).Inc()
+	upstreamRequestDuration.WithLabelValues(d.upstream, cluster, req.Method, status).Observe(time.Since(start).Seconds())
+	return resp, err
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// ObserveCommand 记录外部命令 operation 的耗时及退出码. err 为 cmd.Run 等方法返回的错误.
+func ObserveCommand(operation string, d time.Duration, err error) {
+	code := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		} else {
+			code = -1
+		}
+	}
+	execDuration.WithLabelValues(operation).Observe(d.Seconds())
+	execExitCodes.WithLabelValues(operation, strconv.Itoa(code)).Inc()
+}
@@ -0,0 +1,89 @@
+// Package metrics 提供 Prometheus 指标: HTTP 接口, 上游服务(slurmrestd, lustre, alertmanager),
+// 数据库连接池, 外部命令执行情况及响应缓存命中情况.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "csjk"
+
+// Registry 本服务使用的指标注册表.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by route template, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	upstreamRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "requests_total",
+		Help:      "Total number of outbound requests by upstream, cluster, method and status code (\"error\" on transport failure).",
+	}, []string{"upstream", "cluster", "method", "status"})
+
+	upstreamRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "upstream",
+		Name:      "request_duration_seconds",
+		Help:      "Outbound request latency by upstream, cluster, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"upstream", "cluster", "method", "status"})
+
+	execDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "exec",
+		Name:      "command_duration_seconds",
+		Help:      "External command execution latency by operation.",
+		Buckets:   []float64{.1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"operation"})
+
+	execExitCodes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "exec",
+		Name:      "command_exit_codes_total",
+		Help:      "External command executions by operation and exit code (-1 when the command could not be started).",
+	}, []string{"operation", "code"})
+
+	cacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Total number of response cache lookups by resource, cluster and result (hit, miss, shared).",
+	}, []string{"resource", "cluster", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		upstreamRequestsTotal,
+		upstreamRequestDuration,
+		execDuration,
+		execExitCodes,
+		cacheRequestsTotal,
+	)
+}
+
+// Handler 返回 /metrics 的 HTTP handler.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
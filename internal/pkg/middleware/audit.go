@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/client/postgres/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxAuditBody 审计日志记录的请求体上限, 超出时不记录请求体内容.
+const maxAuditBody = 64 << 10
+
+// 请求体中名称(忽略大小写)包含以下内容的字段在审计日志中脱敏, 如 password, userPassword, bmc_password, refresh_token.
+var redactedKeys = []string{"passw", "secret", "token"}
+
+const redacted = "******"
+
+// Audit 将每个修改类(POST/PUT/PATCH/DELETE)请求写入审计日志: 调用者, 集群, 路由, 操作对象, 脱敏后的请求体, 结果及耗时.
+// 应在 Authenticate 之前使用, 以便记录认证或鉴权失败的请求; 调用者及集群在请求处理完成后读取.
+// 写入失败仅记录日志, 不影响请求.
+func Audit(db *postgres.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+		// 未匹配路由的请求不会修改任何数据, 不记录
+		if c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		body := auditBody(c.Request)
+		c.Next()
+
+		status := c.Writer.Status()
+		entry := model.AuditLog{
+			CreatedAt:  start,
+			Actor:      Username(c),
+			Cluster:    GetCluster(c).Name,
+			Method:     c.Request.Method,
+			Route:      c.FullPath(),
+			Path:       c.Request.URL.Path,
+			Target:     auditTarget(c.Params),
+			Body:       body,
+			Status:     status,
+			Outcome:    model.AUDIT_OUTCOME_SUCCESS,
+			DurationMS: time.Since(start).Milliseconds(),
+			ClientIP:   c.ClientIP(),
+		}
+		if entry.Cluster == "" {
+			entry.Cluster = firstNonEmpty(c.Param("cluster"), c.Query("cluster"))
+		}
+		if status >= http.StatusBadRequest {
+			entry.Outcome = model.AUDIT_OUTCOME_FAILURE
+		}
+
+		// 请求可能已被客户端取消, 使用独立的超时
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+		defer cancel()
+		if err := db.AddAuditLog(ctx, entry); err != nil {
+			Logger(c).Error("unable to write audit log", slog.String("route", entry.Route), slog.String("actor", entry.Actor), slog.Any("err", err))
+		}
+	}
+}
+
+// auditBody 读取请求体并返回脱敏后的内容, 同时保证后续 handler 仍可读取完整请求体.
+func auditBody(r *http.Request) string {
+	if r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+	buf, err := io.ReadAll(io.LimitReader(r.Body, maxAuditBody+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+	if err != nil {
+		return "<unreadable body>"
+	}
+	if len(buf) > maxAuditBody {
+		return "<body too large>"
+	}
+	return redactBody(buf)
+}
+
+// redactBody 将 JSON 请求体中的敏感字段替换为 ******. 非 JSON 请求体无法脱敏, 不记录其内容.
+func redactBody(b []byte) string {
+	if len(bytes.TrimSpace(b)) == 0 {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return "<non-json body omitted>"
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return "<non-json body omitted>"
+	}
+	return string(out)
+}
+
+func redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if isSensitiveKey(k) {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactValue(val)
+		}
+	case []any:
+		for i := range t {
+			t[i] = redactValue(t[i])
+		}
+	}
+	return v
+}
+
+func isSensitiveKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range redactedKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditTarget 返回除 cluster 外的路径参数, 如 name=foo 或 id=3.
+func auditTarget(params gin.Params) string {
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		if p.Key == "cluster" {
+			continue
+		}
+		parts = append(parts, p.Key+"="+p.Value)
+	}
+	return strings.Join(parts, ",")
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const identityKey = "csjk.identity"
+
+// Authenticate 校验 Authorization: Bearer <access token>, 并将身份保存在请求上下文中, 供 handler 通过
+// GetIdentity/Username 读取. proxy 不为空且请求来自受信任代理时, 优先使用代理请求头中的身份.
+// 路径等于 public 中某项, 或 public 中以 "/" 结尾的项为其前缀时, 不做校验.
+func Authenticate(issuer *auth.Issuer, proxy *auth.TrustedProxy, public ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isPublic(c.Request.URL.Path, public) {
+			c.Next()
+			return
+		}
+		if id, ok := proxy.Identity(c.Request); ok {
+			c.Set(identityKey, id)
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{Code: string(apperror.CODE_UNAUTHENTICATED), Detail: "missing bearer token"})
+			return
+		}
+		id, err := issuer.Parse(strings.TrimSpace(token), auth.TOKEN_TYPE_ACCESS)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, response.Response{Code: string(apperror.CODE_UNAUTHENTICATED), Detail: "invalid or expired token"})
+			return
+		}
+		c.Set(identityKey, id)
+		c.Next()
+	}
+}
+
+func isPublic(path string, public []string) bool {
+	for _, p := range public {
+		if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetIdentity 返回 Authenticate 解析出的调用者身份, 第二个返回值表示是否已认证.
+func GetIdentity(c *gin.Context) (auth.Identity, bool) {
+	v, ok := c.Get(identityKey)
+	if !ok {
+		return auth.Identity{}, false
+	}
+	id, ok := v.(auth.Identity)
+	return id, ok
+}
+
+// Username 返回调用者用户名, 未认证(如未启用认证)时返回空字符串.
+func Username(c *gin.Context) string {
+	id, _ := GetIdentity(c)
+	return id.Username
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/client/postgres"
+	"csjk-bk/internal/pkg/client/postgres/model"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const clusterKey = "csjk.cluster"
+
+type clusterNameKey struct{}
+
+// ResolveCluster 根据路径参数 :cluster (不存在时使用查询参数 cluster) 从数据库中查找集群,
+// 并将其保存在请求上下文中, 供后续 handler 通过 GetCluster 读取.
+// 缺少集群名称时返回 400, 集群未注册时返回 404.
+func ResolveCluster(db *postgres.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("cluster")
+		if name == "" {
+			name = c.Query("cluster")
+		}
+		if name == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Response{Code: string(apperror.CODE_VALIDATION_FAILED), Detail: "missing cluster"})
+			return
+		}
+		resolve(c, db, name)
+	}
+}
+
+// ResolveClusterOptional 与 ResolveCluster 相同, 但未指定集群时直接放行.
+func ResolveClusterOptional(db *postgres.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("cluster")
+		if name == "" {
+			name = c.Query("cluster")
+		}
+		if name == "" {
+			c.Next()
+			return
+		}
+		resolve(c, db, name)
+	}
+}
+
+func resolve(c *gin.Context, db *postgres.Client, name string) {
+	cl, err := db.GetCluster(c.Request.Context(), name)
+	if err != nil {
+		if errors.Is(err, postgres.ErrClusterNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, response.Response{Code: string(apperror.CODE_CLUSTER_NOT_FOUND), Detail: "unknown cluster: " + name})
+			return
+		}
+		c.AbortWithStatusJSON(apperror.Render(err, "failed to resolve cluster"))
+		return
+	}
+	c.Set(clusterKey, cl)
+	// 同时写入 request context, 供下游客户端(如指标统计)获取集群名称
+	c.Request = c.Request.WithContext(WithClusterName(c.Request.Context(), cl.Name))
+	c.Next()
+}
+
+// GetCluster 返回 ResolveCluster 解析出的集群, 未解析时返回零值.
+func GetCluster(c *gin.Context) model.Cluster {
+	v, ok := c.Get(clusterKey)
+	if !ok {
+		return model.Cluster{}
+	}
+	cl, _ := v.(model.Cluster)
+	return cl
+}
+
+// WithClusterName 返回携带集群名称的 ctx, 用于不经过 ResolveCluster 的调用(如就绪检查).
+func WithClusterName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, clusterNameKey{}, name)
+}
+
+// ClusterName 返回 ctx 中由 ResolveCluster 写入的集群名称, 不存在时返回空字符串.
+func ClusterName(ctx context.Context) string {
+	name, _ := ctx.Value(clusterNameKey{}).(string)
+	return name
+}
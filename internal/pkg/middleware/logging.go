@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求 ID 请求头, 入站请求中已有时沿用, 并随响应及发往上游服务的请求一同返回/转发.
+const RequestIDHeader = "X-Request-ID"
+
+const loggerKey = "csjk.logger"
+
+type requestIDKey struct{}
+
+// AccessLog 为每个请求分配(或沿用客户端传入的) X-Request-ID, 并在 gin 上下文中保存携带请求 ID 及路由的
+// 子 logger, handler 通过 Logger 获取. 请求结束后输出一条访问日志. 应作为第一个业务中间件使用.
+func AccessLog(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		c.Set(loggerKey, logger.With(slog.String("request_id", id), slog.String("route", route)))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Int("size", c.Writer.Size()),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if errs := c.Errors.String(); errs != "" {
+			attrs = append(attrs, slog.String("errors", errs))
+		}
+		Logger(c).LogAttrs(c.Request.Context(), level, "access", attrs...)
+	}
+}
+
+// Logger 返回当前请求的 logger, 携带请求 ID, 路由, 以及已解析的集群和调用者.
+// 未经过 AccessLog 时返回 slog.Default().
+func Logger(c *gin.Context) *slog.Logger {
+	v, ok := c.Get(loggerKey)
+	if !ok {
+		return slog.Default()
+	}
+	l := v.(*slog.Logger)
+	if cluster := GetCluster(c).Name; cluster != "" {
+		l = l.With(slog.String("cluster", cluster))
+	}
+	if user := Username(c); user != "" {
+		l = l.With(slog.String("user", user))
+	}
+	return l
+}
+
+// WithRequestID 返回携带请求 ID 的 ctx.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 返回 ctx 中的请求 ID, 不存在时返回空字符串.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Doer 与各客户端包中的 Doer 接口一致.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type requestIDDoer struct {
+	next Doer
+}
+
+// ForwardRequestID 包装 next, 将请求 context 中的请求 ID 通过 X-Request-ID 请求头转发给上游服务
+// (slurmrestd, KD Lustre, Alertmanager), 以便端到端追踪.
+func ForwardRequestID(next Doer) Doer {
+	return &requestIDDoer{next: next}
+}
+
+func (d *requestIDDoer) Do(req *http.Request) (*http.Response, error) {
+	if id := RequestID(req.Context()); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, id)
+	}
+	return d.next.Do(req)
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID 仅接受长度不超过 128 的可打印 ASCII(不含空格), 避免日志注入.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
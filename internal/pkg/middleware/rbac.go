@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"csjk-bk/internal/pkg/apperror"
+	"csjk-bk/internal/pkg/auth"
+	"csjk-bk/internal/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizerKey = "csjk.authorizer"
+	rolesKey      = "csjk.roles"
+)
+
+// ErrClusterMismatch 令牌签发的集群与请求的集群不一致.
+var ErrClusterMismatch = apperror.New(apperror.CODE_PERMISSION_DENIED, "permission denied: token was issued for another cluster")
+
+// Authorize 将 az 保存在请求上下文中, 供 RequireRole/HasRole 解析调用者角色. 需在 Authenticate 之后使用.
+// 未使用该中间件(如未启用认证)时, RequireRole 与 HasRole 不做限制.
+func Authorize(az *auth.Authorizer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(authorizerKey, az)
+		c.Next()
+	}
+}
+
+// RequireRole 要求调用者在当前集群(由 ResolveCluster 解析, 未解析时仅判断全局管理员)下拥有 roles 中任一角色,
+// 否则返回 403; 令牌签发的集群与当前集群不一致时同样返回 403. 应放在 ResolveCluster 之后.
+func RequireRole(roles ...auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rs, ok, err := callerRoles(c)
+		if err != nil {
+			c.AbortWithStatusJSON(apperror.Render(err, "failed to resolve roles"))
+			return
+		}
+		if ok && !rs.Has(roles...) {
+			c.AbortWithStatusJSON(http.StatusForbidden, response.Response{Code: string(apperror.CODE_PERMISSION_DENIED), Detail: "permission denied: requires role " + joinRoles(roles)})
+			return
+		}
+		c.Next()
+	}
+}
+
+// HasRole 判断调用者在当前集群下是否拥有 roles 中任一角色, 用于 handler 内按角色区分数据范围.
+// 未启用鉴权时返回 true, 角色解析失败时返回 false.
+func HasRole(c *gin.Context, roles ...auth.Role) bool {
+	rs, ok, err := callerRoles(c)
+	if err != nil {
+		return false
+	}
+	return !ok || rs.Has(roles...)
+}
+
+// callerRoles 解析并缓存调用者角色, 第二个返回值为 false 表示未启用鉴权.
+func callerRoles(c *gin.Context) (auth.Roles, bool, error) {
+	v, ok := c.Get(authorizerKey)
+	if !ok {
+		return nil, false, nil
+	}
+	if rs, ok := c.Get(rolesKey); ok {
+		return rs.(auth.Roles), true, nil
+	}
+	id, ok := GetIdentity(c)
+	if !ok {
+		return auth.Roles{}, true, nil
+	}
+	// 令牌中的附加组来自登录集群的 Ldap, 不能用于其他集群的角色映射; 受信任代理的身份不属于某个集群
+	cluster := GetCluster(c).Name
+	if id.Cluster != "" && cluster != "" && id.Cluster != cluster {
+		return nil, true, ErrClusterMismatch
+	}
+	rs, err := v.(*auth.Authorizer).Roles(c.Request.Context(), id, cluster)
+	if err != nil {
+		return nil, true, err
+	}
+	c.Set(rolesKey, rs)
+	return rs, true, nil
+}
+
+func joinRoles(roles []auth.Role) string {
+	s := make([]string, 0, len(roles))
+	for _, r := range roles {
+		s = append(s, string(r))
+	}
+	return strings.Join(s, "|")
+}
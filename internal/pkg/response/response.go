@@ -12,6 +12,7 @@ type Response struct {
 	Next     url.URL     `json:"next" swaggertype:"string"`
 	Results  interface{} `json:"results"`
 	Detail   string      `json:"detail"`
+	Code     string      `json:"code"` // 错误码(见 apperror), 成功时为空
 }
 
 // MarshalJSON renders Previous and Next as URL strings instead of struct fields.
@@ -22,6 +23,7 @@ func (r Response) MarshalJSON() ([]byte, error) {
 		Next     string      `json:"next"`
 		Results  interface{} `json:"results"`
 		Detail   string      `json:"detail"`
+		Code     string      `json:"code"`
 	}
 
 	prevStr := r.Previous.String()
@@ -32,6 +34,7 @@ func (r Response) MarshalJSON() ([]byte, error) {
 		Next:     nextStr,
 		Results:  r.Results,
 		Detail:   r.Detail,
+		Code:     r.Code,
 	}
 	return json.Marshal(a)
 }